@@ -37,6 +37,9 @@ const TWO_SPACES = "  "
 const emptyDefaultFlagValue = ""
 const currentDirDefaultFlagValue = "."
 
+// overwriteFlagUsage describes the --overwrite flag of the commands generating templates
+const overwriteFlagUsage = "what to do with existing files that would be changed (fail, skip or force)"
+
 const DOCKERFILES_DIR = "dockerfiles"
 
 func listSupportedLanguages() ([]string, error) {
@@ -62,6 +65,11 @@ type createCmd struct {
 	deploymentOnly    bool
 	skipFileDetection bool
 	flagVariables     []string
+	overwrite         string
+	// overwritePolicy is parsed from overwrite and applies to every generated template
+	overwritePolicy handlers.OverwritePolicy
+	// overwriteDeployment is set once the user agrees to recreate existing deployment files
+	overwriteDeployment bool
 
 	createConfigPath string
 	createConfig     *CreateConfig
@@ -96,6 +104,7 @@ func newCreateCmd() *cobra.Command {
 	f.BoolVar(&cc.deploymentOnly, "deployment-only", false, "only create deployment files in the project directory")
 	f.BoolVar(&cc.skipFileDetection, "skip-file-detection", false, "skip file detection step")
 	f.StringArrayVarP(&cc.flagVariables, "variable", "", []string{}, "pass template variables (e.g. --variable PORT=8080 --variable APPNAME=test)")
+	f.StringVar(&cc.overwrite, "overwrite", handlers.OverwriteFail.String(), overwriteFlagUsage)
 
 	return cmd
 }
@@ -127,6 +136,12 @@ func (cc *createCmd) run() error {
 
 	flagVariablesMap = flagVariablesToMap(cc.flagVariables)
 
+	overwritePolicy, err := handlers.ParseOverwritePolicy(cc.overwrite)
+	if err != nil {
+		return err
+	}
+	cc.overwritePolicy = overwritePolicy
+
 	var dryRunRecorder *dryrunpkg.DryRunRecorder
 	if dryRun {
 		dryRunRecorder = dryrunpkg.NewDryRunRecorder()
@@ -239,7 +254,7 @@ func (cc *createCmd) detectLanguage() (*handlers.Template, string, error) {
 	if cc.createConfig.LanguageType != "" {
 		log.Debug("using configuration language")
 		lowerLang := strings.ToLower(cc.createConfig.LanguageType)
-		langDockerfileTemplate, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", cc.dest, cc.templateWriter)
+		langDockerfileTemplate, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", cc.dest, cc.templateWriter, handlers.WithOverwrite(cc.overwritePolicy))
 		if err != nil {
 			return nil, "", err
 		}
@@ -259,7 +274,7 @@ func (cc *createCmd) detectLanguage() (*handlers.Template, string, error) {
 				log.Debug("detected go and go module")
 				lowerLang = "gomodule"
 			}
-			langDockerfileTemplate, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", cc.dest, cc.templateWriter)
+			langDockerfileTemplate, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", cc.dest, cc.templateWriter, handlers.WithOverwrite(cc.overwritePolicy))
 			if err != nil {
 				return nil, "", err
 			}
@@ -303,7 +318,9 @@ func (cc *createCmd) generateDockerfile(dockerfileTemplate *handlers.Template, l
 	}
 
 	if cc.createConfig.LanguageVariables == nil {
-		dockerfileTemplate.Config.VariableMapToDraftConfig(flagVariablesMap)
+		if err := dockerfileTemplate.Config.VariableMapToDraftConfig(flagVariablesMap); err != nil {
+			return err
+		}
 
 		if err = prompts.RunPromptsFromConfigWithSkips(dockerfileTemplate.Config); err != nil {
 			return err
@@ -317,7 +334,7 @@ func (cc *createCmd) generateDockerfile(dockerfileTemplate *handlers.Template, l
 
 	if cc.templateVariableRecorder != nil {
 		for _, variable := range dockerfileTemplate.Config.Variables {
-			cc.templateVariableRecorder.Record(variable.Name, variable.Value)
+			config.RecordVariable(cc.templateVariableRecorder, variable)
 		}
 	}
 
@@ -337,7 +354,7 @@ func (cc *createCmd) createDeployment() error {
 
 	if cc.createConfig.DeployType != "" {
 		deployType = strings.ToLower(cc.createConfig.DeployType)
-		deployTemplate, err = handlers.GetTemplate(fmt.Sprintf("deployment-%s", deployType), "", cc.dest, cc.templateWriter)
+		deployTemplate, err = handlers.GetTemplate(fmt.Sprintf("deployment-%s", deployType), "", cc.dest, cc.templateWriter, handlers.WithOverwrite(cc.overwritePolicy))
 		if err != nil {
 			return err
 		}
@@ -363,7 +380,7 @@ func (cc *createCmd) createDeployment() error {
 			deployType = cc.deployType
 		}
 
-		deployTemplate, err = handlers.GetTemplate(fmt.Sprintf("deployment-%s", deployType), "", cc.dest, cc.templateWriter)
+		deployTemplate, err = handlers.GetTemplate(fmt.Sprintf("deployment-%s", deployType), "", cc.dest, cc.templateWriter, handlers.WithOverwrite(cc.overwritePolicy))
 		if err != nil {
 			return err
 		}
@@ -372,7 +389,9 @@ func (cc *createCmd) createDeployment() error {
 			return errors.New("invalid deployment type")
 		}
 
-		deployTemplate.Config.VariableMapToDraftConfig(flagVariablesMap)
+		if err := deployTemplate.Config.VariableMapToDraftConfig(flagVariablesMap); err != nil {
+			return err
+		}
 
 		err = prompts.RunPromptsFromConfigWithSkips(deployTemplate.Config)
 		if err != nil {
@@ -382,10 +401,14 @@ func (cc *createCmd) createDeployment() error {
 
 	if cc.templateVariableRecorder != nil {
 		for _, variable := range deployTemplate.Config.Variables {
-			cc.templateVariableRecorder.Record(variable.Name, variable.Value)
+			config.RecordVariable(cc.templateVariableRecorder, variable)
 		}
 	}
 
+	if cc.overwriteDeployment {
+		deployTemplate.Overwrite = handlers.OverwriteForce
+	}
+
 	log.Infof("--> Creating %s Kubernetes resources...\n", deployType)
 	return deployTemplate.Generate()
 }
@@ -432,6 +455,9 @@ func (cc *createCmd) createFiles(detectedLangTempalte *handlers.Template, lowerL
 		}
 
 		hasDockerFile = strings.EqualFold(selectResponse, "no")
+		if !hasDockerFile && detectedLangTempalte != nil {
+			detectedLangTempalte.Overwrite = handlers.OverwriteForce
+		}
 	}
 
 	if cc.deploymentOnly {
@@ -458,6 +484,7 @@ func (cc *createCmd) createFiles(detectedLangTempalte *handlers.Template, lowerL
 		}
 
 		hasDeploymentFiles = strings.EqualFold(selectResponse, "no")
+		cc.overwriteDeployment = !hasDeploymentFiles
 	}
 
 	if cc.dockerfileOnly {
@@ -26,6 +26,8 @@ func TestRun(t *testing.T) {
 		dest:           "./..",
 		createConfig:   &testCreateConfig,
 		templateWriter: &writers.LocalFSWriter{},
+		// the repository root already has a Dockerfile and the test generates it twice
+		overwritePolicy: handlers.OverwriteForce,
 	}
 	deployTypes := []string{"helm", "kustomize", "manifests"}
 	oldDockerfile, _ := ioutil.ReadFile("./../Dockerfile")
@@ -229,7 +231,7 @@ func (mcc *createCmd) mockDetectLanguage() (*handlers.Template, string, error) {
 	if mcc.createConfig.LanguageType != "" {
 		log.Debug("using configuration language")
 		lowerLang := strings.ToLower(mcc.createConfig.LanguageType)
-		langConfig, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", mcc.dest, mcc.templateWriter)
+		langConfig, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", mcc.dest, mcc.templateWriter, handlers.WithOverwrite(mcc.overwritePolicy))
 		if err != nil {
 			return nil, "", err
 		}
@@ -251,7 +253,7 @@ func (mcc *createCmd) mockDetectLanguage() (*handlers.Template, string, error) {
 				lowerLang = "gomodule"
 			}
 
-			langConfig, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", mcc.dest, mcc.templateWriter)
+			langConfig, err := handlers.GetTemplate(fmt.Sprintf("dockerfile-%s", lowerLang), "", mcc.dest, mcc.templateWriter, handlers.WithOverwrite(mcc.overwritePolicy))
 			if err != nil {
 				return nil, "", err
 			}
@@ -19,6 +19,7 @@ type generateWorkflowCmd struct {
 	dest           string
 	deployType     string
 	flagVariables  []string
+	overwrite      string
 	templateWriter templatewriter.TemplateWriter
 }
 
@@ -48,6 +49,7 @@ with draft on AKS. This command assumes the 'setup-gh' command has been run prop
 	f.StringVarP(&gwCmd.dest, "destination", "d", currentDirDefaultFlagValue, "specify the path to the project directory")
 	f.StringVarP(&gwCmd.deployType, "deploy-type", "", "", "specify the k8s deployment type (helm, kustomize, manifests)")
 	f.StringArrayVarP(&gwCmd.flagVariables, "variable", "", []string{}, "pass template variables (e.g. --variable CLUSTERNAME=testCluster --variable DOCKERFILE=./Dockerfile)")
+	f.StringVar(&gwCmd.overwrite, "overwrite", handlers.OverwriteFail.String(), overwriteFlagUsage)
 	gwCmd.templateWriter = &writers.LocalFSWriter{}
 	return cmd
 }
@@ -61,6 +63,11 @@ func (gwc *generateWorkflowCmd) generateWorkflows() error {
 
 	flagVariablesMap = flagVariablesToMap(gwc.flagVariables)
 
+	overwritePolicy, err := handlers.ParseOverwritePolicy(gwc.overwrite)
+	if err != nil {
+		return err
+	}
+
 	if gwc.deployType == "" {
 		selection := &promptui.Select{
 			Label: "Select k8s Deployment Type",
@@ -73,7 +80,7 @@ func (gwc *generateWorkflowCmd) generateWorkflows() error {
 		}
 	}
 
-	t, err := handlers.GetTemplate(fmt.Sprintf("github-workflow-%s", gwc.deployType), "", gwc.dest, gwc.templateWriter)
+	t, err := handlers.GetTemplate(fmt.Sprintf("github-workflow-%s", gwc.deployType), "", gwc.dest, gwc.templateWriter, handlers.WithOverwrite(overwritePolicy))
 	if err != nil {
 		return fmt.Errorf("failed to get template: %e", err)
 	}
@@ -81,7 +88,9 @@ func (gwc *generateWorkflowCmd) generateWorkflows() error {
 		return fmt.Errorf("template is nil")
 	}
 
-	t.Config.VariableMapToDraftConfig(flagVariablesMap)
+	if err := t.Config.VariableMapToDraftConfig(flagVariablesMap); err != nil {
+		return err
+	}
 
 	if err = prompts.RunPromptsFromConfigWithSkips(t.Config); err != nil {
 		return err
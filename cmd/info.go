@@ -60,14 +60,19 @@ func newInfoCmd() *cobra.Command {
 
 func (ic *infoCmd) run() error {
 	log.Debugf("getting supported languages")
-	supportedDockerfileTemplates := handlers.GetTemplatesByType(handlers.TemplateTypeDockerfile)
+	supportedDockerfileTemplates := handlers.GetTemplatesByType(handlers.TemplateTypeDockerfile.String())
 
 	languagesInfo := make([]draftConfigInfo, 0)
 	for _, template := range supportedDockerfileTemplates {
+		draftConfig, err := handlers.GetTemplateConfig(template.Name)
+		if err != nil {
+			return err
+		}
+
 		newConfig := draftConfigInfo{
-			Name:                  template.Config.TemplateName,
-			DisplayName:           template.Config.DisplayName,
-			VariableExampleValues: template.Config.GetVariableExampleValues(),
+			Name:                  template.Name,
+			DisplayName:           template.DisplayName,
+			VariableExampleValues: draftConfig.GetVariableExampleValues(),
 		}
 		languagesInfo = append(languagesInfo, newConfig)
 	}
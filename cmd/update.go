@@ -22,6 +22,7 @@ type updateCmd struct {
 	provider                 string
 	addon                    string
 	flagVariables            []string
+	overwrite                string
 	templateWriter           templatewriter.TemplateWriter
 	templateVariableRecorder config.TemplateVariableRecorder
 }
@@ -49,6 +50,7 @@ func newUpdateCmd() *cobra.Command {
 	f.StringVarP(&uc.provider, "provider", "p", "azure", "cloud provider")
 	f.StringVarP(&uc.addon, "addon", "a", "", "addon name")
 	f.StringArrayVarP(&uc.flagVariables, "variable", "", []string{}, "pass template variables (e.g. --variable ingress-tls-cert-keyvault-uri=test.uri ingress-host=host)")
+	f.StringVar(&uc.overwrite, "overwrite", handlers.OverwriteFail.String(), overwriteFlagUsage)
 
 	uc.templateWriter = &writers.LocalFSWriter{}
 
@@ -58,6 +60,11 @@ func newUpdateCmd() *cobra.Command {
 func (uc *updateCmd) run() error {
 	flagVariablesMap = flagVariablesToMap(uc.flagVariables)
 
+	overwritePolicy, err := handlers.ParseOverwritePolicy(uc.overwrite)
+	if err != nil {
+		return err
+	}
+
 	if dryRun {
 		dryRunRecorder = dryrunpkg.NewDryRunRecorder()
 		uc.templateVariableRecorder = dryRunRecorder
@@ -70,7 +77,7 @@ func (uc *updateCmd) run() error {
 		return err
 	}
 
-	ingressTemplate, err := handlers.GetTemplate("app-routing-ingress", "", updatedDest, uc.templateWriter)
+	ingressTemplate, err := handlers.GetTemplate("app-routing-ingress", "", updatedDest, uc.templateWriter, handlers.WithOverwrite(overwritePolicy))
 	if err != nil {
 		log.Errorf("error getting ingress template: %s", err.Error())
 		return err
@@ -79,7 +86,9 @@ func (uc *updateCmd) run() error {
 		return errors.New("DraftConfig is nil")
 	}
 
-	ingressTemplate.Config.VariableMapToDraftConfig(flagVariablesMap)
+	if err := ingressTemplate.Config.VariableMapToDraftConfig(flagVariablesMap); err != nil {
+		return err
+	}
 
 	err = cmdhelpers.PromptAddonValues(uc.dest, ingressTemplate.Config)
 	if err != nil {
@@ -88,7 +97,7 @@ func (uc *updateCmd) run() error {
 
 	if dryRun {
 		for _, variable := range ingressTemplate.Config.Variables {
-			uc.templateVariableRecorder.Record(variable.Name, variable.Value)
+			config.RecordVariable(uc.templateVariableRecorder, variable)
 		}
 	}
 
@@ -33,7 +33,8 @@ const (
 )
 
 func UpdateProductionDeployments(deployType, dest string, draftConfig *config.DraftConfig, templateWriter templatewriter.TemplateWriter) error {
-	acr, err := draftConfig.GetVariable("AZURECONTAINERREGISTRY")
+	// the value is normalized to the registry name, so a login server such as myacr.azurecr.io can be passed too
+	acr, err := draftConfig.GetVariableValue("AZURECONTAINERREGISTRY")
 	if err != nil {
 		return fmt.Errorf("get variable: %w", err)
 	}
@@ -43,7 +44,7 @@ func UpdateProductionDeployments(deployType, dest string, draftConfig *config.Dr
 		return fmt.Errorf("get variable: %w", err)
 	}
 
-	productionImage := fmt.Sprintf("%s.azurecr.io/%s", acr.Value, containerName.Value)
+	productionImage := fmt.Sprintf("%s.azurecr.io/%s", acr, containerName.Value)
 	switch deployType {
 	case "helm":
 		return setHelmContainerImage(dest+"/charts/production.yaml", productionImage, templateWriter)
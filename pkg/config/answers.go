@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// Answers is the persisted form of the variables resolved for a draft config, used to repeat a run.
+// Field names are part of the answers file format and must stay stable across template versions.
+type Answers struct {
+	// TemplateName is the name of the template the answers were recorded for
+	TemplateName string `yaml:"templateName"`
+	// Version is the template version the answers were recorded for
+	Version string `yaml:"version,omitempty"`
+	// Variables holds an entry for every variable that had a value, in declaration order
+	Variables []AnswerVariable `yaml:"variables"`
+}
+
+// AnswerVariable is a single recorded variable. Sensitive values are never written: they are either
+// marked as sourced from an environment variable or as redacted and must be supplied again.
+type AnswerVariable struct {
+	Name     string `yaml:"name"`
+	Value    string `yaml:"value,omitempty"`
+	EnvVar   string `yaml:"envVar,omitempty"`
+	Redacted bool   `yaml:"redacted,omitempty"`
+}
+
+// WriteAnswers writes the template name, version, and every variable with a value to w as YAML
+func (d *DraftConfig) WriteAnswers(w io.Writer, version string) error {
+	answers := Answers{
+		TemplateName: d.TemplateName,
+		Version:      version,
+		Variables:    []AnswerVariable{},
+	}
+
+	for _, variable := range d.Variables {
+		if variable.Value == "" {
+			continue
+		}
+
+		answer := AnswerVariable{Name: variable.Name}
+		switch {
+		case variable.Sensitive && variable.Default.EnvVar != "":
+			answer.EnvVar = variable.Default.EnvVar
+		case variable.Sensitive:
+			answer.Redacted = true
+		default:
+			answer.Value = variable.Value
+		}
+		answers.Variables = append(answers.Variables, answer)
+	}
+
+	answersBytes, err := yaml.Marshal(answers)
+	if err != nil {
+		return fmt.Errorf("marshalling answers: %w", err)
+	}
+
+	if _, err := w.Write(answersBytes); err != nil {
+		return fmt.Errorf("writing answers: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyAnswers loads an answers file written by WriteAnswers and sets each recorded variable.
+// Env-sourced variables are read from the environment and redacted variables are left for the caller to supply.
+func (d *DraftConfig) ApplyAnswers(fileSys fs.FS, path string) error {
+	answers, err := d.readAnswers(fileSys, path)
+	if err != nil {
+		return err
+	}
+
+	for _, answer := range answers {
+		d.SetVariable(answer.Name, answer.Value)
+	}
+
+	return nil
+}
+
+// readAnswers loads an answers file for d and returns the recorded variables that have a value, reading env-sourced
+// values from the environment and skipping redacted ones
+func (d *DraftConfig) readAnswers(fileSys fs.FS, path string) ([]AnswerVariable, error) {
+	answersBytes, err := fs.ReadFile(fileSys, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading answers file: %w", err)
+	}
+
+	var answers Answers
+	if err := yaml.Unmarshal(answersBytes, &answers); err != nil {
+		return nil, fmt.Errorf("unmarshalling answers file %s: %w", path, err)
+	}
+
+	if answers.TemplateName != "" && answers.TemplateName != d.TemplateName {
+		return nil, fmt.Errorf("answers file %s is for template %s, not %s", path, answers.TemplateName, d.TemplateName)
+	}
+
+	values := make([]AnswerVariable, 0, len(answers.Variables))
+	for _, answer := range answers.Variables {
+		switch {
+		case answer.EnvVar != "":
+			if envValue := os.Getenv(answer.EnvVar); envValue != "" {
+				values = append(values, AnswerVariable{Name: answer.Name, Value: envValue})
+			}
+		case answer.Redacted:
+			log.Debugf("answer for variable %s is redacted, skipping", answer.Name)
+		default:
+			values = append(values, AnswerVariable{Name: answer.Name, Value: answer.Value})
+		}
+	}
+
+	return values, nil
+}
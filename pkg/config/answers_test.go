@@ -0,0 +1,57 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnswersRoundTrip(t *testing.T) {
+	t.Setenv("DRAFT_TEST_REGISTRY_TOKEN", "env-token")
+
+	draftConfig := &DraftConfig{
+		TemplateName: "deployment-manifests",
+		Variables: []*BuilderVar{
+			{Name: "APPNAME", Value: "my-app"},
+			{Name: "PORT", Value: "80"},
+			{Name: "UNSET"},
+			{Name: "TOKEN", Value: "secret-token", Sensitive: true, Default: BuilderVarDefault{EnvVar: "DRAFT_TEST_REGISTRY_TOKEN"}},
+			{Name: "PASSWORD", Value: "secret-password", Sensitive: true},
+		},
+	}
+
+	var answers bytes.Buffer
+	assert.Nil(t, draftConfig.WriteAnswers(&answers, "0.0.1"))
+	assert.Equal(t, `templateName: deployment-manifests
+version: 0.0.1
+variables:
+- name: APPNAME
+  value: my-app
+- name: PORT
+  value: "80"
+- name: TOKEN
+  envVar: DRAFT_TEST_REGISTRY_TOKEN
+- name: PASSWORD
+  redacted: true
+`, answers.String())
+
+	newConfig := draftConfig.DeepCopy()
+	for _, variable := range newConfig.Variables {
+		variable.Value = ""
+	}
+
+	fileSys := fstest.MapFS{"answers.yaml": &fstest.MapFile{Data: answers.Bytes()}}
+	assert.Nil(t, newConfig.ApplyAnswers(fileSys, "answers.yaml"))
+	assert.Equal(t, map[string]string{
+		"APPNAME":  "my-app",
+		"PORT":     "80",
+		"UNSET":    "",
+		"TOKEN":    "env-token",
+		"PASSWORD": "",
+	}, newConfig.GetVariableMap())
+
+	newConfig.TemplateName = "other-template"
+	assert.EqualError(t, newConfig.ApplyAnswers(fileSys, "answers.yaml"), "answers file answers.yaml is for template deployment-manifests, not other-template")
+}
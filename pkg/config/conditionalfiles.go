@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+// FileCondition makes the template files matching a conditionalFiles pattern depend on the value of a variable. Exactly
+// one of Equals and NotEquals is set.
+type FileCondition struct {
+	Variable  string  `yaml:"variable"`
+	Equals    *string `yaml:"equals"`
+	NotEquals *string `yaml:"notEquals"`
+}
+
+// activeWhenConstraint returns the condition as the equivalent ActiveWhenConstraint, so files and variables compare
+// values the same way
+func (c FileCondition) activeWhenConstraint() ActiveWhenConstraint {
+	if c.Equals != nil {
+		return ActiveWhenConstraint{VariableName: c.Variable, Value: *c.Equals, Condition: EqualTo}
+	}
+
+	var value string
+	if c.NotEquals != nil {
+		value = *c.NotEquals
+	}
+	return ActiveWhenConstraint{VariableName: c.Variable, Value: value, Condition: NotEqualTo}
+}
+
+func (c FileCondition) String() string {
+	if c.Equals != nil {
+		return fmt.Sprintf("%s equals %q", c.Variable, *c.Equals)
+	}
+	if c.NotEquals != nil {
+		return fmt.Sprintf("%s notEquals %q", c.Variable, *c.NotEquals)
+	}
+	return c.Variable
+}
+
+// IsFileIncluded checks the conditionalFiles patterns matching file, a slash separated path relative to the template
+// directory. It returns false and the condition that failed if the file must be left out of the generated files. The
+// variables of the checked conditions count as used.
+func (d *DraftConfig) IsFileIncluded(file string) (bool, string, error) {
+	for _, pattern := range d.conditionalFilePatterns() {
+		if matched, _ := path.Match(pattern, file); !matched {
+			continue
+		}
+
+		condition := d.ConditionalFiles[pattern]
+		holds, err := d.constraintHolds(condition.activeWhenConstraint())
+		if err != nil {
+			return false, "", fmt.Errorf("conditionalFiles %s: %w", pattern, err)
+		}
+		if variable, err := d.GetVariable(condition.Variable); err == nil {
+			d.markUsed(variable)
+		}
+		if !holds {
+			return false, fmt.Sprintf("condition %s is not met", condition), nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// checkConditionalFiles checks that every conditionalFiles pattern is valid and has a condition on a known variable
+func (d *DraftConfig) checkConditionalFiles() []error {
+	var errs []error
+	for _, pattern := range d.conditionalFilePatterns() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("conditionalFiles: invalid pattern %q: %w", pattern, err))
+		}
+
+		condition := d.ConditionalFiles[pattern]
+		if condition.Variable == "" {
+			errs = append(errs, fmt.Errorf("conditionalFiles %s: variable is empty", pattern))
+		} else if _, err := d.GetVariable(condition.Variable); err != nil {
+			errs = append(errs, fmt.Errorf("conditionalFiles %s: unknown variable %s", pattern, condition.Variable))
+		}
+
+		if (condition.Equals == nil) == (condition.NotEquals == nil) {
+			errs = append(errs, fmt.Errorf("conditionalFiles %s: exactly one of equals and notEquals must be set", pattern))
+		}
+	}
+
+	return errs
+}
+
+// conditionalFilePatterns returns the conditionalFiles patterns in sorted order
+func (d *DraftConfig) conditionalFilePatterns() []string {
+	patterns := make([]string, 0, len(d.ConditionalFiles))
+	for pattern := range d.ConditionalFiles {
+		patterns = append(patterns, pattern)
+	}
+	slices.Sort(patterns)
+	return patterns
+}
+
+func cloneConditionalFiles(conditionalFiles map[string]FileCondition) map[string]FileCondition {
+	if conditionalFiles == nil {
+		return nil
+	}
+
+	cloned := make(map[string]FileCondition, len(conditionalFiles))
+	for pattern, condition := range conditionalFiles {
+		if condition.Equals != nil {
+			equals := *condition.Equals
+			condition.Equals = &equals
+		}
+		if condition.NotEquals != nil {
+			notEquals := *condition.NotEquals
+			condition.NotEquals = &notEquals
+		}
+		cloned[pattern] = condition
+	}
+	return cloned
+}
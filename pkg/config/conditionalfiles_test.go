@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConditionalFiles(t *testing.T) {
+	enabled := "true"
+	draftConfig := &DraftConfig{
+		TemplateName: "conditional",
+		Variables:    []*BuilderVar{{Name: "USEINGRESS", Type: "bool"}},
+		ConditionalFiles: map[string]FileCondition{
+			"ingress.yaml":  {Variable: "USEINGRESS", Equals: &enabled},
+			"hpa[.yaml":     {Variable: "USEINGRESS", NotEquals: &enabled},
+			"secret.yaml":   {Variable: "USESECRET", Equals: &enabled},
+			"pdb.yaml":      {Equals: &enabled},
+			"network*.yaml": {Variable: "USEINGRESS"},
+			"both.yaml":     {Variable: "USEINGRESS", Equals: &enabled, NotEquals: &enabled},
+		},
+	}
+
+	err := draftConfig.Validate()
+	assert.NotNil(t, err)
+	for _, wantErr := range []string{
+		"conditionalFiles: invalid pattern \"hpa[.yaml\": syntax error in pattern",
+		"conditionalFiles secret.yaml: unknown variable USESECRET",
+		"conditionalFiles pdb.yaml: variable is empty",
+		"conditionalFiles network*.yaml: exactly one of equals and notEquals must be set",
+		"conditionalFiles both.yaml: exactly one of equals and notEquals must be set",
+	} {
+		assert.ErrorContains(t, err, wantErr)
+	}
+	assert.NotContains(t, err.Error(), "ingress.yaml")
+}
+
+func TestIsFileIncluded(t *testing.T) {
+	enabled, none := "true", ""
+	draftConfig := &DraftConfig{
+		TemplateName: "conditional",
+		Variables: []*BuilderVar{
+			{Name: "USEINGRESS", Type: "bool", Value: "yes"},
+			{Name: "SECRETNAME", Default: BuilderVarDefault{Value: ""}},
+		},
+		ConditionalFiles: map[string]FileCondition{
+			"manifests/ingress*.yaml": {Variable: "USEINGRESS", Equals: &enabled},
+			"manifests/secret.yaml":   {Variable: "SECRETNAME", NotEquals: &none},
+		},
+	}
+	assert.Nil(t, draftConfig.Validate())
+	draftConfig.TrackVariableUsage()
+
+	for file, want := range map[string]bool{
+		"manifests/ingress.yaml":        true,
+		"manifests/ingress-tls.yaml":    true,
+		"manifests/secret.yaml":         false,
+		"manifests/deployment.yaml":     true,
+		"ingress.yaml":                  true,
+		"manifests/nested/ingress.yaml": true,
+	} {
+		included, reason, err := draftConfig.IsFileIncluded(file)
+		assert.Nil(t, err)
+		assert.Equal(t, want, included, file)
+		if !want {
+			assert.Equal(t, `condition SECRETNAME notEquals "" is not met`, reason)
+		}
+	}
+	assert.True(t, draftConfig.usedVariables["USEINGRESS"])
+
+	draftConfig.SetVariable("USEINGRESS", "false")
+	included, reason, err := draftConfig.IsFileIncluded("manifests/ingress.yaml")
+	assert.Nil(t, err)
+	assert.False(t, included)
+	assert.Equal(t, `condition USEINGRESS equals "true" is not met`, reason)
+}
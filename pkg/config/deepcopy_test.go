@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fillNonZero sets every field reachable from v to a non-zero value so that a copy missing a field is detectable
+func fillNonZero(t *testing.T, v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fmt.Sprintf("value-%d", depth))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int:
+		v.SetInt(int64(depth + 1))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillNonZero(t, v.Field(i), depth+i)
+			}
+		}
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillNonZero(t, v.Elem(), depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < v.Len(); i++ {
+			fillNonZero(t, v.Index(i), depth+i)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fillNonZero(t, key, depth)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillNonZero(t, elem, depth)
+		v.SetMapIndex(key, elem)
+	case reflect.Func:
+		v.Set(reflect.MakeFunc(v.Type(), func(args []reflect.Value) []reflect.Value {
+			results := make([]reflect.Value, v.Type().NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(v.Type().Out(i))
+			}
+			return results
+		}))
+	default:
+		t.Fatalf("fillNonZero: unhandled kind %s, extend the test for the new field type", v.Kind())
+	}
+}
+
+// assertNoSharedMemory fails if any slice, map or pointer in copied aliases the same memory as in original
+func assertNoSharedMemory(t *testing.T, original, copied reflect.Value, path string) {
+	switch original.Kind() {
+	case reflect.Struct:
+		for i := 0; i < original.NumField(); i++ {
+			field := original.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			assert.False(t, copied.Field(i).IsZero(), "field %s.%s was not copied", path, field.Name)
+			assertNoSharedMemory(t, original.Field(i), copied.Field(i), path+"."+field.Name)
+		}
+	case reflect.Pointer:
+		assert.NotEqual(t, original.Pointer(), copied.Pointer(), "pointer %s is shared", path)
+		assertNoSharedMemory(t, original.Elem(), copied.Elem(), path)
+	case reflect.Slice:
+		assert.NotEqual(t, original.Pointer(), copied.Pointer(), "slice %s is shared", path)
+		for i := 0; i < original.Len(); i++ {
+			assertNoSharedMemory(t, original.Index(i), copied.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		assert.NotEqual(t, original.Pointer(), copied.Pointer(), "map %s is shared", path)
+	}
+}
+
+func TestDeepCopyCopiesEveryField(t *testing.T) {
+	original := &DraftConfig{}
+	fillNonZero(t, reflect.ValueOf(original).Elem(), 0)
+
+	copied := original.DeepCopy()
+
+	assertNoSharedMemory(t, reflect.ValueOf(original), reflect.ValueOf(copied), "DraftConfig")
+	assert.True(t, original.Equal(copied))
+	assert.True(t, copied.Equal(original))
+}
+
+func TestDeepCopyKeepsNil(t *testing.T) {
+	original := &DraftConfig{
+		TemplateName: "nil-maps",
+		Variables:    []*BuilderVar{{Name: "APPNAME"}},
+	}
+
+	copied := original.DeepCopy()
+
+	assert.Nil(t, copied.Versions)
+	assert.Nil(t, copied.FileNameOverrideMap)
+	assert.Nil(t, copied.Validators)
+	assert.Nil(t, copied.Transformers)
+	assert.Nil(t, copied.Variables[0].ExampleValues)
+	assert.Nil(t, copied.Variables[0].ActiveWhenConstraints)
+	assert.True(t, reflect.DeepEqual(original, copied))
+}
+
+func TestEqual(t *testing.T) {
+	original := &DraftConfig{
+		TemplateName: "equal",
+		Variables:    []*BuilderVar{{Name: "APPNAME", Value: "my-app"}},
+	}
+	original.SetVariableValidator("kind", func(string) error { return nil })
+
+	copied := original.DeepCopy()
+	assert.True(t, original.Equal(copied))
+
+	copied.SetVariable("APPNAME", "other-app")
+	assert.False(t, original.Equal(copied))
+	assert.Equal(t, "my-app", original.Variables[0].Value)
+
+	copied = original.DeepCopy()
+	copied.SetVariableValidator("kind", func(string) error { return nil })
+	assert.False(t, original.Equal(copied))
+
+	copied = original.DeepCopy()
+	copied.SetFileNameOverride("deployment.yaml", "app.yaml")
+	assert.False(t, original.Equal(copied))
+	assert.Nil(t, original.FileNameOverrideMap)
+
+	var nilConfig *DraftConfig
+	assert.False(t, original.Equal(nilConfig))
+	assert.True(t, nilConfig.Equal(nil))
+}
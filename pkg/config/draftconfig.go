@@ -4,8 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/Azure/draft/pkg/config/schema"
 	"github.com/Azure/draft/pkg/config/transformers"
 	"github.com/Azure/draft/pkg/config/validators"
 	log "github.com/sirupsen/logrus"
@@ -27,24 +35,50 @@ func (v VariableCondition) String() string {
 
 const draftConfigFile = "draft.yaml"
 
+// redactedValue replaces the value of sensitive variables in logs and recorders
+const redactedValue = "***"
+
+// listValueSeparator separates the items of an array variable's value
+const listValueSeparator = ","
+
 type VariableValidator func(string) error
 type VariableTransformer func(string) (any, error)
 
 type DraftConfig struct {
-	TemplateName        string                         `yaml:"templateName"`
-	DisplayName         string                         `yaml:"displayName"`
-	Description         string                         `yaml:"description"`
-	Type                string                         `yaml:"type"`
-	Versions            []string                       `yaml:"versions"`
-	DefaultVersion      string                         `yaml:"defaultVersion"`
-	Variables           []*BuilderVar                  `yaml:"variables"`
-	FileNameOverrideMap map[string]string              `yaml:"filenameOverrideMap"`
-	Validators          map[string]VariableValidator   `yaml:"validators"`
-	Transformers        map[string]VariableTransformer `yaml:"transformers"`
+	TemplateName           string                         `yaml:"templateName"`
+	DisplayName            string                         `yaml:"displayName"`
+	Description            string                         `yaml:"description"`
+	Language               string                         `yaml:"language"`
+	Type                   string                         `yaml:"type"`
+	Versions               []string                       `yaml:"versions"`
+	DefaultVersion         string                         `yaml:"defaultVersion"`
+	Labels                 map[string]string              `yaml:"labels"`
+	Variables              []*BuilderVar                  `yaml:"variables"`
+	VariableGroups         []VariableGroupDefinition      `yaml:"variableGroups"`
+	FileNameOverrideMap    map[string]string              `yaml:"filenameOverrideMap"`
+	Destinations           map[string]string              `yaml:"destinations"`
+	ConditionalFiles       map[string]FileCondition       `yaml:"conditionalFiles"`
+	RawFiles               []string                       `yaml:"rawFiles"`
+	Validators             map[string]VariableValidator   `yaml:"validators"`
+	Transformers           map[string]VariableTransformer `yaml:"transformers"`
+	VariableValidators     map[string]VariableValidator   `yaml:"variableValidators"`
+	VariableTransformers   map[string]VariableTransformer `yaml:"variableTransformers"`
+	CaseSensitiveVariables bool                           `yaml:"caseSensitiveVariables"`
+	LeftDelim              string                         `yaml:"leftDelim"`
+	RightDelim             string                         `yaml:"rightDelim"`
+
+	recorder        TemplateVariableRecorder
+	variableSources map[string]VariableSourceKind
+	sharedStore     *SharedVariableStore
+	generatorSource *GeneratorSource
+	usedVariables   map[string]bool
 }
 
 type BuilderVar struct {
 	Name                  string                 `yaml:"name"`
+	Aliases               []string               `yaml:"aliases"`
+	Deprecated            string                 `yaml:"deprecated"`
+	Group                 string                 `yaml:"group"`
 	ActiveWhenConstraints []ActiveWhenConstraint `yaml:"activeWhen"`
 	Default               BuilderVarDefault      `yaml:"default"`
 	Description           string                 `yaml:"description"`
@@ -52,377 +86,1879 @@ type BuilderVar struct {
 	AllowedValues         []string               `yaml:"allowedValues"`
 	Type                  string                 `yaml:"type"`
 	Kind                  string                 `yaml:"kind"`
+	Transform             BuilderVarTransform    `yaml:"transform"`
+	TransformTemplate     string                 `yaml:"transformTemplate"`
+	Pattern               string                 `yaml:"pattern"`
+	ErrorMessage          string                 `yaml:"errorMessage"`
+	Required              *bool                  `yaml:"required"`
+	MaxRenderedLength     int                    `yaml:"maxRenderedLength"`
+	Sensitive             bool                   `yaml:"sensitive"`
 	Value                 string                 `yaml:"value"`
 	Versions              string                 `yaml:"versions"`
+
+	transformTemplate *parsedTransformTemplate
+	pattern           *compiledPattern
+}
+
+// compiledPattern is a Pattern compiled from its source
+type compiledPattern struct {
+	source string
+	regexp *regexp.Regexp
 }
 
 // BuilderVarDefault holds info on the default value of a variable
 type BuilderVarDefault struct {
-	IsPromptDisabled bool   `yaml:"disablePrompt"`
-	ReferenceVar     string `yaml:"referenceVar"`
-	Value            string `yaml:"value"`
+	IsPromptDisabled   bool               `yaml:"disablePrompt"`
+	ReferenceVar       string             `yaml:"referenceVar"`
+	TransformReference bool               `yaml:"transformReference"`
+	EnvVar             string             `yaml:"envVar"`
+	FromFile           string             `yaml:"fromFile"`
+	TrimNewline        bool               `yaml:"trimNewline"`
+	Generator          string             `yaml:"generator"`
+	Value              string             `yaml:"value"`
+	VersionedDefaults  []VersionedDefault `yaml:"versionedDefaults"`
 }
 
-// ActiveWhenConstraints holds information on when a variable is actively used by a template based off other variable values
-type ActiveWhenConstraint struct {
-	VariableName string            `yaml:"variableName"`
-	Value        string            `yaml:"value"`
-	Condition    VariableCondition `yaml:"condition"`
+// BuilderVarTransform holds text added around a variable's value after the transformer of its kind has run, e.g. a
+// suffix of -sa to derive a service account name. Both may reference other variables with ${VARNAME}.
+type BuilderVarTransform struct {
+	Prefix string `yaml:"prefix"`
+	Suffix string `yaml:"suffix"`
 }
 
-func NewConfigFromFS(fileSys fs.FS, path string) (*DraftConfig, error) {
-	configBytes, err := fs.ReadFile(fileSys, path)
-	if err != nil {
-		return nil, err
-	}
-
-	var draftConfig DraftConfig
-	if err = yaml.Unmarshal(configBytes, &draftConfig); err != nil {
-		return nil, err
-	}
+// VersionedDefault holds a default value that applies to a semver range of template versions
+type VersionedDefault struct {
+	Versions string `yaml:"versions"`
+	Value    string `yaml:"value"`
+}
 
-	return &draftConfig, nil
+// builderVarDefaultYAML is BuilderVarDefault as written in draft.yaml, whose value may be a YAML list. It must have the
+// same fields as BuilderVarDefault.
+type builderVarDefaultYAML struct {
+	IsPromptDisabled   bool               `yaml:"disablePrompt"`
+	ReferenceVar       string             `yaml:"referenceVar"`
+	TransformReference bool               `yaml:"transformReference"`
+	EnvVar             string             `yaml:"envVar"`
+	FromFile           string             `yaml:"fromFile"`
+	TrimNewline        bool               `yaml:"trimNewline"`
+	Generator          string             `yaml:"generator"`
+	Value              listValue          `yaml:"value"`
+	VersionedDefaults  []VersionedDefault `yaml:"versionedDefaults"`
 }
 
-func (d *DraftConfig) GetVariableExampleValues() map[string][]string {
-	variableExampleValues := make(map[string][]string)
-	for _, variable := range d.Variables {
-		if len(variable.ExampleValues) > 0 {
-			variableExampleValues[variable.Name] = variable.ExampleValues
-		}
+// UnmarshalYAML allows the default value of an array variable to be written as a YAML list
+func (bd *BuilderVarDefault) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var fields builderVarDefaultYAML
+	if err := unmarshal(&fields); err != nil {
+		return err
 	}
 
-	return variableExampleValues
+	*bd = BuilderVarDefault{
+		IsPromptDisabled:   fields.IsPromptDisabled,
+		ReferenceVar:       fields.ReferenceVar,
+		TransformReference: fields.TransformReference,
+		EnvVar:             fields.EnvVar,
+		FromFile:           fields.FromFile,
+		TrimNewline:        fields.TrimNewline,
+		Generator:          fields.Generator,
+		Value:              string(fields.Value),
+		VersionedDefaults:  fields.VersionedDefaults,
+	}
+	return nil
 }
 
-// Returns a map of variable names to values used in Gotemplate
-func (d *DraftConfig) GetVariableMap() map[string]string {
-	variableMap := make(map[string]string)
-	for _, variable := range d.Variables {
-		variableMap[variable.Name] = variable.Value
+// listValue is a value written either as a scalar or as a YAML list, whose items are joined into a comma-separated
+// string
+type listValue string
+
+// UnmarshalYAML decodes a scalar as is and joins the items of a list with listValueSeparator
+func (lv *listValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items []string
+	if err := unmarshal(&items); err == nil {
+		*lv = listValue(strings.Join(items, listValueSeparator))
+		return nil
 	}
-	return variableMap
-}
 
-func (d *DraftConfig) GetVariable(name string) (*BuilderVar, error) {
-	for _, variable := range d.Variables {
-		if variable.Name == name {
-			return variable, nil
-		}
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
 	}
+	*lv = listValue(value)
+	return nil
+}
 
-	return nil, fmt.Errorf("variable %s not found", name)
+// ErrVariableInactive is returned when reading a variable whose ActiveWhen constraints are not met
+var ErrVariableInactive = errors.New("variable is inactive")
+
+// VariableTypeError is returned when a variable's value does not match its declared type
+type VariableTypeError struct {
+	Name  string
+	Type  string
+	Value string
 }
 
-func (d *DraftConfig) GetVariableValue(name string) (any, error) {
-	for _, variable := range d.Variables {
-		if variable.Name == name {
-			if variable.Value == "" {
-				return "", fmt.Errorf("variable %s has no value", name)
-			}
+func (e *VariableTypeError) Error() string {
+	return fmt.Sprintf("variable %s of type %s has invalid value: %q", e.Name, e.Type, e.Value)
+}
 
-			if err := d.GetVariableValidator(variable.Kind)(variable.Value); err != nil {
-				return "", fmt.Errorf("failed variable validation: %w", err)
-			}
+// VariableValidationError replaces the error of a failed validation or transformation with the variable's ErrorMessage.
+// The original error is available through errors.Unwrap.
+type VariableValidationError struct {
+	Name    string
+	Message string
+	Err     error
+}
 
-			response, err := d.GetVariableTransformer(variable.Kind)(variable.Value)
-			if err != nil {
-				return "", fmt.Errorf("failed variable transformation: %w", err)
-			}
+func (e *VariableValidationError) Error() string {
+	return e.Message
+}
 
-			return response, nil
-		}
+func (e *VariableValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validationError returns err replaced by the variable's ErrorMessage, if it has one
+func (bv *BuilderVar) validationError(err error) error {
+	if err == nil || bv.ErrorMessage == "" {
+		return err
 	}
 
-	return "", fmt.Errorf("variable %s not found", name)
+	return &VariableValidationError{Name: bv.Name, Message: bv.ErrorMessage, Err: err}
 }
 
-func (d *DraftConfig) SetVariable(name, value string) {
-	if variable, err := d.GetVariable(name); err != nil {
-		d.Variables = append(d.Variables, &BuilderVar{
-			Name:  name,
-			Value: value,
-		})
-	} else {
-		variable.Value = value
-	}
+// ActiveWhenConstraints holds information on when a variable is actively used by a template based off other variable values
+type ActiveWhenConstraint struct {
+	VariableName string            `yaml:"variableName"`
+	Value        string            `yaml:"value"`
+	Condition    VariableCondition `yaml:"condition"`
 }
 
-// GetVariableTransformer returns the transformer for a specific variable kind
-func (d *DraftConfig) GetVariableTransformer(kind string) VariableTransformer {
-	// user overrides
-	if transformer, ok := d.Transformers[kind]; ok {
-		return transformer
-	}
+// ConfigOption configures how a draft config is loaded
+type ConfigOption func(*configOptions)
 
-	// internally defined transformers
-	return transformers.GetTransformer(kind)
+type configOptions struct {
+	skipValidation bool
+	strict         bool
 }
 
-// GetVariableValidator returns the validator for a specific variable kind
-func (d *DraftConfig) GetVariableValidator(kind string) VariableValidator {
-	// user overrides
-	if validator, ok := d.Validators[kind]; ok {
-		return validator
+// WithoutValidation skips DraftConfig.Validate when loading a draft config
+func WithoutValidation() ConfigOption {
+	return func(o *configOptions) {
+		o.skipValidation = true
 	}
+}
 
-	// internally defined validators
-	return validators.GetValidator(kind)
+// Strict rejects draft configs with unknown fields or values of the wrong type, checked against the draft.yaml JSON Schema
+func Strict() ConfigOption {
+	return func(o *configOptions) {
+		o.strict = true
+	}
 }
 
-// SetVariableTransformer sets the transformer for a specific variable kind
-func (d *DraftConfig) SetVariableTransformer(kind string, transformer VariableTransformer) {
-	if d.Transformers == nil {
-		d.Transformers = make(map[string]VariableTransformer)
+// NewConfigFromFS loads and validates the draft config at path
+func NewConfigFromFS(fileSys fs.FS, path string, opts ...ConfigOption) (*DraftConfig, error) {
+	configBytes, err := fs.ReadFile(fileSys, path)
+	if err != nil {
+		return nil, err
 	}
-	d.Transformers[kind] = transformer
+
+	return newConfig(configBytes, path, newConfigOptions(opts))
 }
 
-// SetVariableValidator sets the validator for a specific variable kind
-func (d *DraftConfig) SetVariableValidator(kind string, validator VariableValidator) {
-	if d.Validators == nil {
-		d.Validators = make(map[string]VariableValidator)
+// NewConfigFromBytes loads and validates a draft config from its YAML or JSON contents. In strict mode unknown fields
+// and values of the wrong type are rejected with their line and field, otherwise they are ignored as they always have
+// been. Passing Strict in opts also enables strict mode.
+func NewConfigFromBytes(configBytes []byte, strict bool, opts ...ConfigOption) (*DraftConfig, error) {
+	options := newConfigOptions(opts)
+	options.strict = options.strict || strict
+
+	return newConfig(configBytes, "", options)
+}
+
+func newConfigOptions(opts []ConfigOption) *configOptions {
+	options := &configOptions{}
+	for _, opt := range opts {
+		opt(options)
 	}
-	d.Validators[kind] = validator
+	return options
 }
 
-// ApplyDefaultVariables will apply the defaults to variables that are not already set
-func (d *DraftConfig) ApplyDefaultVariables() error {
-	for _, variable := range d.Variables {
-		if variable.Value == "" {
-			if variable.Default.ReferenceVar != "" {
-				referenceVar, err := d.GetVariable(variable.Default.ReferenceVar)
-				if err != nil {
-					return fmt.Errorf("apply default variables: %w", err)
-				}
-				defaultVal, err := d.recurseReferenceVars(referenceVar, referenceVar, true)
-				if err != nil {
-					return fmt.Errorf("apply default variables: %w", err)
-				}
-				log.Infof("Variable %s defaulting to value %s", variable.Name, defaultVal)
-				variable.Value = defaultVal
-			}
+// newConfig loads a draft config, naming it after source in validation errors
+func newConfig(configBytes []byte, source string, options *configOptions) (*DraftConfig, error) {
+	invalidConfigError := func(err error) error {
+		if source == "" {
+			return fmt.Errorf("invalid draft config: %w", err)
+		}
+		return fmt.Errorf("invalid draft config %s: %w", source, err)
+	}
 
-			isVarActive, err := d.CheckActiveWhenConstraint(variable)
-			if err != nil {
-				return fmt.Errorf("unable to check ActiveWhen constraint: %w", err)
-			}
+	unmarshal := yaml.Unmarshal
+	if options.strict {
+		if err := schema.ValidateAgainstSchema(configBytes); err != nil {
+			return nil, invalidConfigError(err)
+		}
+		unmarshal = yaml.UnmarshalStrict
+	}
 
-			if !isVarActive {
-				continue
-			}
+	var draftConfig DraftConfig
+	if err := unmarshal(configBytes, &draftConfig); err != nil {
+		return nil, err
+	}
 
-			if variable.Value == "" {
-				if variable.Default.Value != "" {
-					log.Infof("Variable %s defaulting to value %s", variable.Name, variable.Default.Value)
-					variable.Value = variable.Default.Value
-				} else {
-					return errors.New("variable " + variable.Name + " has no default value")
-				}
-			}
+	if !options.skipValidation {
+		if err := draftConfig.Validate(); err != nil {
+			return nil, invalidConfigError(err)
 		}
 	}
 
-	return nil
+	return &draftConfig, nil
 }
 
-// ApplyDefaultVariablesForVersion will apply the defaults to variables that are not already set for a specific template version
-func (d *DraftConfig) ApplyDefaultVariablesForVersion(version string) error {
-	v, err := semver.Parse(version)
-	if err != nil {
-		return fmt.Errorf("invalid version: %w", err)
+// Validate checks the structure of the draft config, returning every problem found joined into a single error
+func (d *DraftConfig) Validate() error {
+	var errs []error
+
+	if d.TemplateName == "" {
+		errs = append(errs, errors.New("templateName is empty"))
 	}
 
-	if !slices.Contains(d.Versions, version) {
-		return fmt.Errorf("requested version outside of valid versions: %s", version)
+	for _, version := range d.Versions {
+		if _, err := semver.Parse(version); err != nil {
+			errs = append(errs, fmt.Errorf("versions: invalid version %s: %w", version, err))
+		}
 	}
 
-	for _, variable := range d.Variables {
-		if variable.Value == "" {
-			expectedRange, err := semver.ParseRange(variable.Versions)
-			if err != nil {
-				return fmt.Errorf("invalid variable versions: %w", err)
-			}
+	if d.DefaultVersion != "" && !slices.Contains(d.Versions, d.DefaultVersion) {
+		errs = append(errs, fmt.Errorf("defaultVersion %s is not one of versions [%s]", d.DefaultVersion, strings.Join(d.Versions, ", ")))
+	}
 
-			if !expectedRange(v) {
-				log.Infof("Variable %s versions %s is outside input version %s, skipping", variable.Name, variable.Versions, version)
-				continue
-			}
+	variableCounts := make(map[string]int)
+	duplicateNames := []string{}
+	for i, variable := range d.Variables {
+		if variable.Name == "" {
+			errs = append(errs, fmt.Errorf("variables[%d]: name is empty", i))
+		}
 
-			if variable.Default.ReferenceVar != "" {
-				referenceVar, err := d.GetVariable(variable.Default.ReferenceVar)
-				if err != nil {
-					return fmt.Errorf("apply default variables: %w", err)
-				}
+		for _, name := range append([]string{variable.Name}, variable.Aliases...) {
+			variableCounts[name]++
+			if name != "" && variableCounts[name] == 2 {
+				duplicateNames = append(duplicateNames, name)
+			}
+		}
 
-				defaultVal, err := d.recurseReferenceVars(referenceVar, referenceVar, true)
-				if err != nil {
-					return fmt.Errorf("apply default variables: %w", err)
+		if variable.Kind != "" {
+			for _, kind := range chainedKinds(variable.Kind) {
+				if !d.isKnownKind(kind) {
+					errs = append(errs, fmt.Errorf("variable %s: unknown kind %s", variable.Name, kind))
 				}
-				log.Infof("Variable %s defaulting to value %s", variable.Name, defaultVal)
-				variable.Value = defaultVal
 			}
+		}
 
-			isVarActive, err := d.CheckActiveWhenConstraint(variable)
-			if err != nil {
-				return fmt.Errorf("unable to check ActiveWhen constraint: %w", err)
+		if variable.Versions != "" {
+			if _, err := semver.ParseRange(variable.Versions); err != nil {
+				errs = append(errs, fmt.Errorf("variable %s: invalid versions range %s: %w", variable.Name, variable.Versions, err))
 			}
+		}
 
-			if !isVarActive {
-				continue
-			}
+		if _, err := variable.compilePattern(); err != nil {
+			errs = append(errs, err)
+		}
 
-			if variable.Value == "" {
-				if variable.Default.Value != "" {
-					log.Infof("Variable %s defaulting to value %s", variable.Name, variable.Default.Value)
-					variable.Value = variable.Default.Value
-				} else {
-					return errors.New("variable " + variable.Name + " has no default value")
-				}
+		if err := variable.checkVersionedDefaults(d.Versions); err != nil {
+			errs = append(errs, err)
+		}
+
+		if variable.TransformTemplate != "" {
+			if _, err := variable.parseTransformTemplate(); err != nil {
+				errs = append(errs, err)
 			}
 		}
-	}
 
-	return nil
-}
+		if variable.MaxRenderedLength < 0 {
+			errs = append(errs, fmt.Errorf("variable %s: maxRenderedLength must not be negative", variable.Name))
+		}
 
-func (d *DraftConfig) CheckActiveWhenConstraint(variable *BuilderVar) (bool, error) {
-	if len(variable.ActiveWhenConstraints) > 0 {
-		isVarActive := true
-		for _, activeWhen := range variable.ActiveWhenConstraints {
-			refVar, err := d.GetVariable(activeWhen.VariableName)
-			if err != nil {
-				return false, fmt.Errorf("unable to get ActiveWhen reference variable: %w", err)
+		if variable.Default.Generator != "" {
+			if _, _, err := parseGenerator(variable.Default.Generator); err != nil {
+				errs = append(errs, fmt.Errorf("variable %s: %w", variable.Name, err))
 			}
+		}
+	}
 
-			checkValue := refVar.Value
-			if checkValue == "" {
-				if refVar.Default.Value != "" {
-					checkValue = refVar.Default.Value
-				}
+	errs = append(errs, d.checkVariableGroups()...)
 
-				if refVar.Default.ReferenceVar != "" {
-					refValue, err := d.recurseReferenceVars(refVar, refVar, true)
-					if err != nil {
-						return false, err
-					}
-					if refValue == "" {
-						return false, errors.New("reference variable has no value")
-					}
+	if err := d.checkDelims(); err != nil {
+		errs = append(errs, err)
+	}
 
-					checkValue = refValue
-				}
-			}
+	errs = append(errs, d.checkConditionalFiles()...)
+	errs = append(errs, d.checkDestinations()...)
 
-			switch activeWhen.Condition {
-			case EqualTo:
-				isVarActive = checkValue == activeWhen.Value
-			case NotEqualTo:
-				isVarActive = checkValue != activeWhen.Value
-			default:
-				return false, fmt.Errorf("invalid activeWhen condition: %s", activeWhen.Condition)
-			}
+	for _, pattern := range d.RawFiles {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("rawFiles: invalid pattern %q: %w", pattern, err))
 		}
-		return isVarActive, nil
 	}
 
-	return true, nil
+	if len(duplicateNames) > 0 {
+		errs = append(errs, fmt.Errorf("duplicate variable names: %s", strings.Join(duplicateNames, ", ")))
+	}
+
+	if _, err := d.sortByReferenceVar(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
 }
 
-// recurseReferenceVars recursively checks each variable's ReferenceVar if it doesn't have a custom input. If there's no more ReferenceVars, it will return the default value of the last ReferenceVar.
-func (d *DraftConfig) recurseReferenceVars(referenceVar *BuilderVar, variableCheck *BuilderVar, isFirst bool) (string, error) {
-	if !isFirst && referenceVar.Name == variableCheck.Name {
-		return "", errors.New("cyclical reference detected")
+// IsRawFile returns true if file, a slash separated path relative to the template directory, matches one of the rawFiles
+// patterns and must be copied as it is instead of being executed as a template
+func (d *DraftConfig) IsRawFile(file string) bool {
+	for _, pattern := range d.RawFiles {
+		if matched, _ := path.Match(pattern, file); matched {
+			return true
+		}
 	}
+	return false
+}
 
-	// If referenceVar has a custom value, return it, else check its ReferenceVar, else return its default value
-	if referenceVar.Value != "" {
-		return referenceVar.Value, nil
-	} else if referenceVar.Default.ReferenceVar != "" {
-		referenceVar, err := d.GetVariable(referenceVar.Default.ReferenceVar)
-		if err != nil {
-			return "", fmt.Errorf("recurse reference vars: %w", err)
+// checkDestinations checks that every destinations pattern is valid and maps to a directory. Patterns may contain **
+// elements, which path.Match doesn't know, so each element is checked on its own.
+func (d *DraftConfig) checkDestinations() []error {
+	patterns := make([]string, 0, len(d.Destinations))
+	for pattern := range d.Destinations {
+		patterns = append(patterns, pattern)
+	}
+	slices.Sort(patterns)
+
+	var errs []error
+	for _, pattern := range patterns {
+		for _, element := range strings.Split(pattern, "/") {
+			if _, err := path.Match(element, ""); err != nil {
+				errs = append(errs, fmt.Errorf("destinations: invalid pattern %q: %w", pattern, err))
+				break
+			}
 		}
 
-		return d.recurseReferenceVars(referenceVar, variableCheck, false)
+		if d.Destinations[pattern] == "" {
+			errs = append(errs, fmt.Errorf("destinations %s: directory is empty", pattern))
+		}
 	}
+	return errs
+}
 
-	return referenceVar.Default.Value, nil
+// checkDelims checks that leftDelim and rightDelim are either both unset or both set to different delimiters
+func (d *DraftConfig) checkDelims() error {
+	if d.LeftDelim == "" && d.RightDelim == "" {
+		return nil
+	}
+	if d.LeftDelim == "" || d.RightDelim == "" {
+		return errors.New("leftDelim and rightDelim must be set together")
+	}
+	if d.LeftDelim == d.RightDelim {
+		return fmt.Errorf("leftDelim and rightDelim must differ, both are %q", d.LeftDelim)
+	}
+	return nil
 }
 
-// handles flags that are meant to represent template variables
-func (d *DraftConfig) VariableMapToDraftConfig(flagVariablesMap map[string]string) {
-	for flagName, flagValue := range flagVariablesMap {
-		log.Debugf("flag variable %s=%s", flagName, flagValue)
-		d.SetVariable(flagName, flagValue)
+func (d *DraftConfig) GetVariableExampleValues() map[string][]string {
+	variableExampleValues := make(map[string][]string)
+	for _, variable := range d.Variables {
+		if len(variable.ExampleValues) > 0 {
+			variableExampleValues[variable.Name] = variable.ExampleValues
+		}
 	}
+
+	return variableExampleValues
 }
 
-// SetFileNameOverride sets the filename override for a specific file
-func (d *DraftConfig) SetFileNameOverride(input, override string) {
-	if d.FileNameOverrideMap == nil {
-		d.FileNameOverrideMap = make(map[string]string)
+// Returns a map of variable names to values used in Gotemplate
+func (d *DraftConfig) GetVariableMap() map[string]string {
+	variableMap := make(map[string]string)
+	for _, variable := range d.Variables {
+		variableMap[variable.Name] = variable.Value
 	}
-	d.FileNameOverrideMap[input] = override
+	return variableMap
 }
 
-func (d *DraftConfig) DeepCopy() *DraftConfig {
-	newConfig := &DraftConfig{
-		TemplateName:        d.TemplateName,
-		DisplayName:         d.DisplayName,
-		Description:         d.Description,
-		Type:                d.Type,
-		Versions:            make([]string, len(d.Versions)),
-		DefaultVersion:      d.DefaultVersion,
-		Variables:           make([]*BuilderVar, len(d.Variables)),
-		FileNameOverrideMap: make(map[string]string),
+// GetVariable returns the variable with the given name. Deprecated aliases resolve to their canonical variable with a warning.
+func (d *DraftConfig) GetVariable(name string) (*BuilderVar, error) {
+	for _, variable := range d.Variables {
+		if variable.Name == name {
+			return variable, nil
+		}
 	}
 
-	for i, version := range d.Versions {
-		newConfig.Versions[i] = version
+	for _, variable := range d.Variables {
+		if slices.Contains(variable.Aliases, name) {
+			log.Warnf("Variable %s is deprecated, use %s instead", name, variable.Name)
+			return variable, nil
+		}
 	}
 
-	for i, variable := range d.Variables {
-		newConfig.Variables[i] = variable.DeepCopy()
+	return nil, d.variableNotFoundError(name)
+}
+
+// GetVariableValue returns the validated and transformed value of the variable with the given name or alias
+func (d *DraftConfig) GetVariableValue(name string) (any, error) {
+	variable, err := d.GetVariable(name)
+	if err != nil {
+		return "", err
 	}
 
-	for k, v := range d.FileNameOverrideMap {
-		newConfig.FileNameOverrideMap[k] = v
+	d.markUsed(variable)
+	response, err := d.variableValue(variable, variable.Value)
+	if err != nil {
+		return "", err
 	}
 
-	return newConfig
+	d.record(variable)
+	return response, nil
 }
 
-func (bv *BuilderVar) DeepCopy() *BuilderVar {
-	newVar := &BuilderVar{
-		Name:                  bv.Name,
-		Default:               bv.Default,
-		Description:           bv.Description,
-		Type:                  bv.Type,
-		Kind:                  bv.Kind,
-		Value:                 bv.Value,
-		Versions:              bv.Versions,
-		ExampleValues:         make([]string, len(bv.ExampleValues)),
-		AllowedValues:         make([]string, len(bv.AllowedValues)),
-		ActiveWhenConstraints: make([]ActiveWhenConstraint, len(bv.ActiveWhenConstraints)),
-	}
-	for i, awc := range bv.ActiveWhenConstraints {
-		newVar.ActiveWhenConstraints[i] = *awc.DeepCopy()
-	}
-	copy(newVar.AllowedValues, bv.AllowedValues)
-	copy(newVar.ExampleValues, bv.ExampleValues)
-	return newVar
-}
+// GetVariableValueOrDefault returns the value of a variable like GetVariableValue, but when the variable has no value
+// it is resolved through the variable's default the same way ApplyDefaultVariables would. The stored variable is left
+// untouched unless PersistDefault is passed.
+func (d *DraftConfig) GetVariableValueOrDefault(name string, opts ...DefaultsOption) (any, error) {
+	options := newDefaultsOptions(opts)
 
-func (awc ActiveWhenConstraint) DeepCopy() *ActiveWhenConstraint {
-	return &ActiveWhenConstraint{
-		VariableName: awc.VariableName,
-		Value:        awc.Value,
-		Condition:    awc.Condition,
+	variable, err := d.GetVariable(name)
+	if err != nil {
+		return "", err
+	}
+	d.markUsed(variable)
+
+	value := variable.Value
+	if value == "" {
+		value, err = d.recurseReferenceVars(variable, nil)
+		if err != nil {
+			return "", err
+		}
+		if value == "" && variable.IsRequired() {
+			return "", fmt.Errorf("variable %s has no value or default value", variable.Name)
+		}
 	}
+
+	response, err := d.variableValue(variable, value)
+	if err != nil {
+		return "", err
+	}
+
+	if options.persist && variable.Value == "" && value != "" {
+		typedValue, err := normalizeTypedValue(variable, value)
+		if err != nil {
+			return "", err
+		}
+		log.Infof("Variable %s defaulting to value %s", variable.Name, variable.redact(typedValue))
+		variable.Value = typedValue
+		d.record(variable)
+	}
+
+	return response, nil
 }
 
-// TemplateVariableRecorder is an interface for recording variables that are read using draft configs
-type TemplateVariableRecorder interface {
-	Record(key, value string)
+// variableValue validates and transforms value as a value of variable
+func (d *DraftConfig) variableValue(variable *BuilderVar, value string) (any, error) {
+	isVarActive, err := d.CheckActiveWhenConstraint(variable)
+	if err != nil {
+		return "", fmt.Errorf("unable to check ActiveWhen constraint: %w", err)
+	}
+
+	if !isVarActive {
+		return "", fmt.Errorf("variable %s: %w", variable.Name, ErrVariableInactive)
+	}
+
+	if value == "" {
+		if !variable.IsRequired() {
+			return "", nil
+		}
+		return "", fmt.Errorf("variable %s has no value", variable.Name)
+	}
+
+	value, err = normalizeTypedValue(variable, value)
+	if err != nil {
+		return "", variable.validationError(err)
+	}
+
+	if variable.Type == "array" {
+		items, err := d.validateListValue(variable, value)
+		if err != nil {
+			return "", err
+		}
+		value = strings.Join(items, listValueSeparator)
+	} else if err := d.validateVariableValue(variable, value); err != nil {
+		return "", err
+	}
+
+	response, err := d.variableTransformer(variable)(value)
+	if err != nil {
+		return "", variable.validationError(fmt.Errorf("failed variable transformation: %w", variable.redactError(err, value)))
+	}
+
+	response, err = d.applyTransformAffixes(variable, response)
+	if err != nil {
+		return "", err
+	}
+
+	if rendered, ok := response.(string); ok && variable.MaxRenderedLength > 0 {
+		response = transformers.TruncateWithHash(rendered, variable.MaxRenderedLength)
+	}
+
+	return response, nil
+}
+
+// applyTransformAffixes adds the variable's Transform.Prefix and Transform.Suffix, with their ${VARNAME} references
+// expanded, around its transformed value and checks the result with the variable's validator
+func (d *DraftConfig) applyTransformAffixes(variable *BuilderVar, transformed any) (any, error) {
+	if variable.Transform.Prefix == "" && variable.Transform.Suffix == "" {
+		return transformed, nil
+	}
+
+	value, ok := transformed.(string)
+	if !ok {
+		return "", fmt.Errorf("variable %s: transformer for kind %s returned %T, not a string, so transform prefix and suffix can't be added", variable.Name, variable.Kind, transformed)
+	}
+
+	prefix, err := d.interpolateValue(variable, "transform prefix", variable.Transform.Prefix, []string{variable.Name})
+	if err != nil {
+		return "", err
+	}
+	suffix, err := d.interpolateValue(variable, "transform suffix", variable.Transform.Suffix, []string{variable.Name})
+	if err != nil {
+		return "", err
+	}
+
+	affixed := prefix + value + suffix
+	if err := d.transformedValueValidator(variable)(affixed); err != nil {
+		return "", variable.validationError(fmt.Errorf("failed variable validation with transform prefix and suffix: %w", err))
+	}
+
+	return affixed, nil
+}
+
+// IsRequired returns true unless the variable is explicitly marked as not required. Optional variables may be left
+// empty: they are not defaulted or validated when they have no value, and reading them returns an empty value.
+func (bv *BuilderVar) IsRequired() bool {
+	return bv.Required == nil || *bv.Required
+}
+
+// GetValidatedVariableMap returns the validated and transformed value of every active variable that has a value, which
+// template files read as .Variables. Errors name the variable and whether its value came from a default or from user input.
+func (d *DraftConfig) GetValidatedVariableMap() (map[string]any, error) {
+	varMap := make(map[string]any)
+	var errs []error
+	for _, variable := range d.Variables {
+		if variable.Value == "" {
+			continue
+		}
+
+		value, err := d.variableValue(variable, variable.Value)
+		if errors.Is(err, ErrVariableInactive) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("variable %s has invalid %s: %w", variable.Name, d.variableSources[variable.Name].describe(), err))
+			continue
+		}
+		varMap[variable.Name] = value
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return varMap, nil
+}
+
+// GetVariableValues returns the items of an array variable, validating each item against the variable's kind
+func (d *DraftConfig) GetVariableValues(name string) ([]string, error) {
+	variable, err := d.GetVariable(name)
+	if err != nil {
+		return nil, err
+	}
+	d.markUsed(variable)
+
+	if variable.Type != "array" {
+		return nil, fmt.Errorf("variable %s is of type %s, not array", name, variable.Type)
+	}
+
+	if variable.Value == "" {
+		if !variable.IsRequired() {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("variable %s has no value", name)
+	}
+
+	return d.validateListValue(variable, variable.Value)
+}
+
+// validateListValue splits a list value into its items, validating each item against the variable's kind
+func (d *DraftConfig) validateListValue(variable *BuilderVar, value string) ([]string, error) {
+	items := splitListValue(value)
+	for _, item := range items {
+		if err := d.validateVariableValue(variable, item); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// GetVariableBool returns the value of a bool variable, accepting the values validators.ParseBoolean accepts
+func (d *DraftConfig) GetVariableBool(name string) (bool, error) {
+	value, err := d.getTypedVariableValue(name, "bool")
+	if err != nil {
+		return false, err
+	}
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// GetVariableInt returns the value of an int variable
+func (d *DraftConfig) GetVariableInt(name string) (int, error) {
+	value, err := d.getTypedVariableValue(name, "int")
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+// getTypedVariableValue returns the normalized and validated value of a variable that must be of the given type
+func (d *DraftConfig) getTypedVariableValue(name, variableType string) (string, error) {
+	variable, err := d.GetVariable(name)
+	if err != nil {
+		return "", err
+	}
+	d.markUsed(variable)
+
+	if variable.Type != variableType {
+		return "", fmt.Errorf("variable %s is of type %s, not %s", name, variable.Type, variableType)
+	}
+
+	if variable.Value == "" {
+		if !variable.IsRequired() {
+			return "", nil
+		}
+		return "", fmt.Errorf("variable %s has no value", name)
+	}
+
+	value, err := normalizeTypedValue(variable, variable.Value)
+	if err != nil {
+		return "", variable.validationError(err)
+	}
+
+	if err := d.validateVariableValue(variable, value); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// validateVariableValue checks a single value against the variable's pattern, kind validator and allowed values
+func (d *DraftConfig) validateVariableValue(variable *BuilderVar, value string) error {
+	if err := checkPattern(variable, value); err != nil {
+		return variable.validationError(err)
+	}
+
+	if err := d.variableValidator(variable)(value); err != nil {
+		return variable.validationError(fmt.Errorf("failed variable validation: %w", variable.redactError(err, value)))
+	}
+
+	return variable.validationError(checkAllowedValue(variable, value))
+}
+
+// GetVariableAllowedValues returns the values a variable is restricted to, or an empty slice if any value is allowed
+func (d *DraftConfig) GetVariableAllowedValues(name string) ([]string, error) {
+	variable, err := d.GetVariable(name)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedValues := make([]string, len(variable.AllowedValues))
+	copy(allowedValues, variable.AllowedValues)
+	return allowedValues, nil
+}
+
+// SetVariable sets the value of the named variable, adding it to the config if it doesn't exist
+func (d *DraftConfig) SetVariable(name, value string) {
+	variable, err := d.GetVariable(name)
+	if err != nil {
+		variable = &BuilderVar{
+			Name: name,
+		}
+		d.Variables = append(d.Variables, variable)
+	}
+	variable.Value = value
+
+	if variable.Deprecated != "" {
+		log.Warnf("Variable %s is deprecated: %s", variable.Name, variable.Deprecated)
+	}
+
+	d.record(variable)
+
+	if d.sharedStore != nil {
+		d.sharedStore.share(d, variable.Name, value)
+	}
+}
+
+// SetRecorder sets a recorder that is passed every variable value read, set or defaulted through the draft config
+func (d *DraftConfig) SetRecorder(recorder TemplateVariableRecorder) {
+	d.recorder = recorder
+}
+
+// record passes the variable to the config's recorder, if one is set
+func (d *DraftConfig) record(variable *BuilderVar) {
+	if d.recorder != nil {
+		RecordVariable(d.recorder, variable)
+	}
+}
+
+// GetVariableTransformer returns the transformer for a specific variable kind. For a chain of kinds such as
+// trimSpace,lowercase,kubeName it returns a transformer that applies the transformer of each kind from left to right.
+func (d *DraftConfig) GetVariableTransformer(kind string) VariableTransformer {
+	// user overrides
+	if transformer, ok := d.Transformers[kind]; ok {
+		return transformer
+	}
+
+	if isKindChain(kind) {
+		return d.chainTransformer(chainedKinds(kind))
+	}
+
+	// internally defined transformers
+	return transformers.GetTransformer(kind)
+}
+
+// GetVariableValidator returns the validator for a specific variable kind. For a chain of kinds it returns a validator
+// that checks the value produced by the chain's transformer with the validator of every kind in the chain.
+func (d *DraftConfig) GetVariableValidator(kind string) VariableValidator {
+	// user overrides
+	if validator, ok := d.Validators[kind]; ok {
+		return validator
+	}
+
+	if isKindChain(kind) {
+		return d.chainValidator(chainedKinds(kind))
+	}
+
+	// internally defined validators
+	return validators.GetValidator(kind)
+}
+
+// variableTransformer returns the transformer used for the variable: a transformer set for its name takes precedence
+// over its TransformTemplate, then over one set for its kind, which takes precedence over the built-in transformer of
+// its kind
+func (d *DraftConfig) variableTransformer(variable *BuilderVar) VariableTransformer {
+	return d.variableTransformerOnPath(variable, nil)
+}
+
+// variableTransformerOnPath returns the transformer of variableTransformer while the values of the variables in path
+// are being resolved, which its TransformTemplate doesn't resolve again
+func (d *DraftConfig) variableTransformerOnPath(variable *BuilderVar, path []string) VariableTransformer {
+	if transformer, ok := d.VariableTransformers[variable.Name]; ok {
+		return transformer
+	}
+
+	if variable.TransformTemplate != "" {
+		return d.transformTemplateTransformer(variable, path)
+	}
+
+	return d.GetVariableTransformer(variable.Kind)
+}
+
+// variableValidator returns the validator used for the variable: a validator set for its name takes precedence over
+// one set for its kind, which takes precedence over the built-in validator of its kind. The validators of a chain of
+// kinds check the value produced by the whole chain of transformers.
+func (d *DraftConfig) variableValidator(variable *BuilderVar) VariableValidator {
+	if validator, ok := d.VariableValidators[variable.Name]; ok {
+		return validator
+	}
+
+	if isKindChain(variable.Kind) {
+		return func(value string) error {
+			transformed, err := d.GetVariableTransformer(variable.Kind)(value)
+			if err != nil {
+				return err
+			}
+
+			transformedVal, ok := transformed.(string)
+			if !ok {
+				return fmt.Errorf("transformer for kind %s returned %T, not a string", variable.Kind, transformed)
+			}
+			return d.GetVariableValidator(variable.Kind)(transformedVal)
+		}
+	}
+
+	return d.GetVariableValidator(variable.Kind)
+}
+
+// transformedValueValidator returns the validator for a value the variable's transformer has already produced, which
+// for a chain of kinds must not be passed through the chain again
+func (d *DraftConfig) transformedValueValidator(variable *BuilderVar) VariableValidator {
+	if _, ok := d.VariableValidators[variable.Name]; !ok && isKindChain(variable.Kind) {
+		return d.GetVariableValidator(variable.Kind)
+	}
+
+	return d.variableValidator(variable)
+}
+
+// chainTransformer returns a transformer that passes a value through the transformers of kinds from left to right.
+// Every transformer in the chain must produce a string.
+func (d *DraftConfig) chainTransformer(kinds []string) VariableTransformer {
+	return func(value string) (any, error) {
+		for _, kind := range kinds {
+			transformed, err := d.GetVariableTransformer(kind)(value)
+			if err != nil {
+				return "", fmt.Errorf("kind %s: %w", kind, err)
+			}
+
+			transformedVal, ok := transformed.(string)
+			if !ok {
+				return "", fmt.Errorf("transformer for kind %s returned %T, not a string, so it can't be chained", kind, transformed)
+			}
+			value = transformedVal
+		}
+
+		return value, nil
+	}
+}
+
+// chainValidator returns a validator that checks a value with the validator of every kind in kinds
+func (d *DraftConfig) chainValidator(kinds []string) VariableValidator {
+	return func(value string) error {
+		for _, kind := range kinds {
+			if err := d.GetVariableValidator(kind)(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// SetVariableTransformer sets the transformer for a specific variable kind
+func (d *DraftConfig) SetVariableTransformer(kind string, transformer VariableTransformer) {
+	if d.Transformers == nil {
+		d.Transformers = make(map[string]VariableTransformer)
+	}
+	d.Transformers[kind] = transformer
+}
+
+// SetVariableValidator sets the validator for a specific variable kind
+func (d *DraftConfig) SetVariableValidator(kind string, validator VariableValidator) {
+	if d.Validators == nil {
+		d.Validators = make(map[string]VariableValidator)
+	}
+	d.Validators[kind] = validator
+}
+
+// SetVariableTransformerForName sets the transformer for a single variable, overriding the transformer of its kind
+func (d *DraftConfig) SetVariableTransformerForName(name string, transformer VariableTransformer) {
+	if d.VariableTransformers == nil {
+		d.VariableTransformers = make(map[string]VariableTransformer)
+	}
+	d.VariableTransformers[d.canonicalVariableName(name)] = transformer
+}
+
+// SetVariableValidatorForName sets the validator for a single variable, overriding the validator of its kind
+func (d *DraftConfig) SetVariableValidatorForName(name string, validator VariableValidator) {
+	if d.VariableValidators == nil {
+		d.VariableValidators = make(map[string]VariableValidator)
+	}
+	d.VariableValidators[d.canonicalVariableName(name)] = validator
+}
+
+// canonicalVariableName returns the declared name of the variable matching name the way --variable flags are matched,
+// or name if there is none
+func (d *DraftConfig) canonicalVariableName(name string) string {
+	if variable, err := d.getFlagVariable(name); err == nil {
+		return variable.Name
+	}
+	return name
+}
+
+// DefaultsOption configures how defaults are applied to variables
+type DefaultsOption func(*defaultsOptions)
+
+type defaultsOptions struct {
+	failFast bool
+	persist  bool
+}
+
+// FailFast makes applying defaults stop at the first variable that fails instead of reporting every failure
+func FailFast() DefaultsOption {
+	return func(o *defaultsOptions) {
+		o.failFast = true
+	}
+}
+
+// PersistDefault makes GetVariableValueOrDefault store a resolved default as the variable's value
+func PersistDefault() DefaultsOption {
+	return func(o *defaultsOptions) {
+		o.persist = true
+	}
+}
+
+func newDefaultsOptions(opts []DefaultsOption) *defaultsOptions {
+	options := &defaultsOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// ApplyDefaultVariables will apply the defaults to variables that are not already set.
+// Every variable is visited and all failures are returned together unless FailFast is passed.
+func (d *DraftConfig) ApplyDefaultVariables(opts ...DefaultsOption) error {
+	options := newDefaultsOptions(opts)
+
+	sortedVariables, err := d.sortByReferenceVar()
+	if err != nil {
+		return fmt.Errorf("apply default variables: %w", err)
+	}
+
+	var errs []error
+	for _, variable := range sortedVariables {
+		if err := d.applyDefaultVariable(variable, nil); err != nil {
+			if options.failFast {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyDefaultVariablesForVersion will apply the defaults to variables that are not already set for a specific template version.
+// Every variable is visited and all failures are returned together unless FailFast is passed.
+func (d *DraftConfig) ApplyDefaultVariablesForVersion(version string, opts ...DefaultsOption) error {
+	options := newDefaultsOptions(opts)
+
+	version = NormalizeVersion(version)
+	v, err := semver.Parse(version)
+	if err != nil {
+		return fmt.Errorf("invalid version: %w", err)
+	}
+
+	if !ContainsVersion(d.Versions, version) {
+		return fmt.Errorf("requested version outside of valid versions: %s", version)
+	}
+
+	sortedVariables, err := d.sortByReferenceVar()
+	if err != nil {
+		return fmt.Errorf("apply default variables: %w", err)
+	}
+
+	var errs []error
+	for _, variable := range sortedVariables {
+		if variable.Value == "" {
+			inRange, err := variable.inVersionRange(v)
+			if err != nil {
+				if options.failFast {
+					return err
+				}
+				errs = append(errs, err)
+				continue
+			}
+
+			if !inRange {
+				log.Infof("Variable %s versions %s is outside input version %s, skipping", variable.Name, variable.Versions, version)
+				continue
+			}
+
+			if err := d.applyDefaultVariable(variable, &v); err != nil {
+				if options.failFast {
+					return err
+				}
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NormalizeVersion trims whitespace and a leading v from a template version, so v0.0.1 and 0.0.1 are the same version
+func NormalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') {
+		return version[1:]
+	}
+	return version
+}
+
+// ContainsVersion returns true if version is one of versions after both are normalized
+func ContainsVersion(versions []string, version string) bool {
+	version = NormalizeVersion(version)
+	return slices.ContainsFunc(versions, func(v string) bool {
+		return NormalizeVersion(v) == version
+	})
+}
+
+// inVersionRange returns true if the variable is used for template version v. An empty Versions range inherits the
+// template's versions, so the variable is used for every version.
+func (bv *BuilderVar) inVersionRange(v semver.Version) (bool, error) {
+	if strings.TrimSpace(bv.Versions) == "" {
+		return true, nil
+	}
+
+	expectedRange, err := semver.ParseRange(bv.Versions)
+	if err != nil {
+		return false, fmt.Errorf("variable %s has invalid versions range %q: %w", bv.Name, bv.Versions, err)
+	}
+
+	return expectedRange(v), nil
+}
+
+// CollectUnsetVariables returns the variables that still need a value, in declaration order. Variables with a value,
+// variables outside the versions range of version, inactive variables, and variables with prompts disabled whose
+// default can be resolved are left out. An empty version includes variables of every version.
+func (d *DraftConfig) CollectUnsetVariables(version string) ([]*BuilderVar, error) {
+	var v *semver.Version
+	if version != "" {
+		parsed, err := semver.Parse(NormalizeVersion(version))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version: %w", err)
+		}
+		v = &parsed
+	}
+
+	var unset []*BuilderVar
+	for _, variable := range d.Variables {
+		if variable.Value != "" {
+			continue
+		}
+
+		if v != nil {
+			inRange, err := variable.inVersionRange(*v)
+			if err != nil {
+				return nil, err
+			}
+			if !inRange {
+				continue
+			}
+		}
+
+		isVarActive, err := d.CheckActiveWhenConstraint(variable)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check ActiveWhen constraint: %w", err)
+		}
+		if !isVarActive {
+			continue
+		}
+
+		if variable.Default.IsPromptDisabled {
+			defaultVal, err := d.resolveDefaultValue(variable, v)
+			if err != nil {
+				return nil, err
+			}
+			if defaultVal != "" {
+				continue
+			}
+		}
+
+		unset = append(unset, variable)
+	}
+
+	return unset, nil
+}
+
+// ValidatePromptDisabledDefaults reports every required variable with prompts disabled whose default chain can't
+// produce a value, so the mistake is caught when the template is loaded instead of when defaults are applied. The check
+// doesn't read environment variables or files: a default from Default.FromFile counts as a value, while one from
+// Default.EnvVar alone does not. A reference to a required variable that is prompted for earlier counts as a value too.
+func (d *DraftConfig) ValidatePromptDisabledDefaults() error {
+	var emptyNames []string
+	for _, variable := range d.Variables {
+		if !variable.Default.IsPromptDisabled || !variable.IsRequired() {
+			continue
+		}
+
+		resolves, err := d.defaultResolves(variable)
+		if err != nil {
+			return fmt.Errorf("validate prompt disabled defaults: %w", err)
+		}
+		if !resolves {
+			emptyNames = append(emptyNames, variable.Name)
+		}
+	}
+
+	if len(emptyNames) > 0 {
+		return fmt.Errorf("prompts are disabled for variables with no default value: %s", strings.Join(emptyNames, ", "))
+	}
+
+	return nil
+}
+
+// defaultResolves returns true if variable has a value or ApplyDefaultVariables would find one for it, following the
+// same order of default sources
+func (d *DraftConfig) defaultResolves(variable *BuilderVar) (bool, error) {
+	if variable.Value != "" {
+		return true, nil
+	}
+
+	if variable.Default.ReferenceVar != "" {
+		referenceVar, err := d.GetVariable(variable.Default.ReferenceVar)
+		if err != nil {
+			return false, err
+		}
+		resolves, err := d.referenceResolves(referenceVar, []string{variable.Name})
+		if err != nil || resolves {
+			return resolves, err
+		}
+	}
+
+	if variable.Default.FromFile != "" || variable.Default.Value != "" || variable.Default.Generator != "" {
+		return true, nil
+	}
+
+	for _, versionedDefault := range variable.Default.VersionedDefaults {
+		if versionedDefault.Value != "" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// referenceResolves returns true if recurseReferenceVars would return a value for referenceVar
+func (d *DraftConfig) referenceResolves(referenceVar *BuilderVar, path []string) (bool, error) {
+	if slices.Contains(path, referenceVar.Name) {
+		cycle := append(slices.Clone(path), referenceVar.Name)
+		return false, fmt.Errorf("cyclical reference detected: %s", strings.Join(cycle, " → "))
+	}
+
+	if referenceVar.Value != "" || d.isPromptedBefore(referenceVar, path[0]) {
+		return true, nil
+	}
+
+	if referenceVar.Default.ReferenceVar != "" {
+		nextVar, err := d.GetVariable(referenceVar.Default.ReferenceVar)
+		if err != nil {
+			return false, err
+		}
+		return d.referenceResolves(nextVar, append(slices.Clone(path), referenceVar.Name))
+	}
+
+	return referenceVar.Default.FromFile != "" || referenceVar.Default.Value != "", nil
+}
+
+// isPromptedBefore returns true if referenceVar is always prompted for before the variable named name, so it has a value
+// by the time the default of that variable is applied
+func (d *DraftConfig) isPromptedBefore(referenceVar *BuilderVar, name string) bool {
+	if referenceVar.Default.IsPromptDisabled || !referenceVar.IsRequired() || len(referenceVar.ActiveWhenConstraints) > 0 {
+		return false
+	}
+
+	for _, variable := range d.Variables {
+		if variable.Name == name {
+			return false
+		}
+		if variable == referenceVar {
+			return true
+		}
+	}
+	return false
+}
+
+// PromptDisabledVariables returns the variables with prompts disabled whose ActiveWhen constraints currently hold, in
+// declaration order. Inactive variables are left out since they are neither prompted for nor defaulted.
+func (d *DraftConfig) PromptDisabledVariables() ([]*BuilderVar, error) {
+	var promptDisabled []*BuilderVar
+	for _, variable := range d.Variables {
+		if !variable.Default.IsPromptDisabled {
+			continue
+		}
+
+		isVarActive, err := d.CheckActiveWhenConstraint(variable)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check ActiveWhen constraint: %w", err)
+		}
+		if isVarActive {
+			promptDisabled = append(promptDisabled, variable)
+		}
+	}
+
+	return promptDisabled, nil
+}
+
+// resolveDefaultValue returns the value variable would be defaulted to without setting it. If version is not nil, a
+// matching entry in Default.VersionedDefaults is used when no reference or external default is available.
+func (d *DraftConfig) resolveDefaultValue(variable *BuilderVar, version *semver.Version) (string, error) {
+	if version != nil && variable.Default.ReferenceVar == "" {
+		externalVal, err := variable.externalDefaultValue()
+		if err != nil {
+			return "", err
+		}
+		if externalVal != "" {
+			return externalVal, nil
+		}
+
+		versionedVal, err := variable.Default.valueForVersion(*version)
+		if err != nil {
+			return "", fmt.Errorf("variable %s: %w", variable.Name, err)
+		}
+		if versionedVal != "" {
+			return d.interpolateDefaultValue(variable, versionedVal, []string{variable.Name})
+		}
+	}
+
+	return d.recurseReferenceVars(variable, nil)
+}
+
+// sortByReferenceVar orders the variables so that each one comes after the variable its Default.ReferenceVar points to
+// and the variables interpolated into its default values, letting defaults see the final value of their targets. Cycles are reported with the full chain.
+func (d *DraftConfig) sortByReferenceVar() ([]*BuilderVar, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[string]int)
+	sorted := make([]*BuilderVar, 0, len(d.Variables))
+
+	var visit func(variable *BuilderVar, path []string) error
+	visit = func(variable *BuilderVar, path []string) error {
+		switch state[variable.Name] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(path[slices.Index(path, variable.Name):], variable.Name)
+			return fmt.Errorf("cyclical reference detected: %s", strings.Join(cycle, " → "))
+		}
+
+		state[variable.Name] = visiting
+		dependencies := variable.defaultValueReferences()
+		if variable.Default.ReferenceVar != "" {
+			dependencies = append([]string{variable.Default.ReferenceVar}, dependencies...)
+		}
+		for _, dependency := range dependencies {
+			// a missing dependency is reported when the default is applied
+			if dependencyVar, err := d.GetVariable(dependency); err == nil {
+				if err := visit(dependencyVar, append(path, variable.Name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[variable.Name] = visited
+		sorted = append(sorted, variable)
+
+		return nil
+	}
+
+	for _, variable := range d.Variables {
+		if err := visit(variable, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// applyDefaultVariable applies the default to a single variable if it is not already set and is active.
+// If version is not nil, a matching entry in Default.VersionedDefaults takes precedence over Default.Value.
+func (d *DraftConfig) applyDefaultVariable(variable *BuilderVar, version *semver.Version) error {
+	if variable.Value != "" {
+		return nil
+	}
+
+	isVarActive, err := d.CheckActiveWhenConstraint(variable)
+	if err != nil {
+		return fmt.Errorf("unable to check ActiveWhen constraint: %w", err)
+	}
+
+	if !isVarActive {
+		log.Debugf("Variable %s is inactive, skipping default", variable.Name)
+		return nil
+	}
+
+	source := SourceDefault
+	if variable.Default.ReferenceVar != "" {
+		source = SourceReferenceVar
+		referenceVar, err := d.GetVariable(variable.Default.ReferenceVar)
+		if err != nil {
+			return fmt.Errorf("apply default variables: %w", err)
+		}
+		defaultVal, err := d.recurseReferenceVars(referenceVar, []string{variable.Name})
+		if err != nil {
+			return fmt.Errorf("apply default variables: %w", err)
+		}
+		defaultVal, err = d.transformReferenceValue(variable, defaultVal, []string{variable.Name})
+		if err != nil {
+			return fmt.Errorf("apply default variables: %w", err)
+		}
+		logValue := defaultVal
+		if variable.Sensitive || referenceVar.Sensitive {
+			logValue = redactedValue
+		}
+		log.Infof("Variable %s defaulting to value %s", variable.Name, logValue)
+		variable.Value = defaultVal
+	}
+
+	if variable.Value == "" {
+		externalVal, err := variable.externalDefaultValue()
+		if err != nil {
+			return fmt.Errorf("apply default variables: %w", err)
+		}
+		if externalVal != "" {
+			log.Infof("Variable %s defaulting to value %s", variable.Name, variable.redact(externalVal))
+			variable.Value = externalVal
+		}
+	}
+
+	if variable.Value == "" {
+		defaultVal := variable.Default.Value
+		if version != nil {
+			versionedVal, err := variable.Default.valueForVersion(*version)
+			if err != nil {
+				return fmt.Errorf("apply default variables: variable %s: %w", variable.Name, err)
+			}
+			if versionedVal != "" {
+				defaultVal = versionedVal
+			}
+		}
+
+		defaultVal, err := d.interpolateDefaultValue(variable, defaultVal, []string{variable.Name})
+		if err != nil {
+			return fmt.Errorf("apply default variables: %w", err)
+		}
+
+		if defaultVal == "" && variable.Default.Generator != "" {
+			defaultVal, err = d.generateValue(variable)
+			if err != nil {
+				return fmt.Errorf("apply default variables: %w", err)
+			}
+		}
+
+		if defaultVal != "" {
+			log.Infof("Variable %s defaulting to value %s", variable.Name, variable.redact(defaultVal))
+			variable.Value = defaultVal
+		} else if !variable.IsRequired() {
+			log.Debugf("Variable %s is optional and has no default value, leaving it empty", variable.Name)
+			return nil
+		} else {
+			return errors.New("variable " + variable.Name + " has no default value")
+		}
+	}
+
+	typedValue, err := normalizeTypedValue(variable, variable.Value)
+	if err != nil {
+		return fmt.Errorf("apply default variables: %w", err)
+	}
+	variable.Value = typedValue
+
+	if _, err := d.variableValue(variable, variable.Value); err != nil {
+		variable.Value = ""
+		return fmt.Errorf("apply default variables: variable %s has invalid %s: %w", variable.Name, source.describe(), err)
+	}
+
+	d.setVariableSource(variable.Name, source)
+	d.record(variable)
+
+	return nil
+}
+
+func (d *DraftConfig) CheckActiveWhenConstraint(variable *BuilderVar) (bool, error) {
+	for _, activeWhen := range variable.ActiveWhenConstraints {
+		holds, err := d.constraintHolds(activeWhen)
+		if err != nil {
+			return false, err
+		}
+
+		// every constraint must hold for the variable to be active
+		if !holds {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// constraintHolds compares the value of the constraint's variable, or its default, with the constraint's value
+func (d *DraftConfig) constraintHolds(activeWhen ActiveWhenConstraint) (bool, error) {
+	refVar, err := d.GetVariable(activeWhen.VariableName)
+	if err != nil {
+		return false, fmt.Errorf("unable to get ActiveWhen reference variable: %w", err)
+	}
+
+	checkValue := refVar.Value
+	if checkValue == "" {
+		if refVar.Default.Value != "" {
+			checkValue = refVar.Default.Value
+		}
+
+		if refVar.Default.ReferenceVar != "" {
+			refValue, err := d.recurseReferenceVars(refVar, nil)
+			if err != nil {
+				return false, err
+			}
+			if refValue == "" {
+				return false, errors.New("reference variable has no value")
+			}
+
+			checkValue = refValue
+		}
+	}
+
+	// compare typed values in their normalized form so that e.g. "True" matches "true"
+	conditionValue := activeWhen.Value
+	if normalizedCheckValue, err := normalizeTypedValue(refVar, checkValue); err == nil {
+		checkValue = normalizedCheckValue
+	}
+	if normalizedConditionValue, err := normalizeTypedValue(refVar, conditionValue); err == nil {
+		conditionValue = normalizedConditionValue
+	}
+
+	switch VariableCondition(strings.ToLower(activeWhen.Condition.String())) {
+	case EqualTo:
+		return checkValue == conditionValue, nil
+	case NotEqualTo:
+		return checkValue != conditionValue, nil
+	default:
+		return false, fmt.Errorf("invalid activeWhen condition: %s", activeWhen.Condition)
+	}
+}
+
+// transformReferenceValue passes a value taken from variable's reference variable through variable's transformer when
+// Default.TransformReference is set. Otherwise a value taken from a displayName variable by a variable of another kind
+// is passed through the slug transformer. The transformer must produce a string. path holds the names of the variables
+// whose values are being resolved.
+func (d *DraftConfig) transformReferenceValue(variable *BuilderVar, value string, path []string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+
+	kind := variable.Kind
+	transformer := d.variableTransformerOnPath(variable, path)
+	if !variable.Default.TransformReference {
+		if !d.referencesDisplayName(variable) {
+			return value, nil
+		}
+		kind = slugKind
+		transformer = d.GetVariableTransformer(slugKind)
+	}
+
+	transformed, err := transformer(value)
+	if err != nil {
+		return "", fmt.Errorf("variable %s: transforming value of reference variable %s: %w", variable.Name, variable.Default.ReferenceVar, err)
+	}
+
+	transformedVal, ok := transformed.(string)
+	if !ok {
+		return "", fmt.Errorf("variable %s: transformer for kind %s returned %T, not a string", variable.Name, kind, transformed)
+	}
+
+	return transformedVal, nil
+}
+
+// referencesDisplayName returns true if variable takes its default from a displayName variable without being one
+func (d *DraftConfig) referencesDisplayName(variable *BuilderVar) bool {
+	if variable.Kind == displayNameKind {
+		return false
+	}
+
+	referenceVar, err := d.GetVariable(variable.Default.ReferenceVar)
+	return err == nil && referenceVar.Kind == displayNameKind
+}
+
+// recurseReferenceVars recursively checks each variable's ReferenceVar if it doesn't have a custom input. If there's no more ReferenceVars, it will return the default value of the last ReferenceVar.
+// path holds the names of the variables already traversed so that any cycle in the chain is detected and reported.
+func (d *DraftConfig) recurseReferenceVars(referenceVar *BuilderVar, path []string) (string, error) {
+	if slices.Contains(path, referenceVar.Name) {
+		cycle := append(slices.Clone(path), referenceVar.Name)
+		return "", fmt.Errorf("cyclical reference detected: %s", strings.Join(cycle, " → "))
+	}
+	path = append(slices.Clone(path), referenceVar.Name)
+
+	// If referenceVar has a custom value, return it, else check its ReferenceVar, else its EnvVar or FromFile, else return its default value
+	if referenceVar.Value != "" {
+		return referenceVar.Value, nil
+	} else if referenceVar.Default.ReferenceVar != "" {
+		referringVar := referenceVar
+		referenceVar, err := d.GetVariable(referenceVar.Default.ReferenceVar)
+		if err != nil {
+			return "", fmt.Errorf("recurse reference vars: %w", err)
+		}
+
+		referenceVal, err := d.recurseReferenceVars(referenceVar, path)
+		if err != nil {
+			return "", err
+		}
+
+		return d.transformReferenceValue(referringVar, referenceVal, path)
+	}
+
+	externalVal, err := referenceVar.externalDefaultValue()
+	if err != nil {
+		return "", fmt.Errorf("recurse reference vars: %w", err)
+	}
+	if externalVal != "" {
+		return externalVal, nil
+	}
+
+	return d.interpolateDefaultValue(referenceVar, referenceVar.Default.Value, path)
+}
+
+// handles flags that are meant to represent template variables. Flags may use a variable's aliases, but setting an alias
+// and its canonical name to different values is an error.
+func (d *DraftConfig) VariableMapToDraftConfig(flagVariablesMap map[string]string) error {
+	flagNames := make([]string, 0, len(flagVariablesMap))
+	for flagName := range flagVariablesMap {
+		flagNames = append(flagNames, flagName)
+	}
+	slices.Sort(flagNames)
+
+	canonicalFlags := make(map[string]string)
+	for _, flagName := range flagNames {
+		canonicalName := flagName
+		if variable, err := d.getFlagVariable(flagName); err == nil {
+			canonicalName = variable.Name
+		}
+
+		if otherFlag, ok := canonicalFlags[canonicalName]; ok && flagVariablesMap[otherFlag] != flagVariablesMap[flagName] {
+			return fmt.Errorf("conflicting values for variable %s set through both %s and %s", canonicalName, otherFlag, flagName)
+		}
+		canonicalFlags[canonicalName] = flagName
+	}
+
+	for _, flagName := range flagNames {
+		flagValue := flagVariablesMap[flagName]
+		logValue := flagValue
+		if variable, err := d.getFlagVariable(flagName); err == nil {
+			logValue = variable.redact(flagValue)
+			flagName = variable.Name
+		}
+		log.Debugf("flag variable %s=%s", flagName, logValue)
+		d.SetVariable(flagName, flagValue)
+	}
+
+	return nil
+}
+
+// SetFileNameOverride sets the filename override for a specific file
+func (d *DraftConfig) SetFileNameOverride(input, override string) {
+	if d.FileNameOverrideMap == nil {
+		d.FileNameOverrideMap = make(map[string]string)
+	}
+	d.FileNameOverrideMap[input] = override
+}
+
+// DeepCopy returns a copy of the draft config that shares no slices or maps with the original. Nil slices and maps stay nil.
+func (d *DraftConfig) DeepCopy() *DraftConfig {
+	newConfig := &DraftConfig{
+		TemplateName:           d.TemplateName,
+		DisplayName:            d.DisplayName,
+		Description:            d.Description,
+		Language:               d.Language,
+		Type:                   d.Type,
+		Versions:               slices.Clone(d.Versions),
+		VariableGroups:         slices.Clone(d.VariableGroups),
+		DefaultVersion:         d.DefaultVersion,
+		Labels:                 maps.Clone(d.Labels),
+		FileNameOverrideMap:    maps.Clone(d.FileNameOverrideMap),
+		Destinations:           maps.Clone(d.Destinations),
+		ConditionalFiles:       cloneConditionalFiles(d.ConditionalFiles),
+		RawFiles:               slices.Clone(d.RawFiles),
+		Validators:             maps.Clone(d.Validators),
+		Transformers:           maps.Clone(d.Transformers),
+		VariableValidators:     maps.Clone(d.VariableValidators),
+		VariableTransformers:   maps.Clone(d.VariableTransformers),
+		CaseSensitiveVariables: d.CaseSensitiveVariables,
+		LeftDelim:              d.LeftDelim,
+		RightDelim:             d.RightDelim,
+		recorder:               d.recorder,
+		variableSources:        maps.Clone(d.variableSources),
+		generatorSource:        d.generatorSource,
+		usedVariables:          maps.Clone(d.usedVariables),
+	}
+
+	if d.Variables != nil {
+		newConfig.Variables = make([]*BuilderVar, len(d.Variables))
+		for i, variable := range d.Variables {
+			newConfig.Variables[i] = variable.DeepCopy()
+		}
+	}
+
+	return newConfig
+}
+
+// Equal returns true if other holds the same values as d. Validators and transformers are compared by function identity
+// and the shared store binding, generator source and tracked variable usage are ignored.
+func (d *DraftConfig) Equal(other *DraftConfig) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+
+	if !funcMapsEqual(d.Validators, other.Validators) || !funcMapsEqual(d.Transformers, other.Transformers) ||
+		!funcMapsEqual(d.VariableValidators, other.VariableValidators) || !funcMapsEqual(d.VariableTransformers, other.VariableTransformers) {
+		return false
+	}
+
+	dCopy, otherCopy := *d, *other
+	dCopy.Validators, dCopy.Transformers, dCopy.VariableValidators, dCopy.VariableTransformers = nil, nil, nil, nil
+	otherCopy.Validators, otherCopy.Transformers, otherCopy.VariableValidators, otherCopy.VariableTransformers = nil, nil, nil, nil
+	dCopy.sharedStore, dCopy.generatorSource, dCopy.usedVariables = nil, nil, nil
+	otherCopy.sharedStore, otherCopy.generatorSource, otherCopy.usedVariables = nil, nil, nil
+
+	return reflect.DeepEqual(dCopy, otherCopy)
+}
+
+// funcMapsEqual returns true if both maps have the same keys mapped to the same functions
+func funcMapsEqual[F any](a, b map[string]F) bool {
+	if len(a) != len(b) || (a == nil) != (b == nil) {
+		return false
+	}
+
+	for k, aFunc := range a {
+		bFunc, ok := b[k]
+		if !ok || reflect.ValueOf(aFunc).Pointer() != reflect.ValueOf(bFunc).Pointer() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DeepCopy returns a copy of the variable that shares no slices with the original
+func (bv *BuilderVar) DeepCopy() *BuilderVar {
+	newVar := &BuilderVar{
+		Name:              bv.Name,
+		Aliases:           slices.Clone(bv.Aliases),
+		Deprecated:        bv.Deprecated,
+		Group:             bv.Group,
+		Default:           *bv.Default.DeepCopy(),
+		Description:       bv.Description,
+		ExampleValues:     slices.Clone(bv.ExampleValues),
+		AllowedValues:     slices.Clone(bv.AllowedValues),
+		Type:              bv.Type,
+		Kind:              bv.Kind,
+		Transform:         bv.Transform,
+		TransformTemplate: bv.TransformTemplate,
+		Pattern:           bv.Pattern,
+		ErrorMessage:      bv.ErrorMessage,
+		MaxRenderedLength: bv.MaxRenderedLength,
+		Sensitive:         bv.Sensitive,
+		Value:             bv.Value,
+		Versions:          bv.Versions,
+		transformTemplate: bv.transformTemplate,
+		pattern:           bv.pattern,
+	}
+
+	if bv.Required != nil {
+		required := *bv.Required
+		newVar.Required = &required
+	}
+
+	if bv.ActiveWhenConstraints != nil {
+		newVar.ActiveWhenConstraints = make([]ActiveWhenConstraint, len(bv.ActiveWhenConstraints))
+		for i, awc := range bv.ActiveWhenConstraints {
+			newVar.ActiveWhenConstraints[i] = *awc.DeepCopy()
+		}
+	}
+
+	return newVar
+}
+
+func (bd BuilderVarDefault) DeepCopy() *BuilderVarDefault {
+	newDefault := bd
+	newDefault.VersionedDefaults = slices.Clone(bd.VersionedDefaults)
+	return &newDefault
+}
+
+func (awc ActiveWhenConstraint) DeepCopy() *ActiveWhenConstraint {
+	return &ActiveWhenConstraint{
+		VariableName: awc.VariableName,
+		Value:        awc.Value,
+		Condition:    awc.Condition,
+	}
+}
+
+// valueForVersion returns the value of the VersionedDefaults entry whose range contains version, or an empty string if none does
+func (bd BuilderVarDefault) valueForVersion(version semver.Version) (string, error) {
+	for _, versionedDefault := range bd.VersionedDefaults {
+		versionRange, err := semver.ParseRange(versionedDefault.Versions)
+		if err != nil {
+			return "", fmt.Errorf("invalid versioned default range %s: %w", versionedDefault.Versions, err)
+		}
+
+		if versionRange(version) {
+			return versionedDefault.Value, nil
+		}
+	}
+
+	return "", nil
+}
+
+// checkVersionedDefaults returns an error if any VersionedDefaults range is invalid or if two ranges overlap on one of the template versions
+func (bv *BuilderVar) checkVersionedDefaults(templateVersions []string) error {
+	versionRanges := make([]semver.Range, len(bv.Default.VersionedDefaults))
+	for i, versionedDefault := range bv.Default.VersionedDefaults {
+		versionRange, err := semver.ParseRange(versionedDefault.Versions)
+		if err != nil {
+			return fmt.Errorf("variable %s has invalid versioned default range %s: %w", bv.Name, versionedDefault.Versions, err)
+		}
+		versionRanges[i] = versionRange
+	}
+
+	for _, templateVersion := range templateVersions {
+		v, err := semver.Parse(templateVersion)
+		if err != nil {
+			continue
+		}
+
+		matchingRanges := []string{}
+		for i, versionRange := range versionRanges {
+			if versionRange(v) {
+				matchingRanges = append(matchingRanges, bv.Default.VersionedDefaults[i].Versions)
+			}
+		}
+
+		if len(matchingRanges) > 1 {
+			return fmt.Errorf("variable %s has overlapping versioned default ranges for version %s: %s", bv.Name, templateVersion, strings.Join(matchingRanges, ", "))
+		}
+	}
+
+	return nil
+}
+
+// externalDefaultValue returns the default value read from Default.EnvVar or Default.FromFile, in that order.
+// An empty string is returned when neither source provides a value.
+func (bv *BuilderVar) externalDefaultValue() (string, error) {
+	if bv.Default.EnvVar != "" {
+		if envValue := os.Getenv(bv.Default.EnvVar); envValue != "" {
+			return envValue, nil
+		}
+	}
+
+	if bv.Default.FromFile != "" {
+		fileBytes, err := os.ReadFile(bv.Default.FromFile)
+		if err != nil {
+			return "", fmt.Errorf("reading default value for variable %s from file %s: %w", bv.Name, bv.Default.FromFile, err)
+		}
+
+		fileValue := string(fileBytes)
+		if bv.Default.TrimNewline {
+			fileValue = strings.TrimRight(fileValue, "\r\n")
+		}
+		return fileValue, nil
+	}
+
+	return "", nil
+}
+
+// redact returns value, or a placeholder if the variable is sensitive
+func (bv *BuilderVar) redact(value string) string {
+	if bv.Sensitive {
+		return redactedValue
+	}
+	return value
+}
+
+// redactError returns err with value replaced by a placeholder if the variable is sensitive, since validators and
+// transformers may include the value in their errors
+func (bv *BuilderVar) redactError(err error, value string) error {
+	if !bv.Sensitive || value == "" || !strings.Contains(err.Error(), value) {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), value, redactedValue))
+}
+
+// TemplateVariableRecorder is an interface for recording variables that are read using draft configs
+type TemplateVariableRecorder interface {
+	Record(key, value string)
+}
+
+// SensitiveVariableRecorder is implemented by recorders that opt in to receiving the real values of sensitive variables
+type SensitiveVariableRecorder interface {
+	TemplateVariableRecorder
+	RecordsSensitiveValues() bool
+}
+
+// RecordVariable records the variable's value with recorder, redacting sensitive values unless the recorder opts in
+func RecordVariable(recorder TemplateVariableRecorder, variable *BuilderVar) {
+	if sensitiveRecorder, ok := recorder.(SensitiveVariableRecorder); ok && sensitiveRecorder.RecordsSensitiveValues() {
+		recorder.Record(variable.Name, variable.Value)
+		return
+	}
+	recorder.Record(variable.Name, variable.redact(variable.Value))
+}
+
+// normalizeTypedValue checks value against the variable's declared type, returning it in canonical form.
+// bool values are normalized to "true" or "false" and int values have leading zeros removed.
+func normalizeTypedValue(variable *BuilderVar, value string) (string, error) {
+	switch variable.Type {
+	case "bool":
+		if boolValue, err := validators.ParseBoolean(strings.TrimSpace(value)); err == nil {
+			return strconv.FormatBool(boolValue), nil
+		}
+	case "int":
+		intValue, err := strconv.Atoi(strings.TrimSpace(value))
+		if err == nil {
+			return strconv.Itoa(intValue), nil
+		}
+	default:
+		return value, nil
+	}
+
+	return "", &VariableTypeError{Name: variable.Name, Type: variable.Type, Value: variable.redact(value)}
+}
+
+// compilePattern compiles the variable's Pattern, keeping the result so that the pattern compiled while the config is
+// loaded is reused for every value. It returns nil if the variable has no pattern.
+func (bv *BuilderVar) compilePattern() (*regexp.Regexp, error) {
+	if bv.Pattern == "" {
+		return nil, nil
+	}
+	if bv.pattern != nil && bv.pattern.source == bv.Pattern {
+		return bv.pattern.regexp, nil
+	}
+
+	compiled, err := regexp.Compile(bv.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("variable %s: invalid pattern: %w", bv.Name, err)
+	}
+
+	bv.pattern = &compiledPattern{source: bv.Pattern, regexp: compiled}
+	return compiled, nil
+}
+
+// checkPattern returns an error if the variable has a pattern that value does not match
+func checkPattern(variable *BuilderVar, value string) error {
+	pattern, err := variable.compilePattern()
+	if err != nil || pattern == nil {
+		return err
+	}
+
+	if !pattern.MatchString(value) {
+		return fmt.Errorf("invalid value %q for variable %s: does not match pattern %s", variable.redact(value), variable.Name, variable.Pattern)
+	}
+
+	return nil
+}
+
+// checkAllowedValue returns an error if the variable restricts its values with AllowedValues and value is not one of them
+func checkAllowedValue(variable *BuilderVar, value string) error {
+	if len(variable.AllowedValues) == 0 || slices.Contains(variable.AllowedValues, value) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid value %q for variable %s. allowed values: %s", variable.redact(value), variable.Name, strings.Join(variable.AllowedValues, ", "))
+}
+
+// splitListValue splits a comma-separated array variable value into its trimmed, non-empty items
+func splitListValue(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, listValueSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
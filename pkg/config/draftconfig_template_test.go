@@ -30,6 +30,7 @@ var validVariableTypes = map[string]bool{
 	"int":    true,
 	"float":  true,
 	"object": true,
+	"array":  true,
 }
 var validVariableKinds = map[string]bool{
 	"azureContainerRegistry":     true,
@@ -45,6 +46,7 @@ var validVariableKinds = map[string]bool{
 	"envVarMap":                  true,
 	"filePath":                   true,
 	"flag":                       true,
+	"generated":                  true,
 	"helmChartOverrides":         true,
 	"imagePullPolicy":            true,
 	"ingressHostName":            true,
@@ -57,6 +59,55 @@ var validVariableKinds = map[string]bool{
 	"kubernetesProbeDelay":       true,
 	"kubernetesResourceLimit":    true,
 	"kubernetesResourceName":     true,
+	"kubernetesSubdomainName":    true,
+	"containerPort":              true,
+	"imageReference":             true,
+	"imageTag":                   true,
+	"imageTagStrict":             true,
+	"acrLoginServer":             true,
+	"acrName":                    true,
+	"acrNameOrLoginServer":       true,
+	"guid":                       true,
+	"hostname":                   true,
+	"wildcardHostname":           true,
+	"url":                        true,
+	"httpsUrl":                   true,
+	"semver":                     true,
+	"cronSchedule":               true,
+	"resourceQuantity":           true,
+	"cpuQuantity":                true,
+	"memoryQuantity":             true,
+	"duration":                   true,
+	"durationStrict":             true,
+	"ipAddress":                  true,
+	"cidr":                       true,
+	"repoRelativePath":           true,
+	"existingPath":               true,
+	"gitRef":                     true,
+	"helmReleaseName":            true,
+	"storageAccountName":         true,
+	"boolean":                    true,
+	"integer":                    true,
+	"jsonValue":                  true,
+	"yamlValue":                  true,
+	"environmentName":            true,
+	"kubeName":                   true,
+	"base64Encode":               true,
+	"base64Decode":               true,
+	"yamlQuote":                  true,
+	"envExpand":                  true,
+	"lowercase":                  true,
+	"trimSpace":                  true,
+	"normalize":                  true,
+	"slug":                       true,
+	"imageRefNormalize":          true,
+	"portNormalize":              true,
+	"cpuNormalize":               true,
+	"memoryNormalize":            true,
+	"truncateHash":               true,
+	"displayName":                true,
+	"kubernetesLabelKey":         true,
+	"kubernetesLabelValue":       true,
 	"kubernetesResourceRequest":  true,
 	"label":                      true,
 	"port":                       true,
@@ -128,6 +179,10 @@ func loadTemplatesWithValidation() error {
 			}
 		}
 
+		if err := currTemplate.ValidatePromptDisabledDefaults(); err != nil {
+			return fmt.Errorf("template %s: %w", path, err)
+		}
+
 		referenceVarMap := map[string]*BuilderVar{}
 		activeWhenRefMap := map[string]*BuilderVar{}
 		allVariables := map[string]*BuilderVar{}
@@ -140,8 +195,10 @@ func loadTemplatesWithValidation() error {
 				return fmt.Errorf("template %s has an invalid variable(%s) type: %s", path, variable.Name, variable.Type)
 			}
 
-			if _, ok := validVariableKinds[variable.Kind]; !ok {
-				return fmt.Errorf("template %s has an invalid variable kind: %s", path, variable.Kind)
+			for _, kind := range chainedKinds(variable.Kind) {
+				if _, ok := validVariableKinds[kind]; !ok {
+					return fmt.Errorf("template %s has an invalid variable kind: %s", path, kind)
+				}
 			}
 
 			if _, err := semver.ParseRange(variable.Versions); err != nil {
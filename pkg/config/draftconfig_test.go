@@ -1,7 +1,24 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+
+	"github.com/Azure/draft/pkg/config/transformers"
+	"github.com/Azure/draft/pkg/config/validators"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
 )
 
 func TestApplyDefaultVariables(t *testing.T) {
@@ -97,7 +114,7 @@ func TestApplyDefaultVariables(t *testing.T) {
 				},
 			},
 			want:       map[string]string{},
-			wantErrMsg: "variable var1 has no default value",
+			wantErrMsg: "variable var1 has no default value\nvariable var2 has no default value",
 		},
 		{
 			testName: "getDefaultFromReferenceVarCustomInputs",
@@ -175,7 +192,7 @@ func TestApplyDefaultVariables(t *testing.T) {
 				},
 			},
 			want:       map[string]string{},
-			wantErrMsg: "apply default variables: cyclical reference detected",
+			wantErrMsg: "apply default variables: cyclical reference detected: var1 → var2 → var1",
 		},
 	}
 	for _, tt := range tests {
@@ -365,3 +382,1593 @@ func TestApplyDefaultVariablesForVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestArrayVariables(t *testing.T) {
+	configYaml := `
+variables:
+  - name: "INGRESSHOSTS"
+    type: "array"
+    default:
+      value:
+        - "a.example.com"
+        - "b.example.com"
+  - name: "PORTS"
+    type: "array"
+    default:
+      value: "80"
+`
+	var draftConfig DraftConfig
+	assert.Nil(t, yaml.Unmarshal([]byte(configYaml), &draftConfig))
+
+	hostsVar, err := draftConfig.GetVariable("INGRESSHOSTS")
+	assert.Nil(t, err)
+	assert.Equal(t, "a.example.com,b.example.com", hostsVar.Default.Value)
+
+	draftConfig.SetVariable("PORTS", "80, 443,")
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+
+	hosts, err := draftConfig.GetVariableValues("INGRESSHOSTS")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, hosts)
+
+	ports, err := draftConfig.GetVariableValues("PORTS")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"80", "443"}, ports)
+
+	joinedPorts, err := draftConfig.GetVariableValue("PORTS")
+	assert.Nil(t, err)
+	assert.Equal(t, "80,443", joinedPorts)
+
+	draftConfig.SetVariable("APPNAME", "my-app")
+	_, err = draftConfig.GetVariableValues("APPNAME")
+	assert.NotNil(t, err)
+}
+
+func TestArrayDefaultStrictUnmarshal(t *testing.T) {
+	configYaml := `
+variables:
+  - name: "INGRESSHOSTS"
+    type: "array"
+    default:
+      value:
+        - "a.example.com"
+      disablPrompt: true
+`
+	var draftConfig DraftConfig
+	err := yaml.UnmarshalStrict([]byte(configYaml), &draftConfig)
+	assert.ErrorContains(t, err, "field disablPrompt not found")
+
+	draftConfig = DraftConfig{}
+	assert.Nil(t, yaml.Unmarshal([]byte(configYaml), &draftConfig))
+	assert.Equal(t, "a.example.com", draftConfig.Variables[0].Default.Value)
+
+	draftConfig = DraftConfig{}
+	assert.Nil(t, yaml.UnmarshalStrict([]byte(strings.Replace(configYaml, "disablPrompt", "disablePrompt", 1)), &draftConfig))
+	assert.True(t, draftConfig.Variables[0].Default.IsPromptDisabled)
+
+	// errors point at the line in draft.yaml
+	_, err = NewConfigFromBytes([]byte("templateName: \"hosts\""+configYaml), true)
+	assert.ErrorContains(t, err, `line 8: variables[0].default: unknown field "disablPrompt"`)
+}
+
+func TestBuilderVarDefaultYAMLFields(t *testing.T) {
+	defaultType := reflect.TypeOf(BuilderVarDefault{})
+	yamlType := reflect.TypeOf(builderVarDefaultYAML{})
+	assert.Equal(t, defaultType.NumField(), yamlType.NumField())
+	for i := range defaultType.NumField() {
+		assert.Equal(t, defaultType.Field(i).Name, yamlType.Field(i).Name)
+		assert.Equal(t, defaultType.Field(i).Tag, yamlType.Field(i).Tag)
+	}
+}
+
+func TestBoolTypeAndBooleanKindAgree(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "true", expected: "true"},
+		{input: "Yes", expected: "true"},
+		{input: "y", expected: "true"},
+		{input: "ON", expected: "true"},
+		{input: "1", expected: "true"},
+		{input: "false", expected: "false"},
+		{input: "No", expected: "false"},
+		{input: "n", expected: "false"},
+		{input: "off", expected: "false"},
+		{input: "0", expected: "false"},
+		{input: "enabled"},
+		{input: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			draftConfig := DraftConfig{
+				Variables: []*BuilderVar{
+					{Name: "TYPED", Type: "bool", Value: tt.input},
+					{Name: "KIND", Kind: "boolean", Value: tt.input},
+				},
+			}
+
+			typedValue, typedErr := draftConfig.GetVariableValue("TYPED")
+			kindValue, kindErr := draftConfig.GetVariableValue("KIND")
+			if tt.expected == "" {
+				assert.NotNil(t, typedErr)
+				assert.NotNil(t, kindErr)
+				return
+			}
+
+			assert.Nil(t, typedErr)
+			assert.Nil(t, kindErr)
+			assert.Equal(t, tt.expected, typedValue)
+			assert.Equal(t, tt.expected, kindValue)
+		})
+	}
+}
+
+func TestTypedVariables(t *testing.T) {
+	draftConfig := DraftConfig{
+		Versions: []string{"0.0.1"},
+		Variables: []*BuilderVar{
+			{Name: "ENABLED", Type: "bool", Value: "Yes"},
+			{Name: "DISABLED", Type: "bool", Value: "0"},
+			{Name: "PORT", Type: "int", Value: "0080"},
+			{Name: "BADBOOL", Type: "bool", Value: "maybe"},
+			{Name: "BADINT", Type: "int", Value: "80a"},
+			{Name: "NAME", Type: "string", Value: "my-app"},
+		},
+	}
+
+	enabled, err := draftConfig.GetVariableBool("ENABLED")
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	enabledValue, err := draftConfig.GetVariableValue("ENABLED")
+	assert.Nil(t, err)
+	assert.Equal(t, "true", enabledValue)
+
+	disabled, err := draftConfig.GetVariableBool("DISABLED")
+	assert.Nil(t, err)
+	assert.False(t, disabled)
+
+	port, err := draftConfig.GetVariableInt("PORT")
+	assert.Nil(t, err)
+	assert.Equal(t, 80, port)
+
+	var typeErr *VariableTypeError
+	_, err = draftConfig.GetVariableValue("BADBOOL")
+	assert.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "BADBOOL", typeErr.Name)
+	assert.Equal(t, "maybe", typeErr.Value)
+
+	_, err = draftConfig.GetVariableInt("BADINT")
+	assert.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "80a", typeErr.Value)
+
+	_, err = draftConfig.GetVariableInt("NAME")
+	assert.NotNil(t, err)
+
+	defaultedConfig := DraftConfig{
+		Versions: []string{"0.0.1"},
+		Variables: []*BuilderVar{
+			{Name: "REPLICAS", Type: "int", Versions: ">=0.0.1", Default: BuilderVarDefault{Value: "three"}},
+		},
+	}
+	err = defaultedConfig.ApplyDefaultVariablesForVersion("0.0.1")
+	assert.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "REPLICAS", typeErr.Name)
+}
+
+func TestAllowedValues(t *testing.T) {
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{
+				Name:          "SERVICETYPE",
+				Value:         "ClusterIP",
+				AllowedValues: []string{"ClusterIP", "LoadBalancer"},
+				ExampleValues: []string{"NodePort"},
+			},
+			{
+				Name:          "PROTOCOLS",
+				Type:          "array",
+				Value:         "TCP,SCTP",
+				AllowedValues: []string{"TCP", "UDP"},
+			},
+		},
+	}
+
+	value, err := draftConfig.GetVariableValue("SERVICETYPE")
+	assert.Nil(t, err)
+	assert.Equal(t, "ClusterIP", value)
+
+	// example values are advisory only
+	draftConfig.SetVariable("SERVICETYPE", "NodePort")
+	_, err = draftConfig.GetVariableValue("SERVICETYPE")
+	assert.EqualError(t, err, `invalid value "NodePort" for variable SERVICETYPE. allowed values: ClusterIP, LoadBalancer`)
+
+	_, err = draftConfig.GetVariableValues("PROTOCOLS")
+	assert.EqualError(t, err, `invalid value "SCTP" for variable PROTOCOLS. allowed values: TCP, UDP`)
+
+	allowedValues, err := draftConfig.GetVariableAllowedValues("SERVICETYPE")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ClusterIP", "LoadBalancer"}, allowedValues)
+
+	_, err = draftConfig.GetVariableAllowedValues("MISSING")
+	assert.NotNil(t, err)
+}
+
+func TestVariablePattern(t *testing.T) {
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{
+				Name:    "PROJECTCODE",
+				Pattern: "^[a-z]{3}-[0-9]{4}$",
+				Kind:    "imagePullPolicy",
+			},
+		},
+	}
+
+	draftConfig.SetVariable("PROJECTCODE", "ABC-1234")
+	_, err := draftConfig.GetVariableValue("PROJECTCODE")
+	assert.EqualError(t, err, `invalid value "ABC-1234" for variable PROJECTCODE: does not match pattern ^[a-z]{3}-[0-9]{4}$`)
+
+	// patterns compose with the kind validator
+	draftConfig.SetVariable("PROJECTCODE", "abc-1234")
+	_, err = draftConfig.GetVariableValue("PROJECTCODE")
+	assert.ErrorContains(t, err, "invalid image pull policy")
+
+	draftConfig.Variables[0].Kind = ""
+	value, err := draftConfig.GetVariableValue("PROJECTCODE")
+	assert.Nil(t, err)
+	assert.Equal(t, "abc-1234", value)
+
+	fileSys := fstest.MapFS{
+		"draft.yaml": &fstest.MapFile{Data: []byte(`
+templateName: "pattern-test"
+variables:
+  - name: "PROJECTCODE"
+    pattern: "^[a-z"
+`)},
+	}
+	_, err = NewConfigFromFS(fileSys, "draft.yaml")
+	assert.ErrorContains(t, err, "invalid draft config draft.yaml: variable PROJECTCODE: invalid pattern")
+
+	// the pattern is compiled while loading and shared by copies
+	fileSys["draft.yaml"].Data = []byte(`
+templateName: "pattern-test"
+variables:
+  - name: "PROJECTCODE"
+    pattern: "^[a-z]{3}-[0-9]{4}$"
+`)
+	loadedConfig, err := NewConfigFromFS(fileSys, "draft.yaml")
+	assert.Nil(t, err)
+	compiled := loadedConfig.Variables[0].pattern
+	assert.NotNil(t, compiled)
+	copiedConfig := loadedConfig.DeepCopy()
+	assert.Same(t, compiled, copiedConfig.Variables[0].pattern)
+	copiedConfig.SetVariable("PROJECTCODE", "abc-1234")
+	_, err = copiedConfig.GetVariableValue("PROJECTCODE")
+	assert.Nil(t, err)
+	assert.Same(t, compiled, copiedConfig.Variables[0].pattern)
+}
+
+type testRecorder struct {
+	values          map[string]string
+	recordSensitive bool
+}
+
+func (r *testRecorder) Record(key, value string) {
+	r.values[key] = value
+}
+
+func (r *testRecorder) RecordsSensitiveValues() bool {
+	return r.recordSensitive
+}
+
+func TestSensitiveVariables(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	log.SetLevel(log.DebugLevel)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetLevel(log.InfoLevel)
+	}()
+
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{
+				Name:      "PASSWORD",
+				Sensitive: true,
+				Default:   BuilderVarDefault{Value: "default-secret"},
+			},
+			{
+				Name:      "TOKEN",
+				Sensitive: true,
+				Default:   BuilderVarDefault{ReferenceVar: "SEED"},
+			},
+			{
+				Name:    "SEED",
+				Default: BuilderVarDefault{Value: "seed-secret"},
+			},
+			{
+				Name:      "REGISTRYKEY",
+				Sensitive: true,
+			},
+		},
+	}
+
+	assert.Nil(t, draftConfig.VariableMapToDraftConfig(map[string]string{"REGISTRYKEY": "flag-secret"}))
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+
+	output := logs.String()
+	assert.NotContains(t, output, "default-secret")
+	assert.NotContains(t, output, "flag-secret")
+	assert.Contains(t, output, "Variable PASSWORD defaulting to value ***")
+	assert.Contains(t, output, "Variable TOKEN defaulting to value ***")
+	assert.Contains(t, output, "flag variable REGISTRYKEY=***")
+
+	variableMap := draftConfig.GetVariableMap()
+	assert.Equal(t, "default-secret", variableMap["PASSWORD"])
+	assert.Equal(t, "seed-secret", variableMap["TOKEN"])
+
+	recorder := &testRecorder{values: map[string]string{}}
+	for _, variable := range draftConfig.Variables {
+		RecordVariable(recorder, variable)
+	}
+	assert.Equal(t, "***", recorder.values["PASSWORD"])
+	assert.Equal(t, "***", recorder.values["TOKEN"])
+	assert.Equal(t, "seed-secret", recorder.values["SEED"])
+
+	recorder.recordSensitive = true
+	RecordVariable(recorder, draftConfig.Variables[0])
+	assert.Equal(t, "default-secret", recorder.values["PASSWORD"])
+}
+
+func TestSensitiveVariableErrors(t *testing.T) {
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{
+				Name:      "TOKEN",
+				Sensitive: true,
+				Pattern:   "^ghp_[a-z0-9]+$",
+				Default:   BuilderVarDefault{Value: "gho_mistyped-secret"},
+			},
+		},
+	}
+
+	err := draftConfig.ApplyDefaultVariables()
+	assert.ErrorContains(t, err, `invalid value "***" for variable TOKEN: does not match pattern ^ghp_[a-z0-9]+$`)
+	assert.NotContains(t, err.Error(), "mistyped-secret")
+
+	token := draftConfig.Variables[0]
+	token.Pattern = ""
+	token.AllowedValues = []string{"ghp_allowed"}
+	token.Value = "ghp_other-secret"
+	_, err = draftConfig.GetVariableValue("TOKEN")
+	assert.EqualError(t, err, `invalid value "***" for variable TOKEN. allowed values: ghp_allowed`)
+
+	token.AllowedValues = nil
+	token.Kind = "boolean"
+	token.Value = "maybe-secret"
+	_, err = draftConfig.GetVariableValue("TOKEN")
+	assert.ErrorContains(t, err, "invalid boolean: ***")
+	assert.NotContains(t, err.Error(), "maybe-secret")
+
+	token.Kind = ""
+	token.Type = "int"
+	token.Value = "12-secret"
+	_, err = draftConfig.GetVariableValue("TOKEN")
+	assert.EqualError(t, err, `variable TOKEN of type int has invalid value: "***"`)
+}
+
+func TestSensitiveEncodedVariables(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{
+				Name:      "DBPASSWORD",
+				Kind:      "base64Encode",
+				Sensitive: true,
+				Default:   BuilderVarDefault{Value: "default-secret"},
+			},
+		},
+	}
+
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+	value, err := draftConfig.GetVariableValue("DBPASSWORD")
+	assert.Nil(t, err)
+	assert.Equal(t, "ZGVmYXVsdC1zZWNyZXQ=", value)
+
+	output := logs.String()
+	assert.Contains(t, output, "Variable DBPASSWORD defaulting to value ***")
+	assert.NotContains(t, output, "default-secret")
+	assert.NotContains(t, output, "ZGVmYXVsdC1zZWNyZXQ=")
+}
+
+func TestEnvVarDefaults(t *testing.T) {
+	t.Setenv("DRAFT_TEST_ACR_NAME", "myacr")
+	t.Setenv("DRAFT_TEST_EMPTY", "")
+
+	draftConfig := DraftConfig{
+		Versions: []string{"0.0.1", "0.0.2"},
+		Variables: []*BuilderVar{
+			{
+				Name:     "EXPLICIT",
+				Value:    "explicit-value",
+				Versions: ">=0.0.1",
+				Default:  BuilderVarDefault{EnvVar: "DRAFT_TEST_ACR_NAME", Value: "default-value"},
+			},
+			{
+				Name:     "REFERENCE",
+				Versions: ">=0.0.1",
+				Default:  BuilderVarDefault{ReferenceVar: "EXPLICIT", EnvVar: "DRAFT_TEST_ACR_NAME"},
+			},
+			{
+				Name:     "FROMENV",
+				Versions: ">=0.0.1",
+				Default:  BuilderVarDefault{EnvVar: "DRAFT_TEST_ACR_NAME", Value: "default-value"},
+			},
+			{
+				Name:     "EMPTYENV",
+				Versions: ">=0.0.1",
+				Default:  BuilderVarDefault{EnvVar: "DRAFT_TEST_EMPTY", Value: "default-value"},
+			},
+			{
+				Name:     "UNSETENV",
+				Versions: ">=0.0.1",
+				Default:  BuilderVarDefault{EnvVar: "DRAFT_TEST_UNSET", Value: "default-value"},
+			},
+			{
+				Name:     "NEWERVERSION",
+				Versions: ">=0.0.2",
+				Default:  BuilderVarDefault{EnvVar: "DRAFT_TEST_ACR_NAME"},
+			},
+			{
+				Name:     "REFERENCEENV",
+				Versions: ">=0.0.1",
+				Default:  BuilderVarDefault{ReferenceVar: "NEWERVERSION"},
+			},
+		},
+	}
+
+	assert.Nil(t, draftConfig.ApplyDefaultVariablesForVersion("0.0.1"))
+
+	want := map[string]string{
+		"EXPLICIT":     "explicit-value",
+		"REFERENCE":    "explicit-value",
+		"FROMENV":      "myacr",
+		"EMPTYENV":     "default-value",
+		"UNSETENV":     "default-value",
+		"NEWERVERSION": "",
+		"REFERENCEENV": "myacr",
+	}
+	assert.Equal(t, want, draftConfig.GetVariableMap())
+
+	assert.Nil(t, draftConfig.ApplyDefaultVariablesForVersion("0.0.2"))
+	newerVersion, err := draftConfig.GetVariable("NEWERVERSION")
+	assert.Nil(t, err)
+	assert.Equal(t, "myacr", newerVersion.Value)
+}
+
+func TestFromFileDefaults(t *testing.T) {
+	tempDir := t.TempDir()
+	caBundlePath := filepath.Join(tempDir, "ca.crt")
+	assert.Nil(t, os.WriteFile(caBundlePath, []byte("Y2EtYnVuZGxl\n"), 0644))
+	missingPath := filepath.Join(tempDir, "missing.txt")
+
+	tests := []struct {
+		testName   string
+		variables  []*BuilderVar
+		want       map[string]string
+		wantErrMsg string
+	}{
+		{
+			testName: "readFileKeepNewline",
+			variables: []*BuilderVar{
+				{Name: "CABUNDLE", Default: BuilderVarDefault{FromFile: caBundlePath}},
+			},
+			want: map[string]string{"CABUNDLE": "Y2EtYnVuZGxl\n"},
+		},
+		{
+			testName: "readFileTrimNewline",
+			variables: []*BuilderVar{
+				{Name: "CABUNDLE", Default: BuilderVarDefault{FromFile: caBundlePath, TrimNewline: true}},
+			},
+			want: map[string]string{"CABUNDLE": "Y2EtYnVuZGxl"},
+		},
+		{
+			testName: "explicitValueSkipsFile",
+			variables: []*BuilderVar{
+				{Name: "CABUNDLE", Value: "custom", Default: BuilderVarDefault{FromFile: missingPath}},
+			},
+			want: map[string]string{"CABUNDLE": "custom"},
+		},
+		{
+			testName: "referenceVarTakesPrecedence",
+			variables: []*BuilderVar{
+				{Name: "CABUNDLE", Default: BuilderVarDefault{ReferenceVar: "OTHER", FromFile: missingPath}},
+				{Name: "OTHER", Value: "from-reference"},
+			},
+			want: map[string]string{"CABUNDLE": "from-reference", "OTHER": "from-reference"},
+		},
+		{
+			testName: "referenceVarResolvesFile",
+			variables: []*BuilderVar{
+				{Name: "CABUNDLE", Default: BuilderVarDefault{ReferenceVar: "OTHER"}},
+				{Name: "OTHER", Default: BuilderVarDefault{FromFile: caBundlePath, TrimNewline: true}},
+			},
+			want: map[string]string{"CABUNDLE": "Y2EtYnVuZGxl", "OTHER": "Y2EtYnVuZGxl"},
+		},
+		{
+			testName: "missingFile",
+			variables: []*BuilderVar{
+				{Name: "CABUNDLE", Default: BuilderVarDefault{FromFile: missingPath, Value: "unused"}},
+			},
+			wantErrMsg: "apply default variables: reading default value for variable CABUNDLE from file " + missingPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			draftConfig := DraftConfig{Variables: tt.variables}
+			err := draftConfig.ApplyDefaultVariables()
+			if tt.wantErrMsg != "" {
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, draftConfig.GetVariableMap())
+		})
+	}
+}
+
+func TestActiveWhenConstraints(t *testing.T) {
+	newConfig := func(useTLS string) *DraftConfig {
+		return &DraftConfig{
+			Variables: []*BuilderVar{
+				{
+					Name:  "USETLS",
+					Type:  "bool",
+					Value: useTLS,
+				},
+				{
+					Name: "CERTSECRETNAME",
+					ActiveWhenConstraints: []ActiveWhenConstraint{
+						{VariableName: "USETLS", Value: "true", Condition: EqualTo},
+					},
+					Default: BuilderVarDefault{ReferenceVar: "APPNAME"},
+				},
+				{
+					Name: "HTTPPORT",
+					ActiveWhenConstraints: []ActiveWhenConstraint{
+						{VariableName: "USETLS", Value: "true", Condition: "notEquals"},
+						{VariableName: "APPNAME", Value: "my-app", Condition: EqualTo},
+					},
+					Default: BuilderVarDefault{Value: "80"},
+				},
+				{
+					Name:  "APPNAME",
+					Value: "my-app",
+				},
+			},
+		}
+	}
+
+	inactiveConfig := newConfig("False")
+	assert.Nil(t, inactiveConfig.ApplyDefaultVariables())
+	certSecretName, err := inactiveConfig.GetVariable("CERTSECRETNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "", certSecretName.Value)
+	_, err = inactiveConfig.GetVariableValue("CERTSECRETNAME")
+	assert.True(t, errors.Is(err, ErrVariableInactive))
+	value, err := inactiveConfig.GetVariableValue("HTTPPORT")
+	assert.Nil(t, err)
+	assert.Equal(t, "80", value)
+
+	activeConfig := newConfig("True")
+	assert.Nil(t, activeConfig.ApplyDefaultVariables())
+	value, err = activeConfig.GetVariableValue("CERTSECRETNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app", value)
+	// every constraint must hold, USETLS notEquals true fails
+	_, err = activeConfig.GetVariableValue("HTTPPORT")
+	assert.True(t, errors.Is(err, ErrVariableInactive))
+}
+
+func TestVersionedDefaults(t *testing.T) {
+	configYaml := `
+templateName: "versioned-defaults"
+versions: ["0.0.1", "0.0.2", "0.0.3", "0.0.4"]
+variables:
+  - name: "IMAGETAG"
+    versions: ">=0.0.1"
+    default:
+      value: "latest"
+      versionedDefaults:
+        - versions: "0.0.1"
+          value: "1.0"
+        - versions: ">=0.0.2 <0.0.3"
+          value: "2.0"
+        - versions: "0.0.3"
+          value: "3.0"
+`
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "0.0.1", want: "1.0"},
+		{version: "0.0.2", want: "2.0"},
+		{version: "0.0.3", want: "3.0"},
+		{version: "0.0.4", want: "latest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			draftConfig, err := NewConfigFromFS(fstest.MapFS{"draft.yaml": &fstest.MapFile{Data: []byte(configYaml)}}, "draft.yaml")
+			assert.Nil(t, err)
+			assert.Nil(t, draftConfig.ApplyDefaultVariablesForVersion(tt.version))
+
+			variable, err := draftConfig.GetVariable("IMAGETAG")
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, variable.Value)
+		})
+	}
+
+	overlappingYaml := `
+templateName: "overlapping-defaults"
+versions: ["0.0.1", "0.0.2"]
+variables:
+  - name: "IMAGETAG"
+    versions: ">=0.0.1"
+    default:
+      versionedDefaults:
+        - versions: ">=0.0.1"
+          value: "1.0"
+        - versions: ">=0.0.2"
+          value: "2.0"
+`
+	_, err := NewConfigFromFS(fstest.MapFS{"draft.yaml": &fstest.MapFile{Data: []byte(overlappingYaml)}}, "draft.yaml")
+	assert.EqualError(t, err, "invalid draft config draft.yaml: variable IMAGETAG has overlapping versioned default ranges for version 0.0.2: >=0.0.1, >=0.0.2")
+}
+
+func TestValidate(t *testing.T) {
+	invalidYaml := `
+versions: ["0.0.1", "latest"]
+defaultVersion: "0.0.2"
+variables:
+  - name: ""
+    kind: "port"
+  - name: "IMAGENAME"
+    kind: "containerImageNmae"
+    versions: ">>0.0.1"
+`
+	fileSys := fstest.MapFS{"draft.yaml": &fstest.MapFile{Data: []byte(invalidYaml)}}
+
+	_, err := NewConfigFromFS(fileSys, "draft.yaml")
+	assert.NotNil(t, err)
+	for _, wantErr := range []string{
+		"templateName is empty",
+		"versions: invalid version latest",
+		"defaultVersion 0.0.2 is not one of versions [0.0.1, latest]",
+		"variables[0]: name is empty",
+		"variable IMAGENAME: unknown kind containerImageNmae",
+		"variable IMAGENAME: invalid versions range >>0.0.1",
+	} {
+		assert.ErrorContains(t, err, wantErr)
+	}
+
+	draftConfig, err := NewConfigFromFS(fileSys, "draft.yaml", WithoutValidation())
+	assert.Nil(t, err)
+	assert.NotNil(t, draftConfig)
+
+	// kinds with a custom validator are known
+	draftConfig = &DraftConfig{
+		TemplateName: "custom-kind",
+		Variables:    []*BuilderVar{{Name: "CODE", Kind: "projectCode"}},
+	}
+	assert.NotNil(t, draftConfig.Validate())
+	draftConfig.SetVariableValidator("projectCode", func(string) error { return nil })
+	assert.Nil(t, draftConfig.Validate())
+
+	// kinds registered with the validators package are known to every config
+	draftConfig = &DraftConfig{
+		TemplateName: "registered-kind",
+		Variables:    []*BuilderVar{{Name: "TIER", Kind: "internalServiceTier"}},
+	}
+	assert.Nil(t, validators.Register("internalServiceTier", func(string) error { return nil }, validators.Override()))
+	assert.Nil(t, draftConfig.Validate())
+
+	// kinds registered with the transformers package are known to every config
+	draftConfig = &DraftConfig{
+		TemplateName: "registered-transformer-kind",
+		Variables:    []*BuilderVar{{Name: "REGION", Kind: "internalRegionCode"}},
+	}
+	assert.NotNil(t, draftConfig.Validate())
+	var regionTransformer VariableTransformer = func(input string) (any, error) { return strings.ToUpper(input), nil }
+	assert.Nil(t, transformers.Register("internalRegionCode", regionTransformer, transformers.Override()))
+	assert.Nil(t, draftConfig.Validate())
+	draftConfig.SetVariable("REGION", "weu")
+	value, err := draftConfig.GetVariableValue("REGION")
+	assert.Nil(t, err)
+	assert.Equal(t, "WEU", value)
+}
+
+func TestValidateDelims(t *testing.T) {
+	tests := []struct {
+		leftDelim  string
+		rightDelim string
+		wantErr    string
+	}{
+		{},
+		{leftDelim: "[[", rightDelim: "]]"},
+		{leftDelim: "[[", wantErr: "leftDelim and rightDelim must be set together"},
+		{rightDelim: "]]", wantErr: "leftDelim and rightDelim must be set together"},
+		{leftDelim: "%%", rightDelim: "%%", wantErr: `leftDelim and rightDelim must differ, both are "%%"`},
+	}
+
+	for _, tt := range tests {
+		draftConfig := &DraftConfig{TemplateName: "delims", LeftDelim: tt.leftDelim, RightDelim: tt.rightDelim}
+		err := draftConfig.Validate()
+		if tt.wantErr == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.EqualError(t, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRawFiles(t *testing.T) {
+	draftConfig := &DraftConfig{TemplateName: "raw", RawFiles: []string{"scripts/*.sh", "assets/[a-"}}
+	assert.EqualError(t, draftConfig.Validate(), `rawFiles: invalid pattern "assets/[a-": syntax error in pattern`)
+
+	draftConfig.RawFiles = []string{"scripts/*.sh", "favicon.ico"}
+	assert.Nil(t, draftConfig.Validate())
+	assert.True(t, draftConfig.IsRawFile("scripts/entrypoint.sh"))
+	assert.True(t, draftConfig.IsRawFile("favicon.ico"))
+	assert.False(t, draftConfig.IsRawFile("scripts/nested/entrypoint.sh"))
+	assert.False(t, draftConfig.IsRawFile("assets/favicon.ico"))
+}
+
+func TestCheckDestinations(t *testing.T) {
+	draftConfig := &DraftConfig{TemplateName: "destinations", Destinations: map[string]string{
+		"workflows/*.yaml": ".github/workflows",
+		"charts/[a-/**":    "charts",
+		"docker/**":        "",
+	}}
+	assert.EqualError(t, draftConfig.Validate(), `destinations: invalid pattern "charts/[a-/**": syntax error in pattern
+destinations docker/**: directory is empty`)
+
+	delete(draftConfig.Destinations, "charts/[a-/**")
+	draftConfig.Destinations["docker/**"] = "services/{{ .APPNAME }}"
+	assert.Nil(t, draftConfig.Validate())
+}
+
+func TestDuplicateVariables(t *testing.T) {
+	_, err := NewConfigFromFS(os.DirFS("testdata"), "duplicate_variables.yaml")
+	assert.EqualError(t, err, "invalid draft config duplicate_variables.yaml: duplicate variable names: PORT, APPNAME")
+
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{{Name: "PORT"}},
+	}
+	draftConfig.SetVariable("PORT", "80")
+	draftConfig.SetVariable("PORT", "8080")
+	assert.Len(t, draftConfig.Variables, 1)
+	assert.Equal(t, "8080", draftConfig.Variables[0].Value)
+}
+
+func TestApplyDefaultVariablesAggregatesErrors(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		return &DraftConfig{
+			Versions: []string{"0.0.1"},
+			Variables: []*BuilderVar{
+				{Name: "APPNAME", Versions: ">=0.0.1"},
+				{Name: "PORT", Versions: ">=0.0.1", Default: BuilderVarDefault{Value: "80"}},
+				{Name: "IMAGENAME", Versions: ">=0.0.1", Default: BuilderVarDefault{ReferenceVar: "MISSING"}},
+				{Name: "NAMESPACE", Versions: ">=0.0.1"},
+			},
+		}
+	}
+
+	draftConfig := newConfig()
+	err := draftConfig.ApplyDefaultVariablesForVersion("0.0.1")
+	assert.EqualError(t, err, "variable APPNAME has no default value\napply default variables: variable MISSING not found\nvariable NAMESPACE has no default value")
+	port, _ := draftConfig.GetVariable("PORT")
+	assert.Equal(t, "80", port.Value)
+
+	err = newConfig().ApplyDefaultVariables(FailFast())
+	assert.EqualError(t, err, "variable APPNAME has no default value")
+
+	err = newConfig().ApplyDefaultVariablesForVersion("0.0.1", FailFast())
+	assert.EqualError(t, err, "variable APPNAME has no default value")
+}
+
+func TestApplyDefaultVariablesReferenceOrder(t *testing.T) {
+	draftConfig := DraftConfig{
+		Versions: []string{"0.0.1", "0.0.2"},
+		Variables: []*BuilderVar{
+			{Name: "SERVICENAME", Versions: ">=0.0.1", Default: BuilderVarDefault{ReferenceVar: "DEPLOYMENTNAME"}},
+			{Name: "DEPLOYMENTNAME", Versions: ">=0.0.1", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}},
+			{
+				Name:     "APPNAME",
+				Versions: ">=0.0.1",
+				Default: BuilderVarDefault{
+					Value:             "my-app",
+					VersionedDefaults: []VersionedDefault{{Versions: ">=0.0.2", Value: "my-app-v2"}},
+				},
+			},
+		},
+	}
+
+	assert.Nil(t, draftConfig.ApplyDefaultVariablesForVersion("0.0.2"))
+	assert.Equal(t, map[string]string{
+		"SERVICENAME":    "my-app-v2",
+		"DEPLOYMENTNAME": "my-app-v2",
+		"APPNAME":        "my-app-v2",
+	}, draftConfig.GetVariableMap())
+
+	cyclicConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "A", Default: BuilderVarDefault{ReferenceVar: "B"}},
+			{Name: "B", Default: BuilderVarDefault{ReferenceVar: "C"}},
+			{Name: "C", Default: BuilderVarDefault{ReferenceVar: "A"}},
+		},
+	}
+	assert.EqualError(t, cyclicConfig.ApplyDefaultVariables(), "apply default variables: cyclical reference detected: A → B → C → A")
+}
+
+func TestGetVariableValueOrDefault(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		return &DraftConfig{
+			Variables: []*BuilderVar{
+				{Name: "SERVICENAME", Default: BuilderVarDefault{ReferenceVar: "DEPLOYMENTNAME"}},
+				{Name: "DEPLOYMENTNAME", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}},
+				{Name: "APPNAME", Default: BuilderVarDefault{Value: "my-app"}},
+				{Name: "PORT", Type: "int", Value: "8080"},
+				{Name: "NAMESPACE"},
+			},
+		}
+	}
+
+	draftConfig := newConfig()
+	value, err := draftConfig.GetVariableValueOrDefault("SERVICENAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app", value)
+	serviceName, _ := draftConfig.GetVariable("SERVICENAME")
+	assert.Equal(t, "", serviceName.Value)
+
+	value, err = draftConfig.GetVariableValueOrDefault("PORT")
+	assert.Nil(t, err)
+	assert.Equal(t, "8080", value)
+
+	_, err = draftConfig.GetVariableValueOrDefault("NAMESPACE")
+	assert.EqualError(t, err, "variable NAMESPACE has no value or default value")
+
+	_, err = draftConfig.GetVariableValueOrDefault("MISSING")
+	assert.EqualError(t, err, "variable MISSING not found")
+
+	draftConfig = newConfig()
+	value, err = draftConfig.GetVariableValueOrDefault("DEPLOYMENTNAME", PersistDefault())
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app", value)
+	deploymentName, _ := draftConfig.GetVariable("DEPLOYMENTNAME")
+	assert.Equal(t, "my-app", deploymentName.Value)
+	appName, _ := draftConfig.GetVariable("APPNAME")
+	assert.Equal(t, "", appName.Value)
+
+	cyclicConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "A", Default: BuilderVarDefault{ReferenceVar: "B"}},
+			{Name: "B", Default: BuilderVarDefault{ReferenceVar: "A"}},
+		},
+	}
+	_, err = cyclicConfig.GetVariableValueOrDefault("A")
+	assert.EqualError(t, err, "cyclical reference detected: A → B → A")
+}
+
+func TestRecurseReferenceVarsCycles(t *testing.T) {
+	tests := []struct {
+		testName   string
+		variables  []*BuilderVar
+		wantErrMsg string
+	}{
+		{
+			testName: "twoNodeCycleNotInvolvingStart",
+			variables: []*BuilderVar{
+				{Name: "A", Default: BuilderVarDefault{ReferenceVar: "B"}},
+				{Name: "B", Default: BuilderVarDefault{ReferenceVar: "C"}},
+				{Name: "C", Default: BuilderVarDefault{ReferenceVar: "B"}},
+			},
+			wantErrMsg: "cyclical reference detected: A → B → C → B",
+		},
+		{
+			testName: "threeNodeCycleNotInvolvingStart",
+			variables: []*BuilderVar{
+				{Name: "A", Default: BuilderVarDefault{ReferenceVar: "B"}},
+				{Name: "B", Default: BuilderVarDefault{ReferenceVar: "C"}},
+				{Name: "C", Default: BuilderVarDefault{ReferenceVar: "D"}},
+				{Name: "D", Default: BuilderVarDefault{ReferenceVar: "B"}},
+			},
+			wantErrMsg: "cyclical reference detected: A → B → C → D → B",
+		},
+		{
+			testName: "selfReference",
+			variables: []*BuilderVar{
+				{Name: "A", Default: BuilderVarDefault{ReferenceVar: "A"}},
+			},
+			wantErrMsg: "cyclical reference detected: A → A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			draftConfig := &DraftConfig{TemplateName: "cycles", Variables: tt.variables}
+
+			_, err := draftConfig.recurseReferenceVars(tt.variables[0], nil)
+			assert.EqualError(t, err, tt.wantErrMsg)
+
+			assert.ErrorContains(t, draftConfig.Validate(), "cyclical reference detected")
+		})
+	}
+}
+
+func TestVariableAliases(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	newConfig := func() *DraftConfig {
+		return &DraftConfig{
+			TemplateName: "aliases",
+			Variables: []*BuilderVar{
+				{Name: "TARGETNAMESPACE", Aliases: []string{"namespace", "NAMESPACE"}},
+				{Name: "OLDPORT", Deprecated: "the port is now derived from the service"},
+			},
+		}
+	}
+
+	draftConfig := newConfig()
+	assert.Nil(t, draftConfig.VariableMapToDraftConfig(map[string]string{"namespace": "my-namespace"}))
+	assert.Len(t, draftConfig.Variables, 2)
+	assert.Equal(t, "my-namespace", draftConfig.Variables[0].Value)
+	assert.Contains(t, logs.String(), "Variable namespace is deprecated, use TARGETNAMESPACE instead")
+
+	variable, err := draftConfig.GetVariable("NAMESPACE")
+	assert.Nil(t, err)
+	assert.Equal(t, "TARGETNAMESPACE", variable.Name)
+
+	// templates still reading the old name get the value of the renamed variable
+	logs.Reset()
+	value, err := draftConfig.GetVariableValue("namespace")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-namespace", value)
+	assert.Contains(t, logs.String(), "Variable namespace is deprecated, use TARGETNAMESPACE instead")
+
+	draftConfig.SetVariable("OLDPORT", "80")
+	assert.Contains(t, logs.String(), "Variable OLDPORT is deprecated: the port is now derived from the service")
+
+	// the same value through the alias and canonical name is allowed
+	assert.Nil(t, newConfig().VariableMapToDraftConfig(map[string]string{"namespace": "a", "TARGETNAMESPACE": "a"}))
+
+	err = newConfig().VariableMapToDraftConfig(map[string]string{"namespace": "a", "TARGETNAMESPACE": "b"})
+	assert.EqualError(t, err, "conflicting values for variable TARGETNAMESPACE set through both TARGETNAMESPACE and namespace")
+
+	aliasCollision := newConfig()
+	aliasCollision.Variables = append(aliasCollision.Variables, &BuilderVar{Name: "namespace"})
+	assert.EqualError(t, aliasCollision.Validate(), "duplicate variable names: namespace")
+}
+
+func TestCollectUnsetVariables(t *testing.T) {
+	draftConfig := DraftConfig{
+		Versions: []string{"0.0.1", "0.0.2"},
+		Variables: []*BuilderVar{
+			{Name: "APPNAME", Value: "my-app"},
+			{Name: "PORT", Versions: ">=0.0.1"},
+			{Name: "NAMESPACE", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "APPNAME"}},
+			{Name: "IMAGETAG", Default: BuilderVarDefault{IsPromptDisabled: true}},
+			{
+				Name:     "REPLICAS",
+				Versions: ">=0.0.2",
+				Default: BuilderVarDefault{
+					IsPromptDisabled:  true,
+					VersionedDefaults: []VersionedDefault{{Versions: ">=0.0.2", Value: "3"}},
+				},
+			},
+			{Name: "HEALTHPATH", Versions: ">=0.0.2"},
+			{
+				Name:                  "INGRESSHOST",
+				ActiveWhenConstraints: []ActiveWhenConstraint{{VariableName: "APPNAME", Value: "other-app", Condition: EqualTo}},
+			},
+		},
+	}
+
+	variableNames := func(variables []*BuilderVar) []string {
+		names := []string{}
+		for _, variable := range variables {
+			names = append(names, variable.Name)
+		}
+		return names
+	}
+
+	unset, err := draftConfig.CollectUnsetVariables("0.0.1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"PORT", "IMAGETAG"}, variableNames(unset))
+
+	unset, err = draftConfig.CollectUnsetVariables("0.0.2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"PORT", "IMAGETAG", "HEALTHPATH"}, variableNames(unset))
+
+	unset, err = draftConfig.CollectUnsetVariables("")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"PORT", "IMAGETAG", "REPLICAS", "HEALTHPATH"}, variableNames(unset))
+
+	_, err = draftConfig.CollectUnsetVariables("latest")
+	assert.ErrorContains(t, err, "invalid version")
+}
+
+func TestValidatePromptDisabledDefaults(t *testing.T) {
+	optional := false
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "APPNAME", Default: BuilderVarDefault{Value: "my-app"}},
+			{Name: "NAMESPACE", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "APPNAME"}},
+			{Name: "IMAGENAME", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "IMAGEREPO"}},
+			{Name: "IMAGEREPO"},
+			{Name: "IMAGETAG", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "IMAGEREPO", Value: "latest"}},
+			{Name: "IMAGEDIGEST", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "IMAGEREPO"}},
+			{Name: "SUFFIX", Default: BuilderVarDefault{IsPromptDisabled: true, Generator: "randomHex:4"}},
+			{Name: "TOKEN", Default: BuilderVarDefault{IsPromptDisabled: true, EnvVar: "DRAFT_TEST_TOKEN"}},
+			{Name: "ANNOTATION", Required: &optional, Default: BuilderVarDefault{IsPromptDisabled: true}},
+			{
+				Name:                  "INGRESSHOST",
+				Default:               BuilderVarDefault{IsPromptDisabled: true},
+				ActiveWhenConstraints: []ActiveWhenConstraint{{VariableName: "APPNAME", Value: "other-app", Condition: EqualTo}},
+			},
+		},
+	}
+
+	assert.EqualError(t, draftConfig.ValidatePromptDisabledDefaults(), "prompts are disabled for variables with no default value: IMAGENAME, TOKEN, INGRESSHOST")
+
+	draftConfig.SetVariable("IMAGEREPO", "myregistry.azurecr.io/my-app")
+	draftConfig.SetVariable("TOKEN", "secret")
+	draftConfig.SetVariable("INGRESSHOST", "my-app.example.com")
+	assert.Nil(t, draftConfig.ValidatePromptDisabledDefaults())
+
+	draftConfig.SetVariable("APPNAME", "my-app")
+	promptDisabled, err := draftConfig.PromptDisabledVariables()
+	assert.Nil(t, err)
+	names := []string{}
+	for _, variable := range promptDisabled {
+		names = append(names, variable.Name)
+	}
+	assert.Equal(t, []string{"NAMESPACE", "IMAGENAME", "IMAGETAG", "IMAGEDIGEST", "SUFFIX", "TOKEN", "ANNOTATION"}, names)
+
+	brokenConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "NAMESPACE", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "MISSING"}},
+		},
+	}
+	assert.ErrorContains(t, brokenConfig.ValidatePromptDisabledDefaults(), "validate prompt disabled defaults: variable MISSING not found")
+}
+
+func TestOptionalVariables(t *testing.T) {
+	optional := false
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "APPNAME", Default: BuilderVarDefault{Value: "my-app"}},
+			{Name: "ANNOTATION", Required: &optional, Pattern: "^[a-z]+$"},
+			{Name: "SIDECARIMAGE", Required: &optional, Kind: "imagePullPolicy"},
+			{Name: "HOSTS", Type: "array", Required: &optional},
+			{Name: "REPLICAS", Type: "int", Required: &optional},
+		},
+	}
+	draftConfig.SetVariableValidator("imagePullPolicy", func(string) error { return errors.New("validator ran") })
+
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+	assert.Equal(t, "my-app", draftConfig.Variables[0].Value)
+
+	for _, name := range []string{"ANNOTATION", "SIDECARIMAGE"} {
+		value, err := draftConfig.GetVariableValue(name)
+		assert.Nil(t, err)
+		assert.Equal(t, "", value)
+	}
+
+	hosts, err := draftConfig.GetVariableValues("HOSTS")
+	assert.Nil(t, err)
+	assert.Empty(t, hosts)
+
+	replicas, err := draftConfig.GetVariableInt("REPLICAS")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, replicas)
+
+	value, err := draftConfig.GetVariableValueOrDefault("ANNOTATION", PersistDefault())
+	assert.Nil(t, err)
+	assert.Equal(t, "", value)
+
+	draftConfig.SetVariable("ANNOTATION", "Invalid")
+	_, err = draftConfig.GetVariableValue("ANNOTATION")
+	assert.NotNil(t, err)
+
+	requiredConfig := DraftConfig{Variables: []*BuilderVar{{Name: "NAMESPACE"}}}
+	assert.EqualError(t, requiredConfig.ApplyDefaultVariables(), "variable NAMESPACE has no default value")
+	_, err = requiredConfig.GetVariableValue("NAMESPACE")
+	assert.EqualError(t, err, "variable NAMESPACE has no value")
+}
+
+func TestApplyDefaultVariablesForVersionNormalization(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		return &DraftConfig{
+			Versions: []string{"0.0.1", "0.0.2-beta.1"},
+			Variables: []*BuilderVar{
+				{Name: "APPNAME", Default: BuilderVarDefault{Value: "my-app"}},
+				{Name: "PORT", Versions: ">=0.0.2-beta.1", Default: BuilderVarDefault{Value: "80"}},
+			},
+		}
+	}
+
+	draftConfig := newConfig()
+	assert.Nil(t, draftConfig.ApplyDefaultVariablesForVersion("v0.0.1"))
+	assert.Equal(t, map[string]string{"APPNAME": "my-app", "PORT": ""}, draftConfig.GetVariableMap())
+
+	draftConfig = newConfig()
+	assert.Nil(t, draftConfig.ApplyDefaultVariablesForVersion("0.0.2-beta.1"))
+	assert.Equal(t, map[string]string{"APPNAME": "my-app", "PORT": "80"}, draftConfig.GetVariableMap())
+
+	malformedConfig := newConfig()
+	malformedConfig.Variables[1].Versions = ">=zero"
+	err := malformedConfig.ApplyDefaultVariablesForVersion("0.0.1")
+	assert.ErrorContains(t, err, `variable PORT has invalid versions range ">=zero"`)
+
+	assert.Equal(t, "0.0.1", NormalizeVersion(" v0.0.1 "))
+	assert.Equal(t, "v", NormalizeVersion("v"))
+	assert.True(t, ContainsVersion([]string{"v0.0.1"}, "0.0.1"))
+}
+
+func TestDefaultsAreValidated(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		draftConfig := &DraftConfig{
+			Variables: []*BuilderVar{
+				{Name: "APPNAME", Kind: "label"},
+				{Name: "SERVICENAME", Kind: "label", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}},
+				{Name: "NAMESPACE", Kind: "label", Default: BuilderVarDefault{Value: "Bad_Namespace"}},
+				{Name: "ENVVARS", Kind: "envVarMap", Default: BuilderVarDefault{Value: `{"A":"1"}`}},
+			},
+		}
+		draftConfig.SetVariableValidator("label", func(value string) error {
+			if strings.ToLower(value) != value {
+				return fmt.Errorf("invalid label: %s", value)
+			}
+			return nil
+		})
+		return draftConfig
+	}
+
+	draftConfig := newConfig()
+	draftConfig.SetVariable("APPNAME", "My_App")
+	err := draftConfig.ApplyDefaultVariables()
+	assert.ErrorContains(t, err, "apply default variables: variable SERVICENAME has invalid reference variable value: failed variable validation: invalid label: My_App")
+	assert.ErrorContains(t, err, "apply default variables: variable NAMESPACE has invalid default value: failed variable validation: invalid label: Bad_Namespace")
+	assert.Equal(t, "", draftConfig.Variables[2].Value)
+
+	_, err = draftConfig.GetValidatedVariableMap()
+	assert.EqualError(t, err, "variable APPNAME has invalid user input: failed variable validation: invalid label: My_App")
+
+	draftConfig = newConfig()
+	draftConfig.SetVariable("APPNAME", "my-app")
+	draftConfig.SetVariable("NAMESPACE", "default")
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+
+	varMap, err := draftConfig.GetValidatedVariableMap()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{
+		"APPNAME":     "my-app",
+		"SERVICENAME": "my-app",
+		"NAMESPACE":   "default",
+		"ENVVARS":     map[string]string{"A": "1"},
+	}, varMap)
+}
+
+func TestTransformReference(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		draftConfig := &DraftConfig{
+			Variables: []*BuilderVar{
+				{Name: "APPNAME", Value: "My_App"},
+				{Name: "IMAGENAME", Kind: "imageName", Default: BuilderVarDefault{ReferenceVar: "APPNAME", TransformReference: true}},
+				{Name: "SERVICENAME", Kind: "imageName", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}},
+			},
+		}
+		draftConfig.SetVariableTransformer("imageName", func(value string) (any, error) {
+			if value == "" {
+				return "", errors.New("empty image name")
+			}
+			return strings.ReplaceAll(strings.ToLower(value), "_", "-"), nil
+		})
+		draftConfig.SetVariableValidator("imageName", func(value string) error {
+			if strings.ToLower(value) != value {
+				return fmt.Errorf("invalid image name: %s", value)
+			}
+			return nil
+		})
+		return draftConfig
+	}
+
+	draftConfig := newConfig()
+	err := draftConfig.ApplyDefaultVariables()
+	assert.EqualError(t, err, "apply default variables: variable SERVICENAME has invalid reference variable value: failed variable validation: invalid image name: My_App")
+	assert.Equal(t, "my-app", draftConfig.Variables[1].Value)
+
+	value, err := newConfig().GetVariableValueOrDefault("IMAGENAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app", value)
+
+	failingConfig := newConfig()
+	failingConfig.SetVariableTransformer("imageName", func(string) (any, error) {
+		return nil, errors.New("this is a failing transformer")
+	})
+	err = failingConfig.ApplyDefaultVariables()
+	assert.ErrorContains(t, err, "apply default variables: variable IMAGENAME: transforming value of reference variable APPNAME: this is a failing transformer")
+
+	mapConfig := newConfig()
+	mapConfig.SetVariableTransformer("imageName", func(value string) (any, error) {
+		return map[string]string{"name": value}, nil
+	})
+	err = mapConfig.ApplyDefaultVariables()
+	assert.ErrorContains(t, err, "variable IMAGENAME: transformer for kind imageName returned map[string]string, not a string")
+}
+
+func TestDisplayNameReference(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		return &DraftConfig{
+			Variables: []*BuilderVar{
+				{Name: "DISPLAYNAME", Kind: "displayName", Value: "Café Über App"},
+				{Name: "TITLE", Kind: "displayName", Default: BuilderVarDefault{ReferenceVar: "DISPLAYNAME"}},
+				{Name: "APPNAME", Kind: "kubernetesResourceName", Default: BuilderVarDefault{ReferenceVar: "TITLE"}},
+				{Name: "IMAGENAME", Kind: "containerImageName", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}},
+				{Name: "LABEL", Kind: "normalize", Default: BuilderVarDefault{ReferenceVar: "DISPLAYNAME", TransformReference: true}},
+			},
+		}
+	}
+
+	draftConfig := newConfig()
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+	for name, want := range map[string]string{
+		"TITLE":     "Café Über App",
+		"APPNAME":   "cafe-uber-app",
+		"IMAGENAME": "cafe-uber-app",
+		"LABEL":     "café über app",
+	} {
+		variable, err := draftConfig.GetVariable(name)
+		assert.Nil(t, err)
+		assert.Equal(t, want, variable.Value, name)
+	}
+
+	value, err := newConfig().GetVariableValueOrDefault("IMAGENAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "cafe-uber-app", value)
+
+	emptyConfig := newConfig()
+	emptyConfig.Variables[0].Value = "!!!"
+	err = emptyConfig.ApplyDefaultVariables()
+	assert.ErrorContains(t, err, "variable APPNAME: transforming value of reference variable TITLE: can't make a slug from \"!!!\"")
+}
+
+func TestACRReference(t *testing.T) {
+	for input, want := range map[string][2]string{
+		"myacr":            {"myacr", "myacr.azurecr.io"},
+		"myacr.azurecr.io": {"myacr", "myacr.azurecr.io"},
+		"MyAcr.azurecr.cn": {"myacr", "myacr.azurecr.cn"},
+		"myacr.azurecr.us": {"myacr", "myacr.azurecr.us"},
+	} {
+		draftConfig := &DraftConfig{
+			Variables: []*BuilderVar{
+				{Name: "AZURECONTAINERREGISTRY", Kind: "acrNameOrLoginServer", Value: input},
+				{Name: "ACRNAME", Kind: "acrName", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "AZURECONTAINERREGISTRY", TransformReference: true}},
+				{Name: "ACRLOGINSERVER", Kind: "acrLoginServer", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "AZURECONTAINERREGISTRY", TransformReference: true}},
+			},
+		}
+
+		assert.Nil(t, draftConfig.ApplyDefaultVariables(), input)
+		for name, value := range map[string]string{"AZURECONTAINERREGISTRY": want[0], "ACRNAME": want[0], "ACRLOGINSERVER": want[1]} {
+			rendered, err := draftConfig.GetVariableValue(name)
+			assert.Nil(t, err)
+			assert.Equal(t, value, rendered, input)
+		}
+	}
+
+	promptedConfig := &DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "AZURECONTAINERREGISTRY", Kind: "acrNameOrLoginServer"},
+			{Name: "ACRLOGINSERVER", Kind: "acrLoginServer", Default: BuilderVarDefault{IsPromptDisabled: true, ReferenceVar: "AZURECONTAINERREGISTRY", TransformReference: true}},
+		},
+	}
+	assert.Nil(t, promptedConfig.ValidatePromptDisabledDefaults())
+}
+
+func TestTransformAffixes(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		return &DraftConfig{
+			Variables: []*BuilderVar{
+				{Name: "APPNAME", Kind: "kubeName", Value: "My Cool App"},
+				{Name: "ORG", Value: "contoso"},
+				{Name: "SERVICEACCOUNT", Kind: "kubeName", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}, Transform: BuilderVarTransform{Suffix: "-sa"}},
+				{Name: "SERVICENAME", Kind: "kubeName", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}, Transform: BuilderVarTransform{Prefix: "${ORG}-", Suffix: "-svc"}},
+				{Name: "IMAGE", Kind: "imageReference", Default: BuilderVarDefault{Value: "app"}, Transform: BuilderVarTransform{Prefix: "ghcr.io/${ORG}/"}},
+				{Name: "ESCAPED", Value: "app", Transform: BuilderVarTransform{Suffix: "-$${ORG}"}},
+			},
+		}
+	}
+
+	draftConfig := newConfig()
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+	for name, want := range map[string]string{
+		"APPNAME":        "my-cool-app",
+		"SERVICEACCOUNT": "my-cool-app-sa",
+		"SERVICENAME":    "contoso-my-cool-app-svc",
+		"IMAGE":          "ghcr.io/contoso/app",
+		"ESCAPED":        "app-${ORG}",
+	} {
+		value, err := draftConfig.GetVariableValue(name)
+		assert.Nil(t, err)
+		assert.Equal(t, want, value, name)
+	}
+
+	variable, err := draftConfig.GetVariable("SERVICEACCOUNT")
+	assert.Nil(t, err)
+	assert.Equal(t, "My Cool App", variable.Value, "the stored value must not include the suffix")
+
+	value, err := newConfig().GetVariableValueOrDefault("SERVICEACCOUNT")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cool-app-sa", value)
+
+	draftConfig.SetVariable("ORG", "Contoso")
+	_, err = draftConfig.GetVariableValue("IMAGE")
+	assert.EqualError(t, err, `failed variable validation with transform prefix and suffix: invalid image reference "ghcr.io/Contoso/app": repository component "Contoso" must be lowercase`)
+
+	tooLong := newConfig()
+	tooLong.Variables[0].Kind = "kubernetesResourceName"
+	tooLong.Variables[0].Value = strings.Repeat("a", 62)
+	tooLong.Variables[2].Kind = "kubernetesResourceName"
+	err = tooLong.ApplyDefaultVariables()
+	assert.ErrorContains(t, err, "variable SERVICEACCOUNT has invalid reference variable value: failed variable validation with transform prefix and suffix: invalid kubernetes resource name")
+
+	unknownConfig := newConfig()
+	unknownConfig.Variables[3].Transform.Prefix = "${TEAM}-"
+	_, err = unknownConfig.GetVariableValueOrDefault("SERVICENAME")
+	assert.EqualError(t, err, `variable SERVICENAME: unknown variable TEAM in transform prefix "${TEAM}-"`)
+
+	mapConfig := &DraftConfig{
+		Variables: []*BuilderVar{{Name: "ENV", Kind: "envVarMap", Value: `{"A":"1"}`, Transform: BuilderVarTransform{Suffix: "-x"}}},
+	}
+	_, err = mapConfig.GetVariableValue("ENV")
+	assert.EqualError(t, err, "variable ENV: transformer for kind envVarMap returned map[string]string, not a string, so transform prefix and suffix can't be added")
+}
+
+func TestDescriptiveKinds(t *testing.T) {
+	assert.True(t, slices.IsSorted(descriptiveKinds))
+	for _, kind := range descriptiveKinds {
+		assert.False(t, slices.Contains(validators.List(), kind), "kind %s has a validator", kind)
+		assert.False(t, transformers.Exists(kind), "kind %s has a transformer", kind)
+	}
+
+	draftConfig := &DraftConfig{}
+	assert.True(t, draftConfig.isKnownKind("displayName"))
+	assert.True(t, draftConfig.isKnownKind("kubeName"))
+	assert.True(t, draftConfig.isKnownKind("boolean"))
+	assert.False(t, draftConfig.isKnownKind("kubename"))
+}
+
+func TestKindChain(t *testing.T) {
+	draftConfig := &DraftConfig{
+		TemplateName: "chain",
+		Variables: []*BuilderVar{
+			{Name: "APPNAME", Kind: "trimSpace,lowercase,kubeName", Value: "  My Cool App!  "},
+			{Name: "PORT", Kind: "trimSpace, containerPort", Value: " 8080 "},
+			{Name: "SERVICEACCOUNT", Kind: "trimSpace,lowercase,kubernetesResourceName", Value: " My-App ", Transform: BuilderVarTransform{Suffix: "-sa"}},
+			{Name: "NAMESPACE", Kind: "trimSpace,kubernetesResourceName", Value: " My_Namespace "},
+		},
+	}
+	assert.Nil(t, draftConfig.Validate())
+
+	for name, want := range map[string]string{
+		"APPNAME":        "my-cool-app",
+		"PORT":           "8080",
+		"SERVICEACCOUNT": "my-app-sa",
+	} {
+		value, err := draftConfig.GetVariableValue(name)
+		assert.Nil(t, err)
+		assert.Equal(t, want, value, name)
+	}
+
+	_, err := draftConfig.GetVariableValue("NAMESPACE")
+	assert.ErrorContains(t, err, `failed variable validation: invalid kubernetes resource name "My_Namespace"`)
+
+	copied := draftConfig.DeepCopy()
+	assert.Equal(t, "trimSpace,lowercase,kubeName", copied.Variables[0].Kind)
+	value, err := copied.GetVariableValue("APPNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cool-app", value)
+
+	draftConfig.SetVariableTransformer("lowercase", func(value string) (any, error) {
+		return strings.ToUpper(value), nil
+	})
+	value, err = draftConfig.GetVariableValue("APPNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cool-app", value, "kubeName runs after the overridden lowercase transformer")
+
+	invalidConfig := &DraftConfig{
+		TemplateName: "chain",
+		Variables:    []*BuilderVar{{Name: "APPNAME", Kind: "trimSpace,notAKind,lowercase"}},
+	}
+	assert.EqualError(t, invalidConfig.Validate(), "variable APPNAME: unknown kind notAKind")
+
+	mapConfig := &DraftConfig{
+		Variables: []*BuilderVar{{Name: "ENV", Kind: "envVarMap,trimSpace", Value: `{"A":"1"}`}},
+	}
+	_, err = mapConfig.GetVariableValue("ENV")
+	assert.ErrorContains(t, err, "transformer for kind envVarMap returned map[string]string, not a string, so it can't be chained")
+}
+
+func TestTransformTemplate(t *testing.T) {
+	newConfig := func() *DraftConfig {
+		return &DraftConfig{
+			TemplateName: "transformTemplate",
+			Variables: []*BuilderVar{
+				{Name: "FRAMEWORK", Value: "spring"},
+				{Name: "PROBEPATH", Default: BuilderVarDefault{ReferenceVar: "FRAMEWORK"}, TransformTemplate: `{{ if eq . "spring" }}/actuator/health{{ else }}/healthz{{ end }}`},
+				{Name: "APPNAME", Value: "my-app"},
+				// dot is the raw value, so other variables are read through the vars function instead of .Vars
+				{Name: "IMAGE", Value: "nginx", TransformTemplate: `{{ (vars).APPNAME }}/{{ . }}`},
+				{Name: "MISSING", Value: "x", TransformTemplate: `{{ (vars).NOTDEFINED }}`},
+			},
+		}
+	}
+
+	draftConfig := newConfig()
+	assert.Nil(t, draftConfig.Validate())
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+
+	value, err := draftConfig.GetVariableValue("PROBEPATH")
+	assert.Nil(t, err)
+	assert.Equal(t, "/actuator/health", value)
+
+	draftConfig.SetVariable("PROBEPATH", "flask")
+	value, err = draftConfig.GetVariableValue("PROBEPATH")
+	assert.Nil(t, err)
+	assert.Equal(t, "/healthz", value)
+
+	value, err = draftConfig.GetVariableValue("IMAGE")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app/nginx", value)
+
+	_, err = draftConfig.GetVariableValue("MISSING")
+	assert.ErrorContains(t, err, "failed variable transformation: executing transformTemplate: template: MISSING")
+	assert.ErrorContains(t, err, `map has no entry for key "NOTDEFINED"`)
+
+	copied := draftConfig.DeepCopy()
+	value, err = copied.GetVariableValue("IMAGE")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app/nginx", value)
+
+	invalidConfig := newConfig()
+	invalidConfig.Variables[1].TransformTemplate = "{{ if . }}"
+	err = invalidConfig.Validate()
+	assert.ErrorContains(t, err, `variable PROBEPATH: invalid transformTemplate "{{ if . }}": template: PROBEPATH:1: unexpected EOF`)
+
+	// each template reads the other variable, whose default is the result of that variable's own template
+	recursiveConfig := &DraftConfig{
+		TemplateName: "transformTemplate",
+		Variables: []*BuilderVar{
+			{Name: "SEED", Value: "seed"},
+			{Name: "FIRST", Default: BuilderVarDefault{ReferenceVar: "SEED", TransformReference: true}, TransformTemplate: `{{ . }}-{{ (vars).SECOND }}`},
+			{Name: "SECOND", Default: BuilderVarDefault{ReferenceVar: "SEED", TransformReference: true}, TransformTemplate: `{{ . }}-{{ (vars).FIRST }}`},
+		},
+	}
+	_, err = recursiveConfig.GetVariableValueOrDefault("FIRST")
+	assert.ErrorContains(t, err, `map has no entry for key "SECOND"`)
+
+	// executing transform templates doesn't change the config, so one config can be read concurrently
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := draftConfig.GetVariableValue("IMAGE")
+			assert.Nil(t, err)
+			assert.Equal(t, "my-app/nginx", value)
+			_, err = recursiveConfig.GetVariableValueOrDefault("SECOND")
+			assert.ErrorContains(t, err, `map has no entry for key "FIRST"`)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestVariableErrorMessage(t *testing.T) {
+	const portMessage = "Port must be between 1024 and 65535 because the base image runs as non-root"
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "PORT", Type: "int", Kind: "port", ErrorMessage: portMessage},
+			{Name: "NAMESPACE", Pattern: "^[a-z-]+$", ErrorMessage: "Namespace must be lowercase"},
+			{Name: "PULLPOLICY", AllowedValues: []string{"Always", "Never"}, ErrorMessage: "Pull policy must be Always or Never"},
+			{Name: "TAG", Kind: "failingTransform", ErrorMessage: "Tag could not be parsed"},
+			{Name: "APPNAME", Pattern: "^[a-z-]+$"},
+		},
+	}
+	draftConfig.SetVariableValidator("port", func(value string) error {
+		if port, _ := strconv.Atoi(value); port < 1024 {
+			return errors.New("invalid port")
+		}
+		return nil
+	})
+	draftConfig.SetVariableTransformer("failingTransform", func(string) (any, error) {
+		return nil, errors.New("this is a failing transformer")
+	})
+
+	tests := []struct {
+		name        string
+		value       string
+		wantErrMsg  string
+		wantWrapped string
+	}{
+		{name: "PORT", value: "80", wantErrMsg: portMessage, wantWrapped: "failed variable validation: invalid port"},
+		{name: "PORT", value: "eighty", wantErrMsg: portMessage, wantWrapped: `variable PORT of type int has invalid value: "eighty"`},
+		{name: "NAMESPACE", value: "Default", wantErrMsg: "Namespace must be lowercase"},
+		{name: "PULLPOLICY", value: "IfNotPresent", wantErrMsg: "Pull policy must be Always or Never", wantWrapped: "invalid value \"IfNotPresent\" for variable PULLPOLICY. allowed values: Always, Never"},
+		{name: "TAG", value: "latest", wantErrMsg: "Tag could not be parsed", wantWrapped: "failed variable transformation: this is a failing transformer"},
+	}
+
+	for _, tt := range tests {
+		draftConfig.SetVariable(tt.name, tt.value)
+		_, err := draftConfig.GetVariableValue(tt.name)
+		assert.EqualError(t, err, tt.wantErrMsg)
+
+		var validationErr *VariableValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, tt.name, validationErr.Name)
+		assert.NotNil(t, errors.Unwrap(err))
+		if tt.wantWrapped != "" {
+			assert.EqualError(t, errors.Unwrap(err), tt.wantWrapped)
+		}
+	}
+
+	draftConfig.SetVariable("APPNAME", "My App")
+	_, err := draftConfig.GetVariableValue("APPNAME")
+	var validationErr *VariableValidationError
+	assert.False(t, errors.As(err, &validationErr))
+}
+
+func TestNameScopedValidatorsAndTransformers(t *testing.T) {
+	draftConfig := &DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "PORT", Kind: "port", Value: "80"},
+			{Name: "TARGETPORT", Kind: "port", Value: "8080"},
+			{Name: "APPNAME", Value: "My-App"},
+		},
+	}
+	draftConfig.SetVariableValidator("port", func(value string) error {
+		return fmt.Errorf("kind validator rejected %s", value)
+	})
+	draftConfig.SetVariableValidatorForName("PORT", func(string) error { return nil })
+	draftConfig.SetVariableTransformerForName("appname", func(value string) (any, error) {
+		return strings.ToLower(value), nil
+	})
+
+	value, err := draftConfig.GetVariableValue("PORT")
+	assert.Nil(t, err, "the name override takes precedence over the kind override")
+	assert.Equal(t, "80", value)
+
+	_, err = draftConfig.GetVariableValue("TARGETPORT")
+	assert.EqualError(t, err, "failed variable validation: kind validator rejected 8080", "other variables of the kind keep the kind override")
+
+	value, err = draftConfig.GetVariableValue("APPNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app", value, "name overrides match variable names like --variable flags")
+
+	copied := draftConfig.DeepCopy()
+	assert.True(t, draftConfig.Equal(copied))
+	value, err = copied.GetVariableValue("PORT")
+	assert.Nil(t, err)
+	assert.Equal(t, "80", value)
+
+	copied.SetVariableValidatorForName("TARGETPORT", func(string) error { return nil })
+	assert.False(t, draftConfig.Equal(copied))
+	_, err = draftConfig.GetVariableValue("TARGETPORT")
+	assert.NotNil(t, err, "overrides set on a copy don't affect the original")
+}
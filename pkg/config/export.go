@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	ExportFormatJSON   = "json"
+	ExportFormatDotenv = "dotenv"
+)
+
+// ExportOption configures how variables are exported
+type ExportOption func(*exportOptions)
+
+type exportOptions struct {
+	includeSensitive bool
+}
+
+// IncludeSensitive writes the values of sensitive variables instead of redacting them
+func IncludeSensitive() ExportOption {
+	return func(o *exportOptions) {
+		o.includeSensitive = true
+	}
+}
+
+// ExportVariables writes every active variable with a value to w in the given format, json or dotenv, after applying
+// its transformer. Sensitive variables are redacted unless IncludeSensitive is passed. JSON keys are the variable names,
+// while dotenv keys are upper-snake-cased and written in sorted order.
+func (d *DraftConfig) ExportVariables(format string, w io.Writer, opts ...ExportOption) error {
+	options := &exportOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	values := make(map[string]any)
+	for _, variable := range d.Variables {
+		if variable.Value == "" {
+			continue
+		}
+
+		value, err := d.variableValue(variable, variable.Value)
+		if errors.Is(err, ErrVariableInactive) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("exporting variables: %w", err)
+		}
+
+		if variable.Sensitive && !options.includeSensitive {
+			value = redactedValue
+		}
+		values[variable.Name] = value
+	}
+
+	switch format {
+	case ExportFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(values); err != nil {
+			return fmt.Errorf("exporting variables: %w", err)
+		}
+		return nil
+	case ExportFormatDotenv:
+		return writeDotenv(w, values)
+	default:
+		return fmt.Errorf("exporting variables: unsupported format %q, expected %s or %s", format, ExportFormatJSON, ExportFormatDotenv)
+	}
+}
+
+// writeDotenv writes values as double-quoted KEY="value" lines. Values that are not strings are written as JSON.
+func writeDotenv(w io.Writer, values map[string]any) error {
+	names := make(map[string]string, len(values))
+	keys := make([]string, 0, len(values))
+	for name := range values {
+		key := toUpperSnakeCase(name)
+		if otherName, ok := names[key]; ok {
+			return fmt.Errorf("exporting variables: variables %s and %s both export as %s", min(name, otherName), max(name, otherName), key)
+		}
+		names[key] = name
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value, ok := values[names[key]].(string)
+		if !ok {
+			valueBytes, err := json.Marshal(values[names[key]])
+			if err != nil {
+				return fmt.Errorf("exporting variable %s: %w", names[key], err)
+			}
+			value = string(valueBytes)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s=%s\n", key, quoteDotenvValue(value)); err != nil {
+			return fmt.Errorf("exporting variables: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// quoteDotenvValue double-quotes value, escaping the characters dotenv parsers expand inside double quotes
+func quoteDotenvValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// toUpperSnakeCase converts a variable name such as imageName, image-name or IMAGENAME to IMAGE_NAME style.
+// Word boundaries are lower-to-upper case changes and any character that is not a letter or digit.
+func toUpperSnakeCase(name string) string {
+	var builder strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if builder.Len() > 0 && !strings.HasSuffix(builder.String(), "_") {
+				builder.WriteRune('_')
+			}
+			continue
+		}
+
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			builder.WriteRune('_')
+		}
+		builder.WriteRune(unicode.ToUpper(r))
+	}
+
+	return strings.TrimSuffix(builder.String(), "_")
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportConfig() *DraftConfig {
+	return &DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "imageName", Value: "my-app"},
+			{Name: "PORT", Type: "int", Value: "08080"},
+			{Name: "ENVVARS", Kind: "envVarMap", Value: `{"A":"1"}`},
+			{Name: "token", Sensitive: true, Value: "s3cret"},
+			{Name: "NAMESPACE"},
+			{
+				Name:                  "INGRESSHOST",
+				Value:                 "example.com",
+				ActiveWhenConstraints: []ActiveWhenConstraint{{VariableName: "PORT", Value: "80", Condition: EqualTo}},
+			},
+		},
+	}
+}
+
+func TestExportVariables(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, newExportConfig().ExportVariables(ExportFormatDotenv, &buf))
+	assert.Equal(t, `ENVVARS="{\"A\":\"1\"}"
+IMAGE_NAME="my-app"
+PORT="8080"
+TOKEN="***"
+`, buf.String())
+
+	buf.Reset()
+	assert.Nil(t, newExportConfig().ExportVariables(ExportFormatJSON, &buf, IncludeSensitive()))
+	assert.JSONEq(t, `{"imageName":"my-app","PORT":"8080","ENVVARS":{"A":"1"},"token":"s3cret"}`, buf.String())
+
+	assert.EqualError(t, newExportConfig().ExportVariables("toml", &buf), `exporting variables: unsupported format "toml", expected json or dotenv`)
+
+	collidingConfig := &DraftConfig{Variables: []*BuilderVar{{Name: "imageName", Value: "a"}, {Name: "IMAGE_NAME", Value: "b"}}}
+	assert.EqualError(t, collidingConfig.ExportVariables(ExportFormatDotenv, &buf), "exporting variables: variables IMAGE_NAME and imageName both export as IMAGE_NAME")
+}
+
+func TestExportVariablesSkipsInactiveSensitiveVariables(t *testing.T) {
+	useDBConstraint := []ActiveWhenConstraint{{VariableName: "USEDB", Value: "true", Condition: EqualTo}}
+	draftConfig := &DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "USEDB", Type: "bool", Value: "false"},
+			{Name: "DBHOST", Value: "db.example.com", ActiveWhenConstraints: useDBConstraint},
+			{Name: "DBPASS", Sensitive: true, Value: "s3cret", ActiveWhenConstraints: useDBConstraint},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, draftConfig.ExportVariables(ExportFormatDotenv, &buf))
+	assert.Equal(t, "USEDB=\"false\"\n", buf.String())
+
+	draftConfig.Variables[0].Value = "true"
+	buf.Reset()
+	assert.Nil(t, draftConfig.ExportVariables(ExportFormatDotenv, &buf))
+	assert.Equal(t, `DBHOST="db.example.com"
+DBPASS="***"
+USEDB="true"
+`, buf.String())
+}
+
+func TestToUpperSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"imageName":    "IMAGE_NAME",
+		"image-name":   "IMAGE_NAME",
+		"IMAGENAME":    "IMAGENAME",
+		"IMAGE_NAME":   "IMAGE_NAME",
+		"dockerfile2x": "DOCKERFILE2X",
+		"port2Number":  "PORT2_NUMBER",
+		"-leading.":    "LEADING",
+	}
+
+	for input, want := range tests {
+		assert.Equal(t, want, toUpperSnakeCase(input), input)
+	}
+}
+
+func TestQuoteDotenvValue(t *testing.T) {
+	assert.Equal(t, `"a \"b\" \\ \$HOME\nc"`, quoteDotenvValue("a \"b\" \\ $HOME\nc"))
+}
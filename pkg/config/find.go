@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// configFileNames are the file names a draft config may be stored under, in the order FindConfigFile probes them.
+// JSON configs are read with the same yaml struct tags, as JSON is valid YAML.
+var configFileNames = []string{draftConfigFile, "draft.yml", "draft.json"}
+
+// IsConfigFileName returns true if name is one of the file names a draft config may be stored under
+func IsConfigFileName(name string) bool {
+	return slices.ContainsFunc(configFileNames, func(configFileName string) bool {
+		return strings.EqualFold(name, configFileName)
+	})
+}
+
+// FindConfigFile returns the path of the draft config in dir, probing draft.yaml, draft.yml and draft.json in that
+// order. It returns an error wrapping fs.ErrNotExist if there is none, and an error naming the files if there is more
+// than one.
+func FindConfigFile(fileSys fs.FS, dir string) (string, error) {
+	var found []string
+	for _, name := range configFileNames {
+		configPath := path.Join(dir, name)
+		if _, err := fs.Stat(fileSys, configPath); err == nil {
+			found = append(found, configPath)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("finding draft config in %s: %w", dir, err)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no draft config in %s, expected one of %s: %w", dir, strings.Join(configFileNames, ", "), fs.ErrNotExist)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous draft config in %s: found %s", dir, strings.Join(found, " and "))
+	}
+}
+
+// FindConfig loads the draft config in dir found by FindConfigFile
+func FindConfig(fileSys fs.FS, dir string, opts ...ConfigOption) (*DraftConfig, error) {
+	configPath, err := FindConfigFile(fileSys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConfigFromFS(fileSys, configPath, opts...)
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertAllFieldsSet fails for every exported field reachable from v that is left at its zero value. Function fields
+// can't be set from a config file and are skipped.
+func assertAllFieldsSet(t *testing.T, v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		assert.False(t, v.IsNil(), "%s is not set", path)
+		if !v.IsNil() {
+			assertAllFieldsSet(t, v.Elem(), path)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || field.Type.Kind() == reflect.Map && field.Type.Elem().Kind() == reflect.Func {
+				continue
+			}
+			assertAllFieldsSet(t, v.Field(i), path+"."+field.Name)
+		}
+	case reflect.Slice:
+		assert.NotZero(t, v.Len(), "%s is empty", path)
+		for i := 0; i < v.Len(); i++ {
+			assertAllFieldsSet(t, v.Index(i), path)
+		}
+	default:
+		assert.False(t, v.IsZero(), "%s is not set", path)
+	}
+}
+
+func TestFindConfigJSON(t *testing.T) {
+	draftConfig, err := FindConfig(os.DirFS("testdata"), "jsonconfig")
+	assert.Nil(t, err)
+	assert.Equal(t, "json-config", draftConfig.TemplateName)
+
+	// the last variable sets every variable field, the first one is only there to be referenced
+	draftConfig.Variables = draftConfig.Variables[1:]
+	assertAllFieldsSet(t, reflect.ValueOf(draftConfig).Elem(), "DraftConfig")
+}
+
+func TestFindConfigFile(t *testing.T) {
+	fileSys := fstest.MapFS{
+		"yml/draft.yml":         {Data: []byte("templateName: yml\n")},
+		"yaml/draft.yaml":       {Data: []byte("templateName: yaml\n")},
+		"yaml/deployment.yaml":  {Data: []byte("kind: Deployment\n")},
+		"ambiguous/draft.yml":   {Data: []byte("templateName: ambiguous\n")},
+		"ambiguous/draft.json":  {Data: []byte(`{"templateName": "ambiguous"}`)},
+		"empty/deployment.yaml": {Data: []byte("kind: Deployment\n")},
+		"ambiguous3/draft.yaml": {Data: []byte("templateName: ambiguous\n")},
+		"ambiguous3/draft.yml":  {Data: []byte("templateName: ambiguous\n")},
+		"ambiguous3/draft.json": {Data: []byte(`{"templateName": "ambiguous"}`)},
+	}
+
+	configPath, err := FindConfigFile(fileSys, "yml")
+	assert.Nil(t, err)
+	assert.Equal(t, "yml/draft.yml", configPath)
+
+	draftConfig, err := FindConfig(fileSys, "yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "yaml", draftConfig.TemplateName)
+
+	_, err = FindConfigFile(fileSys, "ambiguous")
+	assert.EqualError(t, err, "ambiguous draft config in ambiguous: found ambiguous/draft.yml and ambiguous/draft.json")
+
+	_, err = FindConfigFile(fileSys, "ambiguous3")
+	assert.EqualError(t, err, "ambiguous draft config in ambiguous3: found ambiguous3/draft.yaml and ambiguous3/draft.yml and ambiguous3/draft.json")
+
+	_, err = FindConfigFile(fileSys, "empty")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.EqualError(t, err, "no draft config in empty, expected one of draft.yaml, draft.yml, draft.json: file does not exist")
+}
+
+func TestIsConfigFileName(t *testing.T) {
+	assert.True(t, IsConfigFileName("draft.yaml"))
+	assert.True(t, IsConfigFileName("Draft.YML"))
+	assert.True(t, IsConfigFileName("draft.json"))
+	assert.False(t, IsConfigFileName("deployment.yaml"))
+}
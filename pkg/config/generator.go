@@ -0,0 +1,96 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	generatorUUID      = "uuid"
+	generatorRandomHex = "randomHex"
+	generatorTimestamp = "timestamp"
+)
+
+// GeneratorSource supplies the randomness and time used to generate default values
+type GeneratorSource struct {
+	Random io.Reader
+	Now    func() time.Time
+}
+
+// defaultGeneratorSource generates values from crypto/rand and the current time
+var defaultGeneratorSource = GeneratorSource{
+	Random: rand.Reader,
+	Now:    time.Now,
+}
+
+// SetGeneratorSource replaces the source used to generate default values, so generated values can be predicted in tests
+func (d *DraftConfig) SetGeneratorSource(source GeneratorSource) {
+	d.generatorSource = &source
+}
+
+func (d *DraftConfig) getGeneratorSource() GeneratorSource {
+	if d.generatorSource == nil {
+		return defaultGeneratorSource
+	}
+	return *d.generatorSource
+}
+
+// parseGenerator splits a generator spec such as randomHex:8 or timestamp:20060102 into its name and argument and
+// checks that it is valid
+func parseGenerator(generator string) (string, string, error) {
+	name, arg, _ := strings.Cut(generator, ":")
+	switch name {
+	case generatorUUID:
+		if arg != "" {
+			return "", "", fmt.Errorf("invalid generator %q: uuid takes no argument", generator)
+		}
+	case generatorRandomHex:
+		if length, err := strconv.Atoi(arg); err != nil || length <= 0 {
+			return "", "", fmt.Errorf("invalid generator %q: randomHex needs a positive length, e.g. randomHex:8", generator)
+		}
+	case generatorTimestamp:
+		if arg == "" {
+			arg = time.RFC3339
+		}
+	default:
+		return "", "", fmt.Errorf("invalid generator %q: expected uuid, randomHex:<length> or timestamp:<layout>", generator)
+	}
+
+	return name, arg, nil
+}
+
+// generateValue returns a new value for the variable's Default.Generator
+func (d *DraftConfig) generateValue(variable *BuilderVar) (string, error) {
+	name, arg, err := parseGenerator(variable.Default.Generator)
+	if err != nil {
+		return "", fmt.Errorf("variable %s: %w", variable.Name, err)
+	}
+
+	source := d.getGeneratorSource()
+	switch name {
+	case generatorUUID:
+		uuid := make([]byte, 16)
+		if _, err := io.ReadFull(source.Random, uuid); err != nil {
+			return "", fmt.Errorf("variable %s: generating uuid: %w", variable.Name, err)
+		}
+		// version 4, RFC 4122 variant
+		uuid[6] = uuid[6]&0x0f | 0x40
+		uuid[8] = uuid[8]&0x3f | 0x80
+		encoded := hex.EncodeToString(uuid)
+		return fmt.Sprintf("%s-%s-%s-%s-%s", encoded[0:8], encoded[8:12], encoded[12:16], encoded[16:20], encoded[20:]), nil
+	case generatorRandomHex:
+		length, _ := strconv.Atoi(arg)
+		randomBytes := make([]byte, (length+1)/2)
+		if _, err := io.ReadFull(source.Random, randomBytes); err != nil {
+			return "", fmt.Errorf("variable %s: generating random hex: %w", variable.Name, err)
+		}
+		return hex.EncodeToString(randomBytes)[:length], nil
+	default:
+		return source.Now().UTC().Format(arg), nil
+	}
+}
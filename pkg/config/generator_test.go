@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testGeneratorSource(fill byte) GeneratorSource {
+	return GeneratorSource{
+		Random: bytes.NewReader(bytes.Repeat([]byte{fill}, 64)),
+		Now: func() time.Time {
+			return time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+		},
+	}
+}
+
+func newGeneratedConfig() *DraftConfig {
+	return &DraftConfig{
+		TemplateName: "generated",
+		Variables: []*BuilderVar{
+			{Name: "RUNID", Kind: "generated", Default: BuilderVarDefault{Generator: "uuid"}},
+			{Name: "SUFFIX", Kind: "generated", Default: BuilderVarDefault{Generator: "randomHex:5"}},
+			{Name: "CREATED", Kind: "generated", Default: BuilderVarDefault{Generator: "timestamp:20060102-150405"}},
+			{Name: "DEFAULTED", Kind: "generated", Default: BuilderVarDefault{Value: "fixed", Generator: "uuid"}},
+		},
+	}
+}
+
+func TestGeneratedDefaults(t *testing.T) {
+	draftConfig := newGeneratedConfig()
+	draftConfig.SetGeneratorSource(testGeneratorSource(0xab))
+
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+	assert.Equal(t, map[string]string{
+		"RUNID":     "abababab-abab-4bab-abab-abababababab",
+		"SUFFIX":    "ababa",
+		"CREATED":   "20240506-070809",
+		"DEFAULTED": "fixed",
+	}, draftConfig.GetVariableMap())
+
+	patternConfig := &DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "SUFFIX", Kind: "generated", Pattern: "^[0-9]+$", Default: BuilderVarDefault{Generator: "randomHex:4"}},
+		},
+	}
+	patternConfig.SetGeneratorSource(testGeneratorSource(0xab))
+	assert.ErrorContains(t, patternConfig.ApplyDefaultVariables(), "variable SUFFIX has invalid default value")
+}
+
+func TestGeneratedDefaultsReuseAnswers(t *testing.T) {
+	draftConfig := newGeneratedConfig()
+	draftConfig.SetGeneratorSource(testGeneratorSource(0x01))
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+
+	var answers bytes.Buffer
+	assert.Nil(t, draftConfig.WriteAnswers(&answers, ""))
+
+	rerunConfig := newGeneratedConfig()
+	rerunConfig.SetGeneratorSource(testGeneratorSource(0x02))
+	assert.Nil(t, rerunConfig.ApplyAnswers(fstest.MapFS{"answers.yaml": {Data: answers.Bytes()}}, "answers.yaml"))
+	assert.Nil(t, rerunConfig.ApplyDefaultVariables())
+	assert.Equal(t, draftConfig.GetVariableMap(), rerunConfig.GetVariableMap())
+}
+
+func TestParseGenerator(t *testing.T) {
+	tests := []struct {
+		generator  string
+		wantName   string
+		wantArg    string
+		wantErrMsg string
+	}{
+		{generator: "uuid", wantName: "uuid"},
+		{generator: "randomHex:8", wantName: "randomHex", wantArg: "8"},
+		{generator: "timestamp", wantName: "timestamp", wantArg: time.RFC3339},
+		{generator: "timestamp:15:04", wantName: "timestamp", wantArg: "15:04"},
+		{generator: "uuid:4", wantErrMsg: `invalid generator "uuid:4": uuid takes no argument`},
+		{generator: "randomHex:0", wantErrMsg: `invalid generator "randomHex:0": randomHex needs a positive length, e.g. randomHex:8`},
+		{generator: "random", wantErrMsg: `invalid generator "random": expected uuid, randomHex:<length> or timestamp:<layout>`},
+	}
+
+	for _, tt := range tests {
+		name, arg, err := parseGenerator(tt.generator)
+		if tt.wantErrMsg != "" {
+			assert.EqualError(t, err, tt.wantErrMsg)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, tt.wantName, name)
+		assert.Equal(t, tt.wantArg, arg)
+	}
+
+	invalidConfig := DraftConfig{
+		TemplateName: "generated",
+		Variables:    []*BuilderVar{{Name: "RUNID", Default: BuilderVarDefault{Generator: "guid"}}},
+	}
+	assert.EqualError(t, invalidConfig.Validate(), `variable RUNID: invalid generator "guid": expected uuid, randomHex:<length> or timestamp:<layout>`)
+}
@@ -0,0 +1,95 @@
+package config
+
+import "fmt"
+
+// DefaultVariableGroupDisplayName is the display name of the trailing group holding variables without a group
+const DefaultVariableGroupDisplayName = "Other"
+
+// VariableGroupDefinition declares a group of related variables in draft.yaml
+type VariableGroupDefinition struct {
+	Name        string `yaml:"name"`
+	DisplayName string `yaml:"displayName"`
+	Description string `yaml:"description"`
+}
+
+// VariableGroup is a group of variables in declaration order, as returned by VariablesByGroup
+type VariableGroup struct {
+	Name        string
+	DisplayName string
+	Description string
+	Variables   []*BuilderVar
+}
+
+// VariablesByGroup returns the variables sorted into groups. Declared groups come first in declaration order, followed
+// by groups that are used by variables but not declared, in order of first use, and finally a default group with the
+// variables that have no group. Groups without variables are left out.
+func (d *DraftConfig) VariablesByGroup() []VariableGroup {
+	var groups []VariableGroup
+	groupIndex := make(map[string]int)
+	for _, definition := range d.VariableGroups {
+		if _, ok := groupIndex[definition.Name]; ok {
+			continue
+		}
+		groupIndex[definition.Name] = len(groups)
+		groups = append(groups, VariableGroup{
+			Name:        definition.Name,
+			DisplayName: definition.DisplayName,
+			Description: definition.Description,
+		})
+	}
+
+	var ungrouped []*BuilderVar
+	for _, variable := range d.Variables {
+		if variable.Group == "" {
+			ungrouped = append(ungrouped, variable)
+			continue
+		}
+
+		i, ok := groupIndex[variable.Group]
+		if !ok {
+			i = len(groups)
+			groupIndex[variable.Group] = i
+			groups = append(groups, VariableGroup{Name: variable.Group, DisplayName: variable.Group})
+		}
+		groups[i].Variables = append(groups[i].Variables, variable)
+	}
+
+	if len(ungrouped) > 0 {
+		groups = append(groups, VariableGroup{DisplayName: DefaultVariableGroupDisplayName, Variables: ungrouped})
+	}
+
+	nonEmpty := groups[:0]
+	for _, group := range groups {
+		if len(group.Variables) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+
+	return nonEmpty
+}
+
+// checkVariableGroups returns an error for every variable whose group isn't declared, if any groups are declared
+func (d *DraftConfig) checkVariableGroups() []error {
+	if len(d.VariableGroups) == 0 {
+		return nil
+	}
+
+	declared := make(map[string]bool)
+	var errs []error
+	for _, definition := range d.VariableGroups {
+		if definition.Name == "" {
+			errs = append(errs, fmt.Errorf("variableGroups: group name is empty"))
+		} else if declared[definition.Name] {
+			errs = append(errs, fmt.Errorf("variableGroups: duplicate group %s", definition.Name))
+		}
+		declared[definition.Name] = true
+	}
+
+	for _, variable := range d.Variables {
+		if variable.Group != "" && !declared[variable.Group] {
+			errs = append(errs, fmt.Errorf("variable %s: group %s is not declared in variableGroups", variable.Name, variable.Group))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariablesByGroup(t *testing.T) {
+	draftConfig := DraftConfig{
+		VariableGroups: []VariableGroupDefinition{
+			{Name: "networking", DisplayName: "Networking", Description: "ports and hosts"},
+			{Name: "image", DisplayName: "Image settings"},
+			{Name: "scaling", DisplayName: "Scaling"},
+		},
+		Variables: []*BuilderVar{
+			{Name: "APPNAME"},
+			{Name: "IMAGENAME", Group: "image"},
+			{Name: "PORT", Group: "networking"},
+			{Name: "IMAGETAG", Group: "image"},
+			{Name: "NAMESPACE"},
+			{Name: "PROBEPATH", Group: "health"},
+		},
+	}
+
+	groups := draftConfig.VariablesByGroup()
+	summary := make([][]string, len(groups))
+	for i, group := range groups {
+		summary[i] = []string{group.Name, group.DisplayName}
+		for _, variable := range group.Variables {
+			summary[i] = append(summary[i], variable.Name)
+		}
+	}
+
+	assert.Equal(t, [][]string{
+		{"networking", "Networking", "PORT"},
+		{"image", "Image settings", "IMAGENAME", "IMAGETAG"},
+		{"health", "health", "PROBEPATH"},
+		{"", DefaultVariableGroupDisplayName, "APPNAME", "NAMESPACE"},
+	}, summary)
+	assert.Equal(t, "ports and hosts", groups[0].Description)
+
+	assert.Empty(t, (&DraftConfig{}).VariablesByGroup())
+}
+
+func TestValidateVariableGroups(t *testing.T) {
+	draftConfig := DraftConfig{
+		TemplateName:   "groups",
+		VariableGroups: []VariableGroupDefinition{{Name: "image"}, {Name: "image"}, {}},
+		Variables:      []*BuilderVar{{Name: "PORT", Group: "networking"}},
+	}
+
+	assert.EqualError(t, draftConfig.Validate(), `variableGroups: duplicate group image
+variableGroups: group name is empty
+variable PORT: group networking is not declared in variableGroups`)
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// interpolationPattern matches ${VARNAME} references in default values, along with the escaped form $${VARNAME}
+var interpolationPattern = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// interpolationReferences returns the names of the variables referenced with ${VARNAME} in value
+func interpolationReferences(value string) []string {
+	var names []string
+	for _, match := range interpolationPattern.FindAllStringSubmatch(value, -1) {
+		if !strings.HasPrefix(match[0], "$$") {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// defaultValueReferences returns the names of the variables referenced with ${VARNAME} in any of the variable's default values
+func (bv *BuilderVar) defaultValueReferences() []string {
+	names := interpolationReferences(bv.Default.Value)
+	for _, versionedDefault := range bv.Default.VersionedDefaults {
+		names = append(names, interpolationReferences(versionedDefault.Value)...)
+	}
+
+	return names
+}
+
+// interpolateDefaultValue expands the ${VARNAME} references in a default value of variable with the resolved values
+// of the referenced variables, and turns $${VARNAME} into a literal ${VARNAME}. path holds the variables being resolved
+// and is used to detect cycles.
+func (d *DraftConfig) interpolateDefaultValue(variable *BuilderVar, value string, path []string) (string, error) {
+	return d.interpolateValue(variable, "default value", value, path)
+}
+
+// interpolateValue expands ${VARNAME} references like interpolateDefaultValue in any setting of variable, described
+// in errors by setting
+func (d *DraftConfig) interpolateValue(variable *BuilderVar, setting, value string, path []string) (string, error) {
+	var interpolateErr error
+	expanded := interpolationPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if interpolateErr != nil {
+			return match
+		}
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		name := match[2 : len(match)-1]
+		referenceVar, err := d.GetVariable(name)
+		if err != nil {
+			interpolateErr = fmt.Errorf("variable %s: unknown variable %s in %s %q", variable.Name, name, setting, value)
+			return match
+		}
+
+		referenceVal, err := d.recurseReferenceVars(referenceVar, path)
+		if err != nil {
+			interpolateErr = err
+			return match
+		}
+		if referenceVal == "" {
+			interpolateErr = fmt.Errorf("variable %s: variable %s in %s %q has no value", variable.Name, name, setting, value)
+			return match
+		}
+
+		return referenceVal
+	})
+	if interpolateErr != nil {
+		return "", interpolateErr
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolatedDefaults(t *testing.T) {
+	tests := []struct {
+		testName   string
+		variables  []*BuilderVar
+		want       map[string]string
+		wantErrMsg string
+	}{
+		{
+			testName: "interpolatesResolvedValues",
+			variables: []*BuilderVar{
+				{Name: "IMAGE", Default: BuilderVarDefault{Value: "${IMAGENAME}:${IMAGETAG}"}},
+				{Name: "IMAGENAME", Value: "my-app"},
+				{Name: "IMAGETAG", Default: BuilderVarDefault{Value: "latest"}},
+			},
+			want: map[string]string{"IMAGE": "my-app:latest", "IMAGENAME": "my-app", "IMAGETAG": "latest"},
+		},
+		{
+			testName: "mixedWithReferenceVar",
+			variables: []*BuilderVar{
+				{Name: "SERVICENAME", Default: BuilderVarDefault{ReferenceVar: "DEPLOYMENTNAME"}},
+				{Name: "DEPLOYMENTNAME", Default: BuilderVarDefault{Value: "${APPNAME}-deployment"}},
+				{Name: "INGRESSNAME", Default: BuilderVarDefault{Value: "${SERVICENAME}-ingress"}},
+				{Name: "APPNAME", Default: BuilderVarDefault{ReferenceVar: "REPONAME"}},
+				{Name: "REPONAME", Value: "draft"},
+			},
+			want: map[string]string{
+				"SERVICENAME":    "draft-deployment",
+				"DEPLOYMENTNAME": "draft-deployment",
+				"INGRESSNAME":    "draft-deployment-ingress",
+				"APPNAME":        "draft",
+				"REPONAME":       "draft",
+			},
+		},
+		{
+			testName: "escapedReference",
+			variables: []*BuilderVar{
+				{Name: "COMMAND", Default: BuilderVarDefault{Value: "echo $${HOME} ${USER}"}},
+				{Name: "USER", Value: "draft"},
+			},
+			want: map[string]string{"COMMAND": "echo ${HOME} draft", "USER": "draft"},
+		},
+		{
+			testName: "unknownVariable",
+			variables: []*BuilderVar{
+				{Name: "IMAGE", Default: BuilderVarDefault{Value: "${IMAGENAME}:latest"}},
+			},
+			wantErrMsg: `apply default variables: variable IMAGE: unknown variable IMAGENAME in default value "${IMAGENAME}:latest"`,
+		},
+		{
+			testName: "cycleThroughInterpolation",
+			variables: []*BuilderVar{
+				{Name: "A", Default: BuilderVarDefault{Value: "${B}"}},
+				{Name: "B", Default: BuilderVarDefault{ReferenceVar: "A"}},
+			},
+			wantErrMsg: "apply default variables: cyclical reference detected: A → B → A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			draftConfig := DraftConfig{Variables: tt.variables}
+			err := draftConfig.ApplyDefaultVariables()
+			if tt.wantErrMsg != "" {
+				assert.EqualError(t, err, tt.wantErrMsg)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, draftConfig.GetVariableMap())
+		})
+	}
+}
+
+func TestInterpolationReferences(t *testing.T) {
+	assert.Equal(t, []string{"IMAGENAME", "IMAGETAG"}, interpolationReferences("${IMAGENAME}:${IMAGETAG}"))
+	assert.Nil(t, interpolationReferences("$${IMAGENAME} {{IMAGETAG}}"))
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/Azure/draft/pkg/config/schema"
+)
+
+// schemaOverrides describes the fields whose custom unmarshalling accepts more than their Go type suggests
+var schemaOverrides = map[string]*schema.Schema{
+	// the default value of an array variable may be written as a YAML list
+	"BuilderVarDefault.value": {
+		Type:  []string{"string", "number", "boolean", "array"},
+		Items: &schema.Schema{Type: []string{"string", "number", "boolean"}},
+	},
+}
+
+// GenerateSchema returns the JSON Schema for draft.yaml files generated from DraftConfig. The generated schema is
+// checked in as schema.DraftConfigSchema and kept in sync by TestSchemaUpToDate.
+func GenerateSchema() ([]byte, error) {
+	s := schema.Generate(reflect.TypeOf(DraftConfig{}), schemaOverrides)
+	schemaBytes, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(schemaBytes, '\n'), nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Azure/draft/pkg/config/schema"
+	"github.com/Azure/draft/template"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSchemaUpToDate fails when the checked in draft.yaml schema no longer matches DraftConfig.
+// Regenerate it with UPDATE_SCHEMA=true go test ./pkg/config -run TestSchemaUpToDate
+func TestSchemaUpToDate(t *testing.T) {
+	generated, err := GenerateSchema()
+	assert.Nil(t, err)
+
+	if os.Getenv("UPDATE_SCHEMA") == "true" {
+		assert.Nil(t, os.WriteFile("schema/draft.schema.json", generated, 0644))
+		return
+	}
+
+	assert.Equal(t, string(generated), string(schema.DraftConfigSchema()), "draft.yaml schema is out of date, regenerate it with UPDATE_SCHEMA=true go test ./pkg/config -run TestSchemaUpToDate")
+}
+
+func TestTemplatesMatchSchema(t *testing.T) {
+	err := fs.WalkDir(template.Templates, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(d.Name(), "draft.yaml") {
+			return err
+		}
+
+		_, err = NewConfigFromFS(template.Templates, path, Strict())
+		assert.Nil(t, err, path)
+		return nil
+	})
+	assert.Nil(t, err)
+}
+
+func TestStrictConfig(t *testing.T) {
+	_, err := NewConfigFromFS(os.DirFS("testdata"), "strict_invalid.yaml", Strict())
+	assert.EqualError(t, err, `invalid draft config strict_invalid.yaml: line 4: (root): unknown field "defaultVersions"
+line 9: variables[0]: unknown field "defualt"
+line 13: variables[1].default: unknown field "disablPrompt"`)
+
+	_, err = NewConfigFromFS(os.DirFS("testdata"), "strict_invalid.yaml")
+	assert.Nil(t, err)
+}
+
+func TestNewConfigFromBytesStrict(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		wantErrMsg string
+	}{
+		{
+			fixture: "misspelled_top_level.yaml",
+			wantErrMsg: `invalid draft config: line 2: (root): unknown field "dispalyName"
+line 7: (root): unknown field "variabels"`,
+		},
+		{
+			fixture: "misspelled_nested.yaml",
+			wantErrMsg: `invalid draft config: line 8: variables[0]: unknown field "descripton"
+line 13: variables[1].default: unknown field "disablePromt"
+line 17: variables[1].activeWhen[0]: unknown field "vaule"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			configBytes, err := os.ReadFile("testdata/" + tt.fixture)
+			assert.Nil(t, err)
+
+			_, err = NewConfigFromBytes(configBytes, true)
+			assert.EqualError(t, err, tt.wantErrMsg)
+
+			_, err = NewConfigFromBytes(configBytes, false, Strict())
+			assert.EqualError(t, err, tt.wantErrMsg)
+
+			draftConfig, err := NewConfigFromBytes(configBytes, false)
+			assert.Nil(t, err)
+			assert.NotEmpty(t, draftConfig.TemplateName)
+		})
+	}
+
+	draftConfig, err := NewConfigFromBytes([]byte("templateName: strict\nvariables:\n  - name: APPNAME\n"), true)
+	assert.Nil(t, err)
+	assert.Equal(t, "APPNAME", draftConfig.Variables[0].Name)
+
+	_, err = NewConfigFromBytes([]byte("variables:\n  - name: APPNAME\n"), true)
+	assert.EqualError(t, err, "invalid draft config: templateName is empty")
+}
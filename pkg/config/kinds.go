@@ -0,0 +1,81 @@
+package config
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Azure/draft/pkg/config/transformers"
+	"github.com/Azure/draft/pkg/config/validators"
+)
+
+const (
+	// displayNameKind marks free text shown to people, such as a product name. Variables of other kinds that default to
+	// a displayName variable through Default.ReferenceVar receive its value as a slug.
+	displayNameKind = "displayName"
+	slugKind        = "slug"
+)
+
+// kindChainSeparator separates the kinds of a variable whose kind chains several kinds, e.g. trimSpace,lowercase,kubeName
+const kindChainSeparator = ","
+
+// descriptiveKinds are the kinds that have neither a validator nor a transformer and only describe the value, e.g. to
+// prompts. Every other known kind comes from the validator and transformer registries.
+var descriptiveKinds = []string{
+	"azureContainerRegistry",
+	"azureKeyvaultUri",
+	"azureManagedCluster",
+	"azureServiceConnection",
+	"clusterResourceType",
+	"containerImageName",
+	"containerImageVersion",
+	"dirPath",
+	"displayName",
+	"dockerFileName",
+	"filePath",
+	"flag",
+	"generated",
+	"helmChartOverrides",
+	"ingressHostName",
+	"kubernetesProbeDelay",
+	"kubernetesProbeHttpPath",
+	"kubernetesProbePeriod",
+	"kubernetesProbeThreshold",
+	"kubernetesProbeTimeout",
+	"kubernetesResourceLimit",
+	"kubernetesResourceRequest",
+	"label",
+	"port",
+	"repositoryBranch",
+	"resourceLimit",
+	"scalingResourceUtilization",
+	"workflowName",
+}
+
+// isKnownKind returns true if the kind is built into draft, registered with validators.Register or
+// transformers.Register or has a validator or transformer override set on the config
+func (d *DraftConfig) isKnownKind(kind string) bool {
+	if _, ok := d.Validators[kind]; ok {
+		return true
+	}
+
+	if _, ok := d.Transformers[kind]; ok {
+		return true
+	}
+
+	return slices.Contains(descriptiveKinds, kind) || slices.Contains(validators.List(), kind) || transformers.Exists(kind)
+}
+
+// isKindChain returns true if kind chains several kinds
+func isKindChain(kind string) bool {
+	return strings.Contains(kind, kindChainSeparator)
+}
+
+// chainedKinds returns the kinds chained in kind in the order their transformers run, or kind itself if it is a single
+// kind
+func chainedKinds(kind string) []string {
+	kinds := strings.Split(kind, kindChainSeparator)
+	for i := range kinds {
+		kinds[i] = strings.TrimSpace(kinds[i])
+	}
+	return kinds
+}
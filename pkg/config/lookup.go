@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxVariableSuggestions is the number of similarly named variables suggested when a lookup fails
+const maxVariableSuggestions = 3
+
+// GetVariableFold returns the variable whose name or alias matches name, ignoring case when there is no exact match
+func (d *DraftConfig) GetVariableFold(name string) (*BuilderVar, error) {
+	if variable, err := d.GetVariable(name); err == nil {
+		return variable, nil
+	}
+
+	for _, variable := range d.Variables {
+		if strings.EqualFold(variable.Name, name) {
+			return variable, nil
+		}
+	}
+
+	for _, variable := range d.Variables {
+		for _, alias := range variable.Aliases {
+			if strings.EqualFold(alias, name) {
+				log.Warnf("Variable %s is deprecated, use %s instead", alias, variable.Name)
+				return variable, nil
+			}
+		}
+	}
+
+	return nil, d.variableNotFoundError(name)
+}
+
+// getFlagVariable looks up a variable set from the command line, matching case-insensitively unless the template
+// sets CaseSensitiveVariables
+func (d *DraftConfig) getFlagVariable(name string) (*BuilderVar, error) {
+	if d.CaseSensitiveVariables {
+		return d.GetVariable(name)
+	}
+
+	return d.GetVariableFold(name)
+}
+
+// variableNotFoundError returns the error for a failed lookup of name, suggesting the closest variable names
+func (d *DraftConfig) variableNotFoundError(name string) error {
+	suggestions := d.suggestVariableNames(name)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("variable %s not found", name)
+	}
+
+	return fmt.Errorf("variable %s not found, did you mean %s?", name, strings.Join(suggestions, ", "))
+}
+
+// suggestVariableNames returns up to maxVariableSuggestions variable names closest to name by case-insensitive edit
+// distance. Names that differ in more than a third of their characters are not considered close.
+func (d *DraftConfig) suggestVariableNames(name string) []string {
+	type suggestion struct {
+		name     string
+		distance int
+	}
+
+	lowerName := strings.ToLower(name)
+	var suggestions []suggestion
+	for _, variable := range d.Variables {
+		distance := editDistance(lowerName, strings.ToLower(variable.Name))
+		if distance <= max(len(name), len(variable.Name))/3 {
+			suggestions = append(suggestions, suggestion{name: variable.Name, distance: distance})
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	names := make([]string, 0, maxVariableSuggestions)
+	for i := 0; i < len(suggestions) && i < maxVariableSuggestions; i++ {
+		names = append(names, suggestions[i].name)
+	}
+
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	aRunes, bRunes := []rune(a), []rune(b)
+	previous := make([]int, len(bRunes)+1)
+	current := make([]int, len(bRunes)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(aRunes); i++ {
+		current[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			cost := 1
+			if aRunes[i-1] == bRunes[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(bRunes)]
+}
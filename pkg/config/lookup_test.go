@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVariableFold(t *testing.T) {
+	draftConfig := DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "IMAGENAME", Aliases: []string{"IMAGE"}},
+			{Name: "IMAGETAG"},
+			{Name: "PORT"},
+		},
+	}
+
+	variable, err := draftConfig.GetVariableFold("imageName")
+	assert.Nil(t, err)
+	assert.Equal(t, "IMAGENAME", variable.Name)
+
+	variable, err = draftConfig.GetVariableFold("image")
+	assert.Nil(t, err)
+	assert.Equal(t, "IMAGENAME", variable.Name)
+
+	_, err = draftConfig.GetVariable("imageName")
+	assert.EqualError(t, err, "variable imageName not found, did you mean IMAGENAME, IMAGETAG?")
+
+	_, err = draftConfig.GetVariableFold("IMAGENAM")
+	assert.EqualError(t, err, "variable IMAGENAM not found, did you mean IMAGENAME, IMAGETAG?")
+
+	_, err = draftConfig.GetVariableFold("NAMESPACE")
+	assert.EqualError(t, err, "variable NAMESPACE not found")
+}
+
+func TestVariableMapToDraftConfigCaseInsensitive(t *testing.T) {
+	draftConfig := DraftConfig{Variables: []*BuilderVar{{Name: "PORT"}}}
+	assert.Nil(t, draftConfig.VariableMapToDraftConfig(map[string]string{"port": "8080"}))
+	assert.Equal(t, map[string]string{"PORT": "8080"}, draftConfig.GetVariableMap())
+
+	assert.EqualError(t, draftConfig.VariableMapToDraftConfig(map[string]string{"port": "8080", "PORT": "80"}),
+		"conflicting values for variable PORT set through both PORT and port")
+
+	strictConfig := DraftConfig{CaseSensitiveVariables: true, Variables: []*BuilderVar{{Name: "PORT"}}}
+	assert.Nil(t, strictConfig.VariableMapToDraftConfig(map[string]string{"port": "8080"}))
+	assert.Equal(t, map[string]string{"PORT": "", "port": "8080"}, strictConfig.GetVariableMap())
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("port", "port"))
+	assert.Equal(t, 1, editDistance("imagenam", "imagename"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 4, editDistance("", "port"))
+}
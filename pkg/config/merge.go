@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Merge overlays another draft config onto d. Display metadata set in the overlay replaces d's, new variables are
+// appended, and variables present in both take the overlay's Default, Description, Kind and ExampleValues while
+// keeping any Value already set on d. FileNameOverrideMap and the validator and transformer maps are merged key by key with
+// the overlay winning. Variables whose Versions ranges differ are reported as an error and d is left unchanged.
+func (d *DraftConfig) Merge(overlay *DraftConfig) error {
+	if overlay == nil {
+		return nil
+	}
+
+	for _, overlayVar := range overlay.Variables {
+		baseVar, err := d.GetVariable(overlayVar.Name)
+		if err != nil {
+			continue
+		}
+
+		if overlayVar.Versions != "" && baseVar.Versions != "" && overlayVar.Versions != baseVar.Versions {
+			return fmt.Errorf("merge draft config: variable %s has conflicting versions %s and %s", overlayVar.Name, baseVar.Versions, overlayVar.Versions)
+		}
+	}
+
+	if overlay.DisplayName != "" {
+		d.DisplayName = overlay.DisplayName
+	}
+	if overlay.Description != "" {
+		d.Description = overlay.Description
+	}
+
+	for _, overlayVar := range overlay.Variables {
+		baseVar, err := d.GetVariable(overlayVar.Name)
+		if err != nil {
+			d.Variables = append(d.Variables, overlayVar.DeepCopy())
+			continue
+		}
+
+		overlayCopy := overlayVar.DeepCopy()
+		baseVar.Default = overlayCopy.Default
+		baseVar.Description = overlayCopy.Description
+		baseVar.Kind = overlayCopy.Kind
+		baseVar.ExampleValues = overlayCopy.ExampleValues
+		if baseVar.Value == "" {
+			baseVar.Value = overlayCopy.Value
+		}
+		if baseVar.Versions == "" {
+			baseVar.Versions = overlayCopy.Versions
+		}
+	}
+
+	for k, v := range overlay.FileNameOverrideMap {
+		d.SetFileNameOverride(k, v)
+	}
+
+	for kind, validator := range overlay.Validators {
+		d.SetVariableValidator(kind, validator)
+	}
+
+	for kind, transformer := range overlay.Transformers {
+		d.SetVariableTransformer(kind, transformer)
+	}
+
+	for name, validator := range overlay.VariableValidators {
+		d.SetVariableValidatorForName(name, validator)
+	}
+
+	for name, transformer := range overlay.VariableTransformers {
+		d.SetVariableTransformerForName(name, transformer)
+	}
+
+	return nil
+}
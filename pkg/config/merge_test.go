@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	base := &DraftConfig{
+		TemplateName: "base",
+		DisplayName:  "Base",
+		Description:  "base description",
+		Variables: []*BuilderVar{
+			{
+				Name:          "PORT",
+				Kind:          "port",
+				Description:   "the port",
+				Value:         "8080",
+				Versions:      ">=0.0.1",
+				Default:       BuilderVarDefault{Value: "80"},
+				ExampleValues: []string{"80"},
+			},
+			{
+				Name:     "APPNAME",
+				Versions: ">=0.0.1",
+				Default:  BuilderVarDefault{Value: "base-app"},
+			},
+		},
+		FileNameOverrideMap: map[string]string{"deployment.yaml": "base-deployment.yaml", "service.yaml": "base-service.yaml"},
+	}
+	base.SetVariableValidator("port", func(string) error { return errors.New("base validator") })
+
+	overlay := &DraftConfig{
+		DisplayName: "Overlay",
+		Variables: []*BuilderVar{
+			{
+				Name:          "PORT",
+				Kind:          "containerPort",
+				Description:   "the container port",
+				Value:         "9090",
+				Versions:      ">=0.0.1",
+				Default:       BuilderVarDefault{Value: "443"},
+				ExampleValues: []string{"443", "8443"},
+			},
+			{
+				Name:    "APPNAME",
+				Default: BuilderVarDefault{Value: "overlay-app"},
+			},
+			{
+				Name:    "NAMESPACE",
+				Default: BuilderVarDefault{Value: "default"},
+			},
+		},
+		FileNameOverrideMap: map[string]string{"deployment.yaml": "overlay-deployment.yaml"},
+	}
+	overlay.SetVariableValidator("port", func(string) error { return errors.New("overlay validator") })
+
+	assert.Nil(t, base.Merge(overlay))
+
+	assert.Equal(t, "Overlay", base.DisplayName)
+	assert.Equal(t, "base description", base.Description)
+	assert.Len(t, base.Variables, 3)
+
+	port, err := base.GetVariable("PORT")
+	assert.Nil(t, err)
+	assert.Equal(t, "8080", port.Value)
+	assert.Equal(t, "containerPort", port.Kind)
+	assert.Equal(t, "the container port", port.Description)
+	assert.Equal(t, "443", port.Default.Value)
+	assert.Equal(t, []string{"443", "8443"}, port.ExampleValues)
+
+	appName, err := base.GetVariable("APPNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "overlay-app", appName.Default.Value)
+	assert.Equal(t, ">=0.0.1", appName.Versions)
+
+	namespace, err := base.GetVariable("NAMESPACE")
+	assert.Nil(t, err)
+	assert.Equal(t, "default", namespace.Default.Value)
+	assert.NotSame(t, overlay.Variables[2], namespace)
+
+	assert.Equal(t, map[string]string{"deployment.yaml": "overlay-deployment.yaml", "service.yaml": "base-service.yaml"}, base.FileNameOverrideMap)
+	assert.EqualError(t, base.GetVariableValidator("port")(""), "overlay validator")
+
+	conflicting := &DraftConfig{
+		DisplayName: "Conflicting",
+		Variables:   []*BuilderVar{{Name: "PORT", Versions: ">=0.0.2"}},
+	}
+	assert.EqualError(t, base.Merge(conflicting), "merge draft config: variable PORT has conflicting versions >=0.0.1 and >=0.0.2")
+	assert.Equal(t, "Overlay", base.DisplayName)
+}
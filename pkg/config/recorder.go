@@ -0,0 +1,31 @@
+package config
+
+import (
+	"maps"
+	"sync"
+)
+
+// MemoryRecorder is a TemplateVariableRecorder that keeps the last value recorded for each variable in memory
+type MemoryRecorder struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func NewMemoryRecorder() *MemoryRecorder {
+	return &MemoryRecorder{
+		values: make(map[string]string),
+	}
+}
+
+func (r *MemoryRecorder) Record(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = value
+}
+
+// Values returns a copy of the recorded variable values
+func (r *MemoryRecorder) Values() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.values)
+}
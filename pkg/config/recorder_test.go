@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftConfigRecorder(t *testing.T) {
+	draftConfig := &DraftConfig{
+		Versions: []string{"0.0.1"},
+		Variables: []*BuilderVar{
+			{Name: "APPNAME", Versions: ">=0.0.1"},
+			{Name: "PORT", Versions: ">=0.0.1", Default: BuilderVarDefault{Value: "80"}},
+			{Name: "TOKEN", Versions: ">=0.0.1", Sensitive: true, Default: BuilderVarDefault{Value: "secret"}},
+			{Name: "UNUSED", Versions: ">=0.0.1", Value: "unused"},
+		},
+	}
+	recorder := NewMemoryRecorder()
+	draftConfig.SetRecorder(recorder)
+
+	draftConfig.SetVariable("APPNAME", "my-app")
+	assert.Equal(t, map[string]string{"APPNAME": "my-app"}, recorder.Values())
+
+	assert.Nil(t, draftConfig.ApplyDefaultVariablesForVersion("0.0.1"))
+	assert.Equal(t, map[string]string{"APPNAME": "my-app", "PORT": "80", "TOKEN": "***"}, recorder.Values())
+
+	value, err := draftConfig.GetVariableValue("UNUSED")
+	assert.Nil(t, err)
+	assert.Equal(t, "unused", value)
+	assert.Equal(t, "unused", recorder.Values()["UNUSED"])
+
+	// copies keep recording to the same recorder
+	draftConfig.DeepCopy().SetVariable("PORT", "8080")
+	assert.Equal(t, "8080", recorder.Values()["PORT"])
+}
@@ -0,0 +1,127 @@
+// Package schema generates a JSON Schema for draft.yaml files and validates draft.yaml files against it.
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const draftSchemaURI = "https://json-schema.org/draft-07/schema#"
+
+//go:embed draft.schema.json
+var draftConfigSchema []byte
+
+// DraftConfigSchema returns the JSON Schema for draft.yaml files
+func DraftConfigSchema() []byte {
+	return bytes.Clone(draftConfigSchema)
+}
+
+// Schema is the subset of JSON Schema needed to describe a draft.yaml file
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 []string           `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	// False makes the schema the boolean schema false, which no value matches
+	False bool `json:"-"`
+}
+
+// MarshalJSON writes a False schema as the boolean schema false
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.False {
+		return []byte("false"), nil
+	}
+
+	type schema Schema
+	return json.Marshal((*schema)(s))
+}
+
+// UnmarshalJSON reads the boolean schema false into a False schema
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "false" {
+		*s = Schema{False: true}
+		return nil
+	}
+
+	type schema Schema
+	return json.Unmarshal(data, (*schema)(s))
+}
+
+// Generate returns the schema of the YAML representation of t, following the yaml struct tags of its fields. Structs
+// don't allow unknown fields, and fields of function types are left out since they can't be set from YAML. overrides
+// replaces the schema of individual fields, keyed by struct type name and YAML field name, e.g. "BuilderVarDefault.value",
+// for fields with custom unmarshalling.
+func Generate(t reflect.Type, overrides map[string]*Schema) *Schema {
+	s := generate(t, overrides)
+	if s == nil {
+		s = &Schema{}
+	}
+	s.Schema = draftSchemaURI
+	s.Title = t.Name()
+	return s
+}
+
+func generate(t reflect.Type, overrides map[string]*Schema) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return generate(t.Elem(), overrides)
+	case reflect.String:
+		// YAML scalars of any type unmarshal into string fields
+		return &Schema{Type: []string{"string", "number", "boolean"}}
+	case reflect.Bool:
+		return &Schema{Type: []string{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: []string{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: []string{"number"}}
+	case reflect.Slice, reflect.Array:
+		items := generate(t.Elem(), overrides)
+		if items == nil {
+			return nil
+		}
+		return &Schema{Type: []string{"array"}, Items: items}
+	case reflect.Map:
+		values := generate(t.Elem(), overrides)
+		if values == nil || t.Key().Kind() != reflect.String {
+			return nil
+		}
+		return &Schema{Type: []string{"object"}, AdditionalProperties: values}
+	case reflect.Struct:
+		s := &Schema{
+			Type:                 []string{"object"},
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: &Schema{False: true},
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+
+			if override, ok := overrides[t.Name()+"."+name]; ok {
+				s.Properties[name] = override
+			} else if fieldSchema := generate(field.Type, overrides); fieldSchema != nil {
+				s.Properties[name] = fieldSchema
+			}
+		}
+		return s
+	case reflect.Interface:
+		return &Schema{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testVariable struct {
+	Name     string            `yaml:"name"`
+	Enabled  bool              `yaml:"enabled"`
+	Replicas *int              `yaml:"replicas"`
+	Labels   map[string]string `yaml:"labels"`
+	Hooks    []func()          `yaml:"hooks"`
+	Ignored  string            `yaml:"-"`
+	internal string
+}
+
+type testConfig struct {
+	Title     string         `yaml:"title,omitempty"`
+	Variables []testVariable `yaml:"variables"`
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testConfig{}), map[string]*Schema{
+		"testVariable.name": {Type: []string{"string"}},
+	})
+
+	assert.Equal(t, draftSchemaURI, s.Schema)
+	assert.Equal(t, "testConfig", s.Title)
+	assert.Equal(t, []string{"title", "variables"}, sortedKeys(s.Properties))
+
+	variable := s.Properties["variables"].Items
+	assert.Equal(t, []string{"enabled", "labels", "name", "replicas"}, sortedKeys(variable.Properties))
+	assert.Equal(t, []string{"string"}, variable.Properties["name"].Type)
+	assert.Equal(t, []string{"integer"}, variable.Properties["replicas"].Type)
+	assert.True(t, variable.AdditionalProperties.False)
+	assert.Equal(t, []string{"string", "number", "boolean"}, variable.Properties["labels"].AdditionalProperties.Type)
+
+	schemaBytes, err := json.Marshal(s)
+	assert.Nil(t, err)
+	var roundTripped Schema
+	assert.Nil(t, json.Unmarshal(schemaBytes, &roundTripped))
+	assert.Equal(t, s, &roundTripped)
+}
+
+func TestValidate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testConfig{}), nil)
+
+	assert.Nil(t, Validate(s, []byte(`title: example
+variables:
+  - name: APPNAME
+    enabled: true
+    replicas: 3
+    labels:
+      app: example
+      version: 2
+  - name: PORT
+    labels:
+`)))
+
+	err := Validate(s, []byte(`title: example
+variables:
+  - name: APPNAME
+    enabeld: true
+  - name: PORT
+    replicas: three
+    labels: [a, b]
+`))
+	assert.EqualError(t, err, `line 4: variables[0]: unknown field "enabeld"
+line 6: variables[1].replicas: expected integer, got string
+line 7: variables[1].labels: expected object, got array`)
+
+	assert.EqualError(t, Validate(s, []byte("- a\n- b\n")), "line 1: (root): expected object, got array")
+	assert.Nil(t, Validate(s, []byte("")))
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	assert.Nil(t, ValidateAgainstSchema([]byte(`templateName: example
+versions: ["0.0.1"]
+variables:
+  - name: HOSTS
+    type: array
+    default:
+      value: [a.example.com, b.example.com]
+`)))
+
+	assert.EqualError(t, ValidateAgainstSchema([]byte(`templateName: example
+variables:
+  - name: PORT
+    default:
+      disablePrompt: sometimes
+`)), "line 5: variables[0].default.disablePrompt: expected boolean, got string")
+}
+
+func sortedKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
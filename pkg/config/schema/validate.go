@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateAgainstSchema validates a draft.yaml file against DraftConfigSchema, reporting every unknown field and
+// value of the wrong type with its line and field path
+func ValidateAgainstSchema(configBytes []byte) error {
+	var s Schema
+	if err := json.Unmarshal(draftConfigSchema, &s); err != nil {
+		return fmt.Errorf("loading draft config schema: %w", err)
+	}
+
+	return Validate(&s, configBytes)
+}
+
+// Validate validates a YAML document against s
+func Validate(s *Schema, document []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(document, &root); err != nil {
+		return fmt.Errorf("parsing document: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	var errs []error
+	validateNode(s, root.Content[0], "", &errs)
+	return errors.Join(errs...)
+}
+
+func validateNode(s *Schema, node *yaml.Node, path string, errs *[]error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	nodeType := yamlNodeType(node)
+	if nodeType == "null" {
+		return
+	}
+
+	if s.False {
+		*errs = append(*errs, fmt.Errorf("line %d: %s: value is not allowed", node.Line, displayPath(path)))
+		return
+	}
+
+	if len(s.Type) > 0 && !slices.Contains(s.Type, nodeType) && !(nodeType == "integer" && slices.Contains(s.Type, "number")) {
+		*errs = append(*errs, fmt.Errorf("line %d: %s: expected %s, got %s", node.Line, displayPath(path), strings.Join(s.Type, " or "), nodeType))
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := key.Value
+			if path != "" {
+				fieldPath = path + "." + key.Value
+			}
+
+			if propertySchema, ok := s.Properties[key.Value]; ok {
+				validateNode(propertySchema, value, fieldPath, errs)
+				continue
+			}
+
+			if s.AdditionalProperties == nil {
+				continue
+			}
+			if s.AdditionalProperties.False {
+				*errs = append(*errs, fmt.Errorf("line %d: %s: unknown field %q", key.Line, displayPath(path), key.Value))
+				continue
+			}
+			validateNode(s.AdditionalProperties, value, fieldPath, errs)
+		}
+	case yaml.SequenceNode:
+		if s.Items == nil {
+			return
+		}
+		for i, item := range node.Content {
+			validateNode(s.Items, item, path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+// yamlNodeType returns the JSON Schema type of a YAML node
+func yamlNodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.ShortTag() {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	default:
+		return "string"
+	}
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"slices"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SharedVariableStore shares variable values between the draft configs bound to it, so a value set on one config
+// populates the variables with the same name in the others
+type SharedVariableStore struct {
+	mu      sync.Mutex
+	values  map[string]string
+	configs []*DraftConfig
+}
+
+func NewSharedVariableStore() *SharedVariableStore {
+	return &SharedVariableStore{
+		values: make(map[string]string),
+	}
+}
+
+// Value returns the last value shared for the variable name
+func (s *SharedVariableStore) Value(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[name]
+	return value, ok
+}
+
+// BindSharedStore attaches d to store. Values already in the store are applied to matching variables of d that have
+// no value, and from then on SetVariable on d or any other bound config updates matching variables in all of them.
+// Copies made with DeepCopy are not bound.
+func (d *DraftConfig) BindSharedStore(store *SharedVariableStore) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if slices.Contains(store.configs, d) {
+		return
+	}
+
+	for _, variable := range d.Variables {
+		for _, other := range store.configs {
+			otherVar, err := other.GetVariable(variable.Name)
+			if err == nil && otherVar.Kind != variable.Kind {
+				log.Warnf("Shared variable %s has kind %s in template %s and kind %s in template %s", variable.Name, variable.Kind, d.TemplateName, otherVar.Kind, other.TemplateName)
+				break
+			}
+		}
+
+		if value, ok := store.values[variable.Name]; ok && variable.Value == "" {
+			d.setSharedValue(variable, value)
+		}
+	}
+
+	store.configs = append(store.configs, d)
+	d.sharedStore = store
+}
+
+// share records a value set on source and copies it into the matching variables of the other bound configs
+func (s *SharedVariableStore) share(source *DraftConfig, name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[name] = value
+	for _, config := range s.configs {
+		if config == source {
+			continue
+		}
+
+		if variable, err := config.GetVariable(name); err == nil {
+			config.setSharedValue(variable, value)
+		}
+	}
+}
+
+// setSharedValue sets a value shared from another config, unless it fails this config's validation for the variable
+func (d *DraftConfig) setSharedValue(variable *BuilderVar, value string) {
+	if err := d.validateVariableValue(variable, value); err != nil {
+		log.Warnf("Not sharing value of variable %s with template %s: %s", variable.Name, d.TemplateName, err)
+		return
+	}
+
+	variable.Value = value
+	d.record(variable)
+}
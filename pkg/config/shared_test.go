@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedVariableStore(t *testing.T) {
+	dockerfile := &DraftConfig{
+		TemplateName: "dockerfile",
+		Variables:    []*BuilderVar{{Name: "PORT", Kind: "port"}, {Name: "VERSION"}},
+	}
+	deployment := &DraftConfig{
+		TemplateName: "deployment",
+		Variables:    []*BuilderVar{{Name: "PORT", Kind: "port"}, {Name: "APPNAME"}},
+	}
+	workflow := &DraftConfig{
+		TemplateName: "workflow",
+		Variables:    []*BuilderVar{{Name: "APPNAME", Kind: "strictName"}, {Name: "BRANCHNAME"}},
+	}
+	workflow.SetVariableValidator("strictName", func(value string) error {
+		if value != "my-app" {
+			return errors.New("invalid name")
+		}
+		return nil
+	})
+
+	store := NewSharedVariableStore()
+	dockerfile.BindSharedStore(store)
+	deployment.BindSharedStore(store)
+
+	dockerfile.SetVariable("PORT", "8080")
+	assert.Equal(t, "8080", deployment.Variables[0].Value)
+
+	deployment.SetVariable("APPNAME", "My App")
+	value, ok := store.Value("APPNAME")
+	assert.True(t, ok)
+	assert.Equal(t, "My App", value)
+
+	// values already in the store are applied on bind, unless they fail the config's validation
+	workflow.BindSharedStore(store)
+	assert.Equal(t, "", workflow.Variables[0].Value)
+
+	deployment.SetVariable("APPNAME", "my-app")
+	assert.Equal(t, "my-app", workflow.Variables[0].Value)
+
+	workflow.SetVariable("BRANCHNAME", "main")
+	assert.Equal(t, []string{"PORT", "APPNAME"}, []string{deployment.Variables[0].Name, deployment.Variables[1].Name})
+	assert.Len(t, deployment.Variables, 2)
+
+	copied := deployment.DeepCopy()
+	copied.SetVariable("PORT", "9090")
+	assert.Equal(t, "8080", dockerfile.Variables[0].Value)
+	assert.True(t, copied.Equal(copied.DeepCopy()))
+}
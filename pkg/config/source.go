@@ -0,0 +1,207 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VariableSourceKind identifies where a variable's value came from
+type VariableSourceKind string
+
+const (
+	SourceFlag    VariableSourceKind = "flag"
+	SourceEnv     VariableSourceKind = "env"
+	SourceAnswers VariableSourceKind = "answers"
+	SourceDefault VariableSourceKind = "default"
+	// SourceReferenceVar marks a value defaulted from another variable through Default.ReferenceVar
+	SourceReferenceVar VariableSourceKind = "referenceVar"
+)
+
+// sourcePrecedence lists the source kinds that can be passed to Resolve from highest to lowest priority
+var sourcePrecedence = []VariableSourceKind{SourceFlag, SourceEnv, SourceAnswers}
+
+// envVariablePrefix prefixes the environment variables read by EnvSource, e.g. DRAFT_VAR_PORT sets PORT
+const envVariablePrefix = "DRAFT_VAR_"
+
+// VariableSource supplies variable values from one origin
+type VariableSource interface {
+	// Kind returns the kind of the source, which determines its priority
+	Kind() VariableSourceKind
+	// Values returns the variable values supplied by the source, keyed by variable name or alias
+	Values(d *DraftConfig) (map[string]string, error)
+}
+
+type flagSource map[string]string
+
+// FlagSource supplies the values passed with --variable flags
+func FlagSource(values map[string]string) VariableSource {
+	return flagSource(values)
+}
+
+func (s flagSource) Kind() VariableSourceKind {
+	return SourceFlag
+}
+
+func (s flagSource) Values(d *DraftConfig) (map[string]string, error) {
+	return s, nil
+}
+
+type envSource struct {
+	environ func() []string
+}
+
+// EnvSource supplies values from DRAFT_VAR_<NAME> environment variables. Names are matched like --variable flags and
+// environment variables that don't match a declared variable are ignored.
+func EnvSource() VariableSource {
+	return envSource{environ: os.Environ}
+}
+
+func (s envSource) Kind() VariableSourceKind {
+	return SourceEnv
+}
+
+func (s envSource) Values(d *DraftConfig) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, env := range s.environ() {
+		key, value, _ := strings.Cut(env, "=")
+		name, ok := strings.CutPrefix(key, envVariablePrefix)
+		if !ok || value == "" {
+			continue
+		}
+
+		if _, err := d.getFlagVariable(name); err != nil {
+			log.Debugf("ignoring environment variable %s: %s", key, err)
+			continue
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+type answersSource struct {
+	fileSys fs.FS
+	path    string
+}
+
+// AnswersSource supplies the values recorded in an answers file written by WriteAnswers
+func AnswersSource(fileSys fs.FS, path string) VariableSource {
+	return answersSource{fileSys: fileSys, path: path}
+}
+
+func (s answersSource) Kind() VariableSourceKind {
+	return SourceAnswers
+}
+
+func (s answersSource) Values(d *DraftConfig) (map[string]string, error) {
+	answers, err := d.readAnswers(s.fileSys, s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(answers))
+	for _, answer := range answers {
+		values[answer.Name] = answer.Value
+	}
+
+	return values, nil
+}
+
+// Resolve sets the variables of d from sources in a fixed order of precedence regardless of the order sources are
+// passed in: flags win over DRAFT_VAR_ environment variables, which win over an answers file, and variables that are
+// still unset are then defaulted with ApplyDefaultVariables. The winning source of each variable is available through
+// GetVariableSource. Sources of the same kind that give a variable different values are reported as an error and no
+// variables are set.
+func Resolve(d *DraftConfig, sources ...VariableSource) error {
+	type candidate struct {
+		value string
+		kind  VariableSourceKind
+	}
+
+	resolved := make(map[string]candidate)
+	var errs []error
+	for _, source := range sources {
+		kind := source.Kind()
+		if !slices.Contains(sourcePrecedence, kind) {
+			return fmt.Errorf("resolve variables: unsupported variable source %q", kind)
+		}
+
+		values, err := source.Values(d)
+		if err != nil {
+			return fmt.Errorf("resolve variables from %s: %w", kind, err)
+		}
+
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			canonicalName := name
+			if variable, err := d.getFlagVariable(name); err == nil {
+				canonicalName = variable.Name
+			}
+
+			current, ok := resolved[canonicalName]
+			switch {
+			case !ok || slices.Index(sourcePrecedence, kind) < slices.Index(sourcePrecedence, current.kind):
+				resolved[canonicalName] = candidate{value: values[name], kind: kind}
+			case current.kind == kind && current.value != values[name]:
+				errs = append(errs, fmt.Errorf("conflicting values for variable %s from more than one %s source", canonicalName, kind))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("resolve variables: %w", errors.Join(errs...))
+	}
+
+	names := make([]string, 0, len(resolved))
+	for name := range resolved {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		d.SetVariable(name, resolved[name].value)
+		d.setVariableSource(name, resolved[name].kind)
+	}
+
+	return d.ApplyDefaultVariables()
+}
+
+// GetVariableSource returns the kind of source that set the variable's value during Resolve or when defaults were
+// applied, or an empty kind if the variable was set directly
+func (d *DraftConfig) GetVariableSource(name string) (VariableSourceKind, error) {
+	variable, err := d.GetVariable(name)
+	if err != nil {
+		return "", err
+	}
+
+	return d.variableSources[variable.Name], nil
+}
+
+// describe returns how a value from the source is described in error messages
+func (k VariableSourceKind) describe() string {
+	switch k {
+	case SourceDefault:
+		return "default value"
+	case SourceReferenceVar:
+		return "reference variable value"
+	default:
+		return "user input"
+	}
+}
+
+func (d *DraftConfig) setVariableSource(name string, kind VariableSourceKind) {
+	if d.variableSources == nil {
+		d.variableSources = make(map[string]VariableSourceKind)
+	}
+	d.variableSources[name] = kind
+}
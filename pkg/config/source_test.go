@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSourceConfig() *DraftConfig {
+	return &DraftConfig{
+		TemplateName: "deployment",
+		Variables: []*BuilderVar{
+			{Name: "APPNAME"},
+			{Name: "PORT", Default: BuilderVarDefault{Value: "80"}},
+			{Name: "NAMESPACE", Default: BuilderVarDefault{Value: "default"}},
+			{Name: "IMAGENAME"},
+		},
+	}
+}
+
+func testEnvSource(environ ...string) VariableSource {
+	return envSource{environ: func() []string { return environ }}
+}
+
+func TestResolve(t *testing.T) {
+	answersFS := fstest.MapFS{
+		"answers.yaml": {Data: []byte(`templateName: deployment
+variables:
+- name: APPNAME
+  value: answers-app
+- name: PORT
+  value: "8080"
+- name: IMAGENAME
+  value: answers-image
+`)},
+	}
+
+	draftConfig := newSourceConfig()
+	err := Resolve(draftConfig,
+		AnswersSource(answersFS, "answers.yaml"),
+		testEnvSource("DRAFT_VAR_PORT=9090", "DRAFT_VAR_IMAGENAME=env-image", "DRAFT_VAR_UNKNOWN=x", "HOME=/root"),
+		FlagSource(map[string]string{"imagename": "flag-image"}),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"APPNAME":   "answers-app",
+		"PORT":      "9090",
+		"NAMESPACE": "default",
+		"IMAGENAME": "flag-image",
+	}, draftConfig.GetVariableMap())
+
+	wantSources := map[string]VariableSourceKind{
+		"APPNAME":   SourceAnswers,
+		"PORT":      SourceEnv,
+		"NAMESPACE": SourceDefault,
+		"IMAGENAME": SourceFlag,
+	}
+	for name, want := range wantSources {
+		source, err := draftConfig.GetVariableSource(name)
+		assert.Nil(t, err)
+		assert.Equal(t, want, source, name)
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+	draftConfig := newSourceConfig()
+	err := Resolve(draftConfig,
+		FlagSource(map[string]string{"PORT": "80"}),
+		FlagSource(map[string]string{"PORT": "8080"}),
+	)
+	assert.EqualError(t, err, "resolve variables: conflicting values for variable PORT from more than one flag source")
+	assert.Equal(t, "", draftConfig.Variables[1].Value)
+
+	assert.Nil(t, Resolve(newSourceConfig(),
+		FlagSource(map[string]string{"PORT": "80", "APPNAME": "app", "IMAGENAME": "image"}),
+		FlagSource(map[string]string{"PORT": "80"}),
+	))
+}
@@ -0,0 +1,100 @@
+package transformers
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// registry holds the transformers registered with Register. It is safe for concurrent use, so kinds can be registered
+// while templates are generated, though registering kinds once at startup is expected.
+var registry = struct {
+	sync.RWMutex
+	transformers map[string]func(string) (any, error)
+}{
+	transformers: make(map[string]func(string) (any, error)),
+}
+
+// RegisterOption configures how Register adds a transformer
+type RegisterOption func(*registerOptions)
+
+type registerOptions struct {
+	override bool
+}
+
+// Override lets Register replace the transformer of a built-in kind or a kind that is already registered
+func Override() RegisterOption {
+	return func(o *registerOptions) {
+		o.override = true
+	}
+}
+
+// Register adds a transformer for kind, e.g. an organization specific kind referenced by custom templates, which is
+// then returned by GetTransformer and accepted as a known kind when draft configs are validated. A
+// config.VariableTransformer can be passed as the transformer. Registering a built-in kind or a kind that is already
+// registered returns an error unless Override is passed. Register is safe for concurrent use.
+func Register(kind string, transformer func(string) (any, error), opts ...RegisterOption) error {
+	if kind == "" {
+		return fmt.Errorf("register transformer: kind must not be empty")
+	}
+	if transformer == nil {
+		return fmt.Errorf("register transformer for kind %s: transformer must not be nil", kind)
+	}
+
+	options := &registerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if !options.override {
+		if _, ok := builtinTransformers[kind]; ok {
+			return fmt.Errorf("register transformer for kind %s: kind is built in, pass Override to replace its transformer", kind)
+		}
+		if _, ok := registry.transformers[kind]; ok {
+			return fmt.Errorf("register transformer for kind %s: kind is already registered, pass Override to replace its transformer", kind)
+		}
+	}
+
+	registry.transformers[kind] = transformer
+	return nil
+}
+
+// List returns the sorted kinds that have a transformer, built in or registered with Register. It is safe for
+// concurrent use.
+func List() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	kinds := make([]string, 0, len(builtinTransformers)+len(registry.transformers))
+	for kind := range builtinTransformers {
+		kinds = append(kinds, kind)
+	}
+	for kind := range registry.transformers {
+		if _, ok := builtinTransformers[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// Exists returns true if kind has a transformer, built in or registered with Register. It is safe for concurrent use.
+func Exists(kind string) bool {
+	if _, ok := builtinTransformers[kind]; ok {
+		return true
+	}
+
+	_, ok := registeredTransformer(kind)
+	return ok
+}
+
+func registeredTransformer(kind string) (func(string) (any, error), bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	transformer, ok := registry.transformers[kind]
+	return transformer, ok
+}
@@ -0,0 +1,128 @@
+package transformers
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unregister removes kind from the registry so tests don't leak registrations
+func unregister(kind string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.transformers, kind)
+}
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() { unregister("internalServiceTier") })
+
+	tierTransformer := func(input string) (any, error) {
+		return strings.ToUpper(input), nil
+	}
+
+	assert.False(t, Exists("internalServiceTier"))
+	assert.Nil(t, Register("internalServiceTier", tierTransformer))
+	assert.True(t, Exists("internalServiceTier"))
+	res, err := GetTransformer("internalServiceTier")("gold")
+	assert.Nil(t, err)
+	assert.Equal(t, "GOLD", res)
+	assert.True(t, slices.Contains(List(), "internalServiceTier"))
+
+	err = Register("internalServiceTier", DefaultTransformer)
+	assert.EqualError(t, err, "register transformer for kind internalServiceTier: kind is already registered, pass Override to replace its transformer")
+
+	assert.Nil(t, Register("internalServiceTier", DefaultTransformer, Override()))
+	res, err = GetTransformer("internalServiceTier")("gold")
+	assert.Nil(t, err)
+	assert.Equal(t, "gold", res)
+}
+
+func TestRegisterBuiltinKind(t *testing.T) {
+	t.Cleanup(func() { unregister("lowercase") })
+
+	err := Register("lowercase", DefaultTransformer)
+	assert.EqualError(t, err, "register transformer for kind lowercase: kind is built in, pass Override to replace its transformer")
+	res, err := GetTransformer("lowercase")("MyApp")
+	assert.Nil(t, err)
+	assert.Equal(t, "myapp", res)
+
+	assert.Nil(t, Register("lowercase", DefaultTransformer, Override()))
+	res, err = GetTransformer("lowercase")("MyApp")
+	assert.Nil(t, err)
+	assert.Equal(t, "MyApp", res)
+	assert.Equal(t, 1, len(slices.Compact(slices.DeleteFunc(List(), func(kind string) bool { return kind != "lowercase" }))))
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	assert.EqualError(t, Register("", DefaultTransformer), "register transformer: kind must not be empty")
+	assert.EqualError(t, Register("someKind", nil), "register transformer for kind someKind: transformer must not be nil")
+}
+
+func TestList(t *testing.T) {
+	kinds := List()
+	assert.True(t, slices.IsSorted(kinds))
+	assert.Contains(t, kinds, "kubeName")
+	assert.Contains(t, kinds, "acrLoginServer")
+	assert.Len(t, kinds, len(builtinTransformers))
+}
+
+func TestExists(t *testing.T) {
+	assert.True(t, Exists("slug"))
+	assert.False(t, Exists("slugg"))
+	res, err := GetTransformer("slugg")("Some Value")
+	assert.Nil(t, err)
+	assert.Equal(t, "Some Value", res)
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	kinds := []string{"concurrentKindA", "concurrentKindB", "concurrentKindC"}
+	t.Cleanup(func() {
+		for _, kind := range kinds {
+			unregister(kind)
+		}
+	})
+
+	var wg sync.WaitGroup
+	for _, kind := range kinds {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, Register(kind, DefaultTransformer))
+		}()
+		go func() {
+			defer wg.Done()
+			GetTransformer(kind)("value")
+			Exists(kind)
+			List()
+		}()
+	}
+	wg.Wait()
+
+	for _, kind := range kinds {
+		assert.True(t, Exists(kind))
+	}
+
+	// only one of several registrations of the same kind succeeds
+	var failures sync.Map
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Register("concurrentKindD", DefaultTransformer); err != nil {
+				failures.Store(i, err)
+			}
+		}()
+	}
+	wg.Wait()
+	t.Cleanup(func() { unregister("concurrentKindD") })
+
+	count := 0
+	failures.Range(func(any, any) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 7, count)
+}
@@ -1,17 +1,64 @@
 package transformers
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Azure/draft/pkg/config/validators"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/unicode/norm"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
+// builtinTransformers are the transformers of the kinds built into draft
+var builtinTransformers = map[string]func(string) (any, error){
+	"acrLoginServer":       ACRLoginServerTransformer,
+	"acrName":              ACRNameTransformer,
+	"acrNameOrLoginServer": ACRNameTransformer,
+	"base64Decode":         Base64DecodeTransformer,
+	"base64Encode":         Base64EncodeTransformer,
+	"boolean":              BooleanTransformer,
+	"cpuNormalize":         CPUNormalizeTransformer,
+	"duration":             DurationSecondsTransformer,
+	"durationStrict":       DurationSecondsTransformer,
+	"envExpand":            EnvExpandTransformer(os.LookupEnv, true),
+	"envVarMap":            EnvironmentVariableMapTransformer,
+	"guid":                 GUIDTransformer,
+	"imageRefNormalize":    ImageRefNormalizeOptions{}.Transformer(),
+	"kubeName":             KubeNameTransformer,
+	"lowercase":            LowercaseTransformer,
+	"memoryNormalize":      MemoryNormalizeTransformer,
+	"normalize":            NormalizeTransformer,
+	"portNormalize":        PortNormalizeTransformer,
+	"semver":               SemverTransformer,
+	"slug":                 SlugTransformer(DefaultSlugMaxLength),
+	"trimSpace":            TrimSpaceTransformer,
+	"truncateHash":         TruncateHashTransformer(DefaultTruncateHashLimit),
+	"yamlQuote":            YAMLQuoteTransformer,
+}
+
+// GetTransformer returns the transformer of a kind registered with Register, or else of a built-in kind. Kinds without
+// a transformer return the value unchanged, use Exists to tell them apart.
 func GetTransformer(variableKind string) func(string) (any, error) {
-	switch variableKind {
-	case "envVarMap":
-		return EnvironmentVariableMapTransformer
-	default:
-		return DefaultTransformer
+	if transformer, ok := registeredTransformer(variableKind); ok {
+		return transformer
+	}
+
+	if transformer, ok := builtinTransformers[variableKind]; ok {
+		return transformer
 	}
+
+	return DefaultTransformer
 }
 
 func EnvironmentVariableMapTransformer(inputVar string) (any, error) {
@@ -22,6 +69,406 @@ func EnvironmentVariableMapTransformer(inputVar string) (any, error) {
 	return inputVarMap, nil
 }
 
+// EnvExpandTransformer returns a transformer that replaces $NAME and ${NAME} in a value with environment variables
+// looked up with lookup, usually os.LookupEnv, e.g. myacr.azurecr.io/team/app for $REGISTRY/$TEAM/app. $$ stands for
+// a literal $. If strict is true, referencing an unset variable is an error, otherwise it expands to an empty string.
+func EnvExpandTransformer(lookup func(string) (string, bool), strict bool) func(string) (any, error) {
+	return func(inputVar string) (any, error) {
+		var missing []string
+		expanded := os.Expand(inputVar, func(name string) string {
+			if name == "$" {
+				return "$"
+			}
+
+			value, ok := lookup(name)
+			if !ok && !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return value
+		})
+
+		if strict && len(missing) > 0 {
+			return "", fmt.Errorf("environment variables referenced by the value are not set: %s", strings.Join(missing, ", "))
+		}
+		return expanded, nil
+	}
+}
+
+// defaultACRLoginServerSuffix is the login server domain of Azure Container Registries in the public cloud
+const defaultACRLoginServerSuffix = ".azurecr.io"
+
+// ACRNameTransformer returns the lowercase registry name of an Azure Container Registry name or login server in the
+// public or a sovereign cloud, e.g. myacr for myacr.azurecr.io or myacr.azurecr.cn
+func ACRNameTransformer(inputVar string) (any, error) {
+	name, _, err := validators.ParseACRLoginServer(inputVar)
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// ACRLoginServerTransformer returns the login server of an Azure Container Registry, e.g. myacr.azurecr.io for myacr.
+// Login servers in the public or a sovereign cloud, such as myacr.azurecr.cn, are kept with their lowercase host name.
+func ACRLoginServerTransformer(inputVar string) (any, error) {
+	name, suffix, err := validators.ParseACRLoginServer(inputVar)
+	if err != nil {
+		return "", err
+	}
+	if suffix == "" {
+		suffix = defaultACRLoginServerSuffix
+	}
+	return name + suffix, nil
+}
+
+// LowercaseTransformer returns the value with every letter lowercased, including non-ASCII letters, e.g. myacr for MyACR
+func LowercaseTransformer(inputVar string) (any, error) {
+	return strings.ToLower(inputVar), nil
+}
+
+// TrimSpaceTransformer returns the value without leading and trailing whitespace, including Unicode spaces such as
+// the no-break spaces often copied from web pages
+func TrimSpaceTransformer(inputVar string) (any, error) {
+	return strings.TrimSpace(inputVar), nil
+}
+
+// NormalizeTransformer trims surrounding whitespace from the value and lowercases it, e.g. myacr.azurecr.io/app for
+// " MyACR.azurecr.io/App\n" pasted from a portal
+func NormalizeTransformer(inputVar string) (any, error) {
+	return strings.ToLower(strings.TrimSpace(inputVar)), nil
+}
+
+// GUIDTransformer returns a GUID in lowercase without braces, e.g. 0a1b2c3d-... for {0A1B2C3D-...}
+func GUIDTransformer(inputVar string) (any, error) {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(inputVar, "{"), "}")), nil
+}
+
+// ImageRefNormalizeOptions configures the transformer returned by Transformer
+type ImageRefNormalizeOptions struct {
+	// DefaultRegistry is prepended to references without a registry host, e.g. myacr.azurecr.io. References without a
+	// registry are left without one if it is empty.
+	DefaultRegistry string
+	// AppendLatest adds the latest tag to references with neither a tag nor a digest
+	AppendLatest bool
+}
+
+// dockerHubRegistry is the registry docker pulls images without a registry host from. Its official images live under
+// library/, so nginx and library/nginx are the same image.
+const dockerHubRegistry = "docker.io"
+
+// Transformer returns a transformer that puts container image references in one canonical form, so that nginx and
+// library/nginx or MyACR.azurecr.io/App and myacr.azurecr.io/app are written the same way. The registry host and
+// repository path are lowercased, the tag keeps its case and a digest is kept as it is. The result is checked with the
+// imageReference validator.
+func (o ImageRefNormalizeOptions) Transformer() func(string) (any, error) {
+	return func(inputVar string) (any, error) {
+		name, digest, hasDigest := strings.Cut(strings.TrimSpace(inputVar), "@")
+
+		var tag string
+		if tagIndex := strings.LastIndex(name, ":"); tagIndex > strings.LastIndex(name, "/") {
+			name, tag = name[:tagIndex], name[tagIndex+1:]
+		}
+
+		name = strings.ToLower(name)
+		host, path, hasHost := strings.Cut(name, "/")
+		if !hasHost || !isImageRegistryHost(host) {
+			host, path = "", name
+		}
+
+		if host == "" {
+			host = strings.ToLower(strings.TrimSuffix(o.DefaultRegistry, "/"))
+		}
+		switch {
+		case host == "" && strings.Count(path, "/") == 1:
+			path = strings.TrimPrefix(path, "library/")
+		case host == dockerHubRegistry && !strings.Contains(path, "/"):
+			path = "library/" + path
+		}
+
+		reference := path
+		if host != "" {
+			reference = host + "/" + path
+		}
+		if tag == "" && !hasDigest && o.AppendLatest {
+			tag = "latest"
+		}
+		if tag != "" {
+			reference += ":" + tag
+		}
+		if hasDigest {
+			reference += "@" + digest
+		}
+
+		if err := validators.GetValidator("imageReference")(reference); err != nil {
+			return "", fmt.Errorf("can't normalize image reference %q: %w", inputVar, err)
+		}
+		return reference, nil
+	}
+}
+
+// isImageRegistryHost returns true if the first component of a lowercase image name is a registry host rather than
+// part of the repository path
+func isImageRegistryHost(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
+
+// truncateHashLength is the number of hex characters of the hash appended by TruncateWithHash
+const truncateHashLength = 6
+
+// DefaultTruncateHashLimit is the length the truncateHash kind bounds its values to, the maximum length of most
+// Kubernetes names
+const DefaultTruncateHashLimit = 63
+
+// TruncateWithHash shortens value to at most limit characters. Values within the limit are returned unchanged; longer
+// values are cut and end with "-" and a short hash of the full value, so different long values stay distinct after
+// truncation and the same value always truncates the same way. Separators left at the end of the cut are dropped so
+// the result is still a valid Kubernetes name.
+func TruncateWithHash(value string, limit int) string {
+	if limit <= 0 || len(value) <= limit {
+		return value
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	hash := hex.EncodeToString(sum[:])[:truncateHashLength]
+	if limit <= truncateHashLength+1 {
+		return hash[:limit]
+	}
+
+	prefix := strings.TrimRight(value[:limit-truncateHashLength-1], "-_.")
+	if prefix == "" {
+		return hash
+	}
+	return prefix + "-" + hash
+}
+
+// TruncateHashTransformer returns a transformer that shortens values longer than limit with TruncateWithHash
+func TruncateHashTransformer(limit int) func(string) (any, error) {
+	return func(inputVar string) (any, error) {
+		return TruncateWithHash(inputVar, limit), nil
+	}
+}
+
+// kubeNameMaxLength is the maximum length of an RFC 1123 label, the rule for most Kubernetes names
+const kubeNameMaxLength = 63
+
+// KubeNameTransformer turns free text into an RFC 1123 label usable as a Kubernetes name, e.g. my-cool-app for
+// My Cool App!: letters are lowercased, every other character that isn't a letter or digit becomes '-', repeated
+// dashes are collapsed, and the result is trimmed of dashes and shortened to 63 characters with TruncateWithHash.
+// Input without any ASCII letter or digit is an error.
+func KubeNameTransformer(inputVar string) (any, error) {
+	var name strings.Builder
+	for _, r := range strings.ToLower(inputVar) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			name.WriteRune(r)
+		} else if !strings.HasSuffix(name.String(), "-") {
+			name.WriteRune('-')
+		}
+	}
+
+	kubeName := TruncateWithHash(strings.Trim(name.String(), "-"), kubeNameMaxLength)
+
+	if err := validators.GetValidator("kubernetesResourceName")(kubeName); err != nil {
+		return "", fmt.Errorf("can't make a kubernetes name from %q: %w", inputVar, err)
+	}
+	return kubeName, nil
+}
+
+// DefaultSlugMaxLength is the length the slug kind bounds its values to, the maximum length of most Kubernetes names
+const DefaultSlugMaxLength = 63
+
+// slugTransliterations spells letters that don't decompose into a base letter and accents with ASCII letters
+var slugTransliterations = map[rune]string{
+	'ß': "ss",
+	'æ': "ae",
+	'œ': "oe",
+	'ø': "o",
+	'đ': "d",
+	'ð': "d",
+	'ł': "l",
+	'þ': "th",
+	'ı': "i",
+}
+
+// SlugTransformer returns a transformer that turns a display name into an identifier of at most maxLength characters,
+// e.g. cafe-uber-app for Café Über App: accents are removed from letters, letters are lowercased, every run of
+// characters that aren't ASCII letters or digits becomes a single '-', and the result is trimmed of dashes. Input that
+// leaves nothing after this is an error.
+func SlugTransformer(maxLength int) func(string) (any, error) {
+	return func(inputVar string) (any, error) {
+		var slug strings.Builder
+		for _, r := range norm.NFKD.String(strings.ToLower(inputVar)) {
+			if unicode.Is(unicode.Mn, r) {
+				continue
+			}
+
+			if transliteration, ok := slugTransliterations[r]; ok {
+				slug.WriteString(transliteration)
+			} else if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				slug.WriteRune(r)
+			} else if !strings.HasSuffix(slug.String(), "-") {
+				slug.WriteRune('-')
+			}
+		}
+
+		slugValue := strings.Trim(slug.String(), "-")
+		if len(slugValue) > maxLength {
+			slugValue = strings.TrimRight(slugValue[:maxLength], "-")
+		}
+
+		if slugValue == "" {
+			return "", fmt.Errorf("can't make a slug from %q: it has no letters or digits that can be kept", inputVar)
+		}
+		return slugValue, nil
+	}
+}
+
+// PortNormalizeTransformer turns a port copied from a Dockerfile EXPOSE line or typed loosely into a plain decimal
+// port usable both in a Dockerfile and as a containerPort, e.g. 8080 for " 08080/tcp ". A /tcp or /udp protocol is
+// dropped with a warning, and the result must be a valid containerPort.
+func PortNormalizeTransformer(inputVar string) (any, error) {
+	port := strings.TrimSpace(inputVar)
+	if number, protocol, ok := strings.Cut(port, "/"); ok && (strings.EqualFold(protocol, "tcp") || strings.EqualFold(protocol, "udp")) {
+		log.Warnf("Dropping protocol %s from port %s, only the port number is used", protocol, number)
+		port = strings.TrimSpace(number)
+	}
+
+	if err := validators.GetValidator("containerPort")(port); err != nil {
+		return "", fmt.Errorf("can't normalize port %q: %w", inputVar, err)
+	}
+
+	number, _ := strconv.Atoi(port)
+	return strconv.Itoa(number), nil
+}
+
+// CPUNormalizeTransformer returns the canonical Kubernetes form of a cpu amount, e.g. 500m for 0.5 or 0.5 cores
+func CPUNormalizeTransformer(inputVar string) (any, error) {
+	return validators.NormalizeCPUQuantity(inputVar)
+}
+
+// MemoryNormalizeTransformer returns the canonical Kubernetes form of a memory amount, e.g. 512Mi for 512mb
+func MemoryNormalizeTransformer(inputVar string) (any, error) {
+	return validators.NormalizeMemoryQuantity(inputVar)
+}
+
+// SemverTransformer removes the optional leading v from a semantic version, e.g. 1.2.3 for v1.2.3
+func SemverTransformer(inputVar string) (any, error) {
+	if len(inputVar) > 1 && (inputVar[0] == 'v' || inputVar[0] == 'V') {
+		return inputVar[1:], nil
+	}
+	return inputVar, nil
+}
+
+// Base64EncodeTransformer returns the standard base64 encoding of a value, e.g. for the data of a Kubernetes Secret
+func Base64EncodeTransformer(inputVar string) (any, error) {
+	return base64.StdEncoding.EncodeToString([]byte(inputVar)), nil
+}
+
+// Base64DecodeTransformer decodes a standard base64 value after removing surrounding whitespace. Errors don't include
+// the value, since it is often a secret.
+func Base64DecodeTransformer(inputVar string) (any, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(inputVar))
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 value: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// BooleanTransformer returns true or false for any of the spellings accepted by the boolean kind, e.g. true for YES
+func BooleanTransformer(inputVar string) (any, error) {
+	value, err := validators.ParseBoolean(inputVar)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(value), nil
+}
+
+// DurationSecondsTransformer returns a duration or bare number of seconds as a whole number of seconds, e.g. 5400 for
+// 1h30m
+func DurationSecondsTransformer(inputVar string) (any, error) {
+	duration, err := validators.ParseDuration(inputVar)
+	if err != nil {
+		return "", fmt.Errorf("invalid duration %q: %w", inputVar, err)
+	}
+	return strconv.FormatInt(int64(duration/time.Second), 10), nil
+}
+
+// yamlBlockIndent indents the lines of multi-line values quoted by YAMLQuoteTransformer, deep enough for values of
+// keys indented by up to 10 spaces, such as the pod template annotations of a Deployment
+const yamlBlockIndent = 12
+
+// YAMLQuoteTransformer returns a value that can be spliced into a YAML document after a key and reads back as the same
+// string. Values that already read back as themselves, such as draft or my-app, are returned unchanged. Other
+// single-line values, such as no, 1.0 or foo: bar, are double-quoted with escapes, and multi-line values become a
+// literal block scalar indented by 12 spaces.
+func YAMLQuoteTransformer(inputVar string) (any, error) {
+	if isPlainYAMLString(inputVar) {
+		return inputVar, nil
+	}
+
+	if lines := strings.Split(strings.TrimRight(inputVar, "\n"), "\n"); len(lines) > 1 && canBeYAMLBlock(lines) {
+		chomping := "-"
+		switch trailing := len(inputVar) - len(strings.TrimRight(inputVar, "\n")); {
+		case trailing == 1:
+			chomping = ""
+		case trailing > 1:
+			chomping = "+"
+		}
+
+		indent := strings.Repeat(" ", yamlBlockIndent)
+		var block strings.Builder
+		block.WriteString("|" + chomping)
+		for _, line := range strings.Split(strings.TrimSuffix(inputVar, "\n"), "\n") {
+			block.WriteString("\n")
+			if line != "" {
+				block.WriteString(indent + line)
+			}
+		}
+		return block.String(), nil
+	}
+
+	return strconv.Quote(inputVar), nil
+}
+
+// isPlainYAMLString reports whether value reads back as the same string when written as a plain YAML scalar, under
+// both the YAML 1.1 rules used by Kubernetes, where no and on are booleans, and YAML 1.2
+func isPlainYAMLString(value string) bool {
+	if value == "" || strings.ContainsAny(value, "\n\r\t\"'\\") {
+		return false
+	}
+
+	document := []byte("value: " + value)
+	var yaml11, yaml12 map[string]any
+	if err := yamlv2.Unmarshal(document, &yaml11); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal(document, &yaml12); err != nil {
+		return false
+	}
+
+	return yaml11["value"] == value && yaml12["value"] == value
+}
+
+// canBeYAMLBlock reports whether lines can be written in a literal block scalar with a fixed indentation, which needs
+// the first non-empty line not to start with a space and every character to be printable
+func canBeYAMLBlock(lines []string) bool {
+	for _, line := range lines {
+		if line != "" {
+			if strings.HasPrefix(line, " ") {
+				return false
+			}
+			break
+		}
+	}
+
+	for _, line := range lines {
+		for _, r := range line {
+			if r != '\t' && !strconv.IsPrint(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func DefaultTransformer(inputVar string) (any, error) {
 	return inputVar, nil
 }
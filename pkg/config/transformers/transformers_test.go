@@ -1,9 +1,17 @@
 package transformers
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/Azure/draft/pkg/config/validators"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 func TestGetTransformer(t *testing.T) {
@@ -15,3 +23,525 @@ func TestDefaultTransformer(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "test", res)
 }
+
+func TestACRNameTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"myacr":            "myacr",
+		"myacr.azurecr.io": "myacr",
+		"MyAcr.AzureCR.io": "myacr",
+		"myacr.azurecr.cn": "myacr",
+		"myacr.azurecr.us": "myacr",
+	} {
+		res, err := GetTransformer("acrNameOrLoginServer")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res)
+
+		res, err = GetTransformer("acrName")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res)
+	}
+
+	_, err := GetTransformer("acrName")("myacr.example.io")
+	assert.ErrorContains(t, err, "must end with one of .azurecr.io, .azurecr.cn, .azurecr.us")
+}
+
+func TestACRLoginServerTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"myacr":            "myacr.azurecr.io",
+		"myacr.azurecr.io": "myacr.azurecr.io",
+		"MyAcr.AzureCR.io": "myacr.azurecr.io",
+		"myacr.azurecr.cn": "myacr.azurecr.cn",
+		"MyAcr.AzureCR.US": "myacr.azurecr.us",
+	} {
+		res, err := GetTransformer("acrLoginServer")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res)
+
+		again, err := GetTransformer("acrLoginServer")(want)
+		assert.Nil(t, err)
+		assert.Equal(t, want, again)
+	}
+
+	for _, input := range []string{"acr", "my-acr", "myacr.azurecr.com", "myacr.azurecr.io.example.com"} {
+		_, err := GetTransformer("acrLoginServer")(input)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestGUIDTransformer(t *testing.T) {
+	for _, input := range []string{
+		"0a1b2c3d-4e5f-6789-abcd-ef0123456789",
+		"0A1B2C3D-4E5F-6789-ABCD-EF0123456789",
+		"{0A1B2C3D-4E5F-6789-abcd-EF0123456789}",
+	} {
+		res, err := GetTransformer("guid")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, "0a1b2c3d-4e5f-6789-abcd-ef0123456789", res)
+	}
+}
+
+func TestSemverTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"1.2.3":        "1.2.3",
+		"v1.2.3":       "1.2.3",
+		"V1.2.3-rc.1":  "1.2.3-rc.1",
+		"v1.2.3+build": "1.2.3+build",
+	} {
+		res, err := GetTransformer("semver")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res)
+	}
+}
+
+func TestDurationSecondsTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"90":    "90",
+		"090":   "90",
+		"30s":   "30",
+		"1h30m": "5400",
+		"0s":    "0",
+	} {
+		res, err := GetTransformer("duration")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res)
+	}
+
+	_, err := GetTransformer("durationStrict")("1 min")
+	assert.ErrorContains(t, err, `invalid duration "1 min"`)
+}
+
+func TestBooleanTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"true":  "true",
+		"True":  "true",
+		"YES":   "true",
+		"1":     "true",
+		"false": "false",
+		"No":    "false",
+		"0":     "false",
+	} {
+		res, err := GetTransformer("boolean")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res)
+	}
+
+	_, err := GetTransformer("boolean")("enabled")
+	assert.ErrorContains(t, err, "invalid boolean: enabled")
+}
+
+func TestTruncateWithHash(t *testing.T) {
+	long := strings.Repeat("my-application-", 5) + "staging-svc"
+
+	truncated := TruncateWithHash(long, 63)
+	assert.Len(t, truncated, 63)
+	assert.True(t, strings.HasPrefix(truncated, long[:56]))
+	assert.Regexp(t, "-[0-9a-f]{6}$", truncated)
+	assert.Equal(t, truncated, TruncateWithHash(long, 63), "same input must truncate the same way")
+
+	assert.Equal(t, "my-app", TruncateWithHash("my-app", 63))
+	assert.Equal(t, strings.Repeat("a", 63), TruncateWithHash(strings.Repeat("a", 63), 63))
+	assert.Equal(t, long, TruncateWithHash(long, 0))
+
+	// separators left at the end of the cut are dropped
+	assert.Regexp(t, "^abc-[0-9a-f]{6}$", TruncateWithHash("abc-------defghij", 11))
+	assert.Regexp(t, "^[0-9a-f]{5}$", TruncateWithHash(long, 5))
+
+	// values sharing the kept prefix and differing only after it stay distinct
+	seen := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		value := fmt.Sprintf("%s-%d", long, i)
+		truncated := TruncateWithHash(value, 63)
+		assert.Len(t, truncated, 63)
+		assert.NotContains(t, seen, truncated, "%s and %s truncate the same way", value, seen[truncated])
+		seen[truncated] = value
+	}
+}
+
+func TestTruncateHashTransformer(t *testing.T) {
+	long := strings.Repeat("a", 70)
+
+	res, err := GetTransformer("truncateHash")(long)
+	assert.Nil(t, err)
+	assert.Equal(t, TruncateWithHash(long, 63), res)
+
+	res, err = TruncateHashTransformer(20)(long)
+	assert.Nil(t, err)
+	assert.Regexp(t, "^a{13}-[0-9a-f]{6}$", res)
+
+	res, err = GetTransformer("truncateHash")("my-app")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-app", res)
+}
+
+func TestKubeNameTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"My Cool App!":                 "my-cool-app",
+		"my-app":                       "my-app",
+		"  --Hello__World--  ":         "hello-world",
+		"app.v2":                       "app-v2",
+		"Café Ñandú":                   "caf-and",
+		"123":                          "123",
+		strings.Repeat("a", 70):        TruncateWithHash(strings.Repeat("a", 70), 63),
+		strings.Repeat("a", 62) + " b": TruncateWithHash(strings.Repeat("a", 62)+"-b", 63),
+	} {
+		res, err := GetTransformer("kubeName")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res)
+		assert.Nil(t, validators.GetValidator("kubernetesResourceName")(res.(string)))
+
+		again, err := GetTransformer("kubeName")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, res, again)
+	}
+
+	for _, input := range []string{"", "!!!", "  ", "日本語"} {
+		_, err := GetTransformer("kubeName")(input)
+		assert.ErrorContains(t, err, "can't make a kubernetes name from")
+	}
+}
+
+func TestNormalizationTransformers(t *testing.T) {
+	tests := []struct {
+		input     string
+		lowercase string
+		trimSpace string
+		normalize string
+	}{
+		{"myacr.azurecr.io", "myacr.azurecr.io", "myacr.azurecr.io", "myacr.azurecr.io"},
+		{" MyACR.azurecr.io/App\n", " myacr.azurecr.io/app\n", "MyACR.azurecr.io/App", "myacr.azurecr.io/app"},
+		{"\u00a0ÄBC\t", "\u00a0äbc\t", "ÄBC", "äbc"},
+		{"ÉCOLE Ωmega", "école ωmega", "ÉCOLE Ωmega", "école ωmega"},
+		{"   ", "   ", "", ""},
+		{"", "", "", ""},
+	}
+
+	for _, test := range tests {
+		for kind, want := range map[string]string{
+			"lowercase": test.lowercase,
+			"trimSpace": test.trimSpace,
+			"normalize": test.normalize,
+		} {
+			res, err := GetTransformer(kind)(test.input)
+			assert.Nil(t, err)
+			assert.Equal(t, want, res, "%s of %q", kind, test.input)
+
+			again, err := GetTransformer(kind)(want)
+			assert.Nil(t, err)
+			assert.Equal(t, want, again, "%s of already normalized %q", kind, want)
+		}
+	}
+}
+
+func TestSlugTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"Café Über App":                "cafe-uber-app",
+		"cafe-uber-app":                "cafe-uber-app",
+		"  Crème brûlée -- 2.0!  ":     "creme-brulee-2-0",
+		"Straße Øresund Æsir Łódź":     "strasse-oresund-aesir-lodz",
+		"Ñandú_Señor":                  "nandu-senor",
+		"日本語 App":                      "app",
+		strings.Repeat("a", 70):        strings.Repeat("a", 63),
+		strings.Repeat("a", 62) + " b": strings.Repeat("a", 62),
+	} {
+		res, err := GetTransformer("slug")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res, "input %q", input)
+	}
+
+	res, err := SlugTransformer(10)("Café Über App")
+	assert.Nil(t, err)
+	assert.Equal(t, "cafe-uber", res)
+
+	for _, input := range []string{"", "  ", "!!!", "日本語"} {
+		_, err := GetTransformer("slug")(input)
+		assert.ErrorContains(t, err, "can't make a slug from")
+	}
+}
+
+func TestImageRefNormalizeTransformer(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		input      string
+		bare       string
+		registry   string
+		dockerHub  string
+		withLatest string
+	}{
+		{"nginx", "nginx", "myacr.azurecr.io/nginx", "docker.io/library/nginx", "nginx:latest"},
+		{"library/nginx", "nginx", "myacr.azurecr.io/library/nginx", "docker.io/library/nginx", "nginx:latest"},
+		{"docker.io/library/nginx", "docker.io/library/nginx", "docker.io/library/nginx", "docker.io/library/nginx", "docker.io/library/nginx:latest"},
+		{" MyACR.azurecr.io/Team/App:V1.2-RC \n", "myacr.azurecr.io/team/app:V1.2-RC", "myacr.azurecr.io/team/app:V1.2-RC", "myacr.azurecr.io/team/app:V1.2-RC", "myacr.azurecr.io/team/app:V1.2-RC"},
+		{"localhost:5000/App", "localhost:5000/app", "localhost:5000/app", "localhost:5000/app", "localhost:5000/app:latest"},
+		{"Team/App", "team/app", "myacr.azurecr.io/team/app", "docker.io/team/app", "team/app:latest"},
+		{"nginx@" + digest, "nginx@" + digest, "myacr.azurecr.io/nginx@" + digest, "docker.io/library/nginx@" + digest, "nginx@" + digest},
+		{"App:Stable@" + digest, "app:Stable@" + digest, "myacr.azurecr.io/app:Stable@" + digest, "docker.io/library/app:Stable@" + digest, "app:Stable@" + digest},
+	}
+
+	bare := GetTransformer("imageRefNormalize")
+	registry := ImageRefNormalizeOptions{DefaultRegistry: "MyACR.azurecr.io/"}.Transformer()
+	dockerHub := ImageRefNormalizeOptions{DefaultRegistry: "docker.io"}.Transformer()
+	withLatest := ImageRefNormalizeOptions{AppendLatest: true}.Transformer()
+
+	validate := validators.GetValidator("imageReference")
+	for _, test := range tests {
+		for _, normalized := range []struct {
+			transformer func(string) (any, error)
+			want        string
+		}{{bare, test.bare}, {registry, test.registry}, {dockerHub, test.dockerHub}, {withLatest, test.withLatest}} {
+			res, err := normalized.transformer(test.input)
+			assert.Nil(t, err)
+			assert.Equal(t, normalized.want, res, "input %q", test.input)
+			assert.Nil(t, validate(res.(string)))
+
+			again, err := normalized.transformer(res.(string))
+			assert.Nil(t, err)
+			assert.Equal(t, res, again, "normalizing %q again", res)
+		}
+	}
+
+	for _, input := range []string{"", "my app", "nginx:-bad", "nginx@sha256:ABC", "my_acr.azurecr.io/app"} {
+		_, err := GetTransformer("imageRefNormalize")(input)
+		assert.ErrorContains(t, err, "can't normalize image reference", "input %q", input)
+	}
+}
+
+func TestPortNormalizeTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"8080":        "8080",
+		" 8080 ":      "8080",
+		"08080":       "8080",
+		"000443":      "443",
+		"8080/tcp":    "8080",
+		"53/udp":      "53",
+		" 0080/TCP\n": "80",
+		"+8080":       "8080",
+	} {
+		res, err := GetTransformer("portNormalize")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res, "input %q", input)
+		assert.Nil(t, validators.GetValidator("containerPort")(res.(string)))
+	}
+
+	for input, wantErr := range map[string]string{
+		"":          "must be a whole number",
+		"http":      "must be a whole number",
+		"8080/sctp": "must be a whole number",
+		"8080-8090": "must be a whole number",
+		"0000":      "must be at least 1",
+		"65536/tcp": "must be at most 65535",
+		"-80":       "must not be negative",
+	} {
+		_, err := GetTransformer("portNormalize")(input)
+		assert.ErrorContains(t, err, fmt.Sprintf("can't normalize port %q", input))
+		assert.ErrorContains(t, err, wantErr)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	_, err := GetTransformer("portNormalize")("8080")
+	assert.Nil(t, err)
+	assert.Empty(t, logs.String())
+
+	_, err = GetTransformer("portNormalize")("8080/udp")
+	assert.Nil(t, err)
+	assert.Contains(t, logs.String(), "Dropping protocol udp from port 8080")
+}
+
+func TestQuantityNormalizeTransformers(t *testing.T) {
+	tests := []struct {
+		kind    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{kind: "cpuNormalize", input: "500m", want: "500m"},
+		{kind: "cpuNormalize", input: "2", want: "2"},
+		{kind: "cpuNormalize", input: "1000m", want: "1000m"},
+		{kind: "cpuNormalize", input: "0.5", want: "500m"},
+		{kind: "cpuNormalize", input: ".25", want: "250m"},
+		{kind: "cpuNormalize", input: "1.5", want: "1500m"},
+		{kind: "cpuNormalize", input: "2.0", want: "2"},
+		{kind: "cpuNormalize", input: "0.125", want: "125m"},
+		{kind: "cpuNormalize", input: " 0.5 cores ", want: "500m"},
+		{kind: "cpuNormalize", input: "2 vCPUs", want: "2"},
+		{kind: "cpuNormalize", input: "250 millicores", want: "250m"},
+		{kind: "cpuNormalize", input: "0.0005", wantErr: `invalid cpu quantity "0.0005": must not be more precise than 1m`},
+		{kind: "cpuNormalize", input: "1Gi", wantErr: `invalid cpu quantity "1Gi": binary suffix Gi is not allowed for cpu`},
+		{kind: "cpuNormalize", input: "-0.5", wantErr: "must not be negative"},
+		{kind: "cpuNormalize", input: "half", wantErr: "must start with a number"},
+		{kind: "memoryNormalize", input: "512Mi", want: "512Mi"},
+		{kind: "memoryNormalize", input: "500M", want: "500M"},
+		{kind: "memoryNormalize", input: "64k", want: "64k"},
+		{kind: "memoryNormalize", input: "1.5Gi", want: "1.5Gi"},
+		{kind: "memoryNormalize", input: "512mi", want: "512Mi"},
+		{kind: "memoryNormalize", input: "2GiB", want: "2Gi"},
+		{kind: "memoryNormalize", input: "512mb", want: "512Mi"},
+		{kind: "memoryNormalize", input: "512MB", want: "512Mi"},
+		{kind: "memoryNormalize", input: "512m", want: "512Mi"},
+		{kind: "memoryNormalize", input: "1 gb", want: "1Gi"},
+		{kind: "memoryNormalize", input: "64kb", want: "64Ki"},
+		{kind: "memoryNormalize", input: "64K", want: "64Ki"},
+		{kind: "memoryNormalize", input: "512", wantErr: `invalid memory quantity "512": add a unit such as 512Mi or 512Gi`},
+		{kind: "memoryNormalize", input: "1 core", wantErr: `invalid memory quantity "1 core": unknown suffix " core"`},
+		{kind: "memoryNormalize", input: "-1Gi", wantErr: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.input, func(t *testing.T) {
+			res, err := GetTransformer(tt.kind)(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, res)
+			assert.Nil(t, validators.GetValidator("resourceQuantity")(res.(string)))
+
+			again, err := GetTransformer(tt.kind)(res.(string))
+			assert.Nil(t, err)
+			assert.Equal(t, res, again)
+		})
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	_, err := GetTransformer("memoryNormalize")("512mi")
+	assert.Nil(t, err)
+	assert.Empty(t, logs.String())
+
+	_, err = GetTransformer("memoryNormalize")("512mb")
+	assert.Nil(t, err)
+	assert.Contains(t, logs.String(), `Reading memory quantity \"512mb\" as 512Mi, use 512M if decimal units were meant`)
+}
+
+func TestBase64Transformers(t *testing.T) {
+	for _, input := range []string{"", "password", "p@ss w0rd!\n", "日本語", strings.Repeat("x", 100)} {
+		encoded, err := GetTransformer("base64Encode")(input)
+		assert.Nil(t, err)
+
+		decoded, err := GetTransformer("base64Decode")(encoded.(string))
+		assert.Nil(t, err)
+		assert.Equal(t, input, decoded)
+	}
+
+	encoded, err := GetTransformer("base64Encode")("password")
+	assert.Nil(t, err)
+	assert.Equal(t, "cGFzc3dvcmQ=", encoded)
+
+	decoded, err := GetTransformer("base64Decode")("  cGFzc3dvcmQ=\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "password", decoded)
+
+	for _, input := range []string{"not base64!", "cGFzc3dvcmQ", "cGFz c3dvcmQ="} {
+		_, err := GetTransformer("base64Decode")(input)
+		assert.ErrorContains(t, err, "invalid base64 value: illegal base64 data at input byte")
+		assert.NotContains(t, err.Error(), input)
+	}
+}
+
+func TestYAMLQuoteTransformer(t *testing.T) {
+	for input, want := range map[string]string{
+		"draft":            "draft",
+		"my-app":           "my-app",
+		"hello world":      "hello world",
+		"no":               `"no"`,
+		"On":               `"On"`,
+		"true":             `"true"`,
+		"1.0":              `"1.0"`,
+		"0o17":             `"0o17"`,
+		"~":                `"~"`,
+		"":                 `""`,
+		"foo: bar":         `"foo: bar"`,
+		"foo #bar":         `"foo #bar"`,
+		"*anchor":          `"*anchor"`,
+		"- item":           `"- item"`,
+		"[a, b]":           `"[a, b]"`,
+		" padded ":         `" padded "`,
+		`say "hi"`:         `"say \"hi\""`,
+		`C:\path`:          `"C:\\path"`,
+		"line1\nline2":     "|-\n            line1\n            line2",
+		"line1\n\nline3\n": "|\n            line1\n\n            line3",
+		"a\nb\n\n":         "|+\n            a\n            b\n",
+		"  indented\nnext": `"  indented\nnext"`,
+		"a\r\nb":           `"a\r\nb"`,
+	} {
+		res, err := GetTransformer("yamlQuote")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res, "input %q", input)
+	}
+}
+
+func TestYAMLQuoteTransformerRoundTrips(t *testing.T) {
+	for _, input := range []string{
+		"draft", "no", "yes", "1.0", "null", "foo: bar", "a # b", "@at", "%percent", "`tick", "{x}", "key: 'v'",
+		`say "hi"`, "tab\there", "line1\nline2", "line1\n  indented\nline3\n", "\nleading blank", "a\nb\n\n", "日本語: はい",
+	} {
+		quoted, err := GetTransformer("yamlQuote")(input)
+		assert.Nil(t, err)
+
+		document := []byte("metadata:\n  annotations:\n    key: " + quoted.(string) + "\n")
+
+		var yaml11 struct {
+			Metadata struct {
+				Annotations map[string]string
+			}
+		}
+		assert.Nil(t, yamlv2.Unmarshal(document, &yaml11), "input %q", input)
+		assert.Equal(t, input, yaml11.Metadata.Annotations["key"], "input %q", input)
+
+		var yaml12 map[string]map[string]map[string]any
+		assert.Nil(t, yaml.Unmarshal(document, &yaml12), "input %q", input)
+		assert.Equal(t, input, yaml12["metadata"]["annotations"]["key"], "input %q", input)
+
+		again, err := GetTransformer("yamlQuote")(input)
+		assert.Nil(t, err)
+		assert.Equal(t, quoted, again)
+	}
+}
+
+func TestEnvExpandTransformer(t *testing.T) {
+	environment := map[string]string{"REGISTRY": "myacr.azurecr.io", "TEAM": "payments", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := environment[name]
+		return value, ok
+	}
+
+	strict := EnvExpandTransformer(lookup, true)
+	for input, want := range map[string]string{
+		"$REGISTRY/$TEAM/app":   "myacr.azurecr.io/payments/app",
+		"${REGISTRY}/${TEAM}-x": "myacr.azurecr.io/payments-x",
+		"no variables":          "no variables",
+		"cost: $$5":             "cost: $5",
+		"$$REGISTRY":            "$REGISTRY",
+		"empty:$EMPTY.":         "empty:.",
+	} {
+		res, err := strict(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, res, "input %q", input)
+	}
+
+	_, err := strict("$REGISTRY/$MISSING/$OTHER/$MISSING")
+	assert.EqualError(t, err, "environment variables referenced by the value are not set: MISSING, OTHER")
+
+	lenient := EnvExpandTransformer(lookup, false)
+	res, err := lenient("$REGISTRY/$MISSING/app")
+	assert.Nil(t, err)
+	assert.Equal(t, "myacr.azurecr.io//app", res)
+}
+
+func TestEnvExpandKind(t *testing.T) {
+	t.Setenv("DRAFT_TEST_REGISTRY", "myacr.azurecr.io")
+
+	res, err := GetTransformer("envExpand")("$DRAFT_TEST_REGISTRY/app")
+	assert.Nil(t, err)
+	assert.Equal(t, "myacr.azurecr.io/app", res)
+
+	_, err = GetTransformer("envExpand")("$DRAFT_TEST_UNSET_VARIABLE/app")
+	assert.ErrorContains(t, err, "not set: DRAFT_TEST_UNSET_VARIABLE")
+}
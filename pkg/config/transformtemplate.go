@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"text/template"
+)
+
+// transformTemplateVarsFunc names the function that returns the values of the other variables in a transform template
+const transformTemplateVarsFunc = "vars"
+
+// parsedTransformTemplate is a TransformTemplate parsed from its source
+type parsedTransformTemplate struct {
+	source   string
+	template *template.Template
+}
+
+// parseTransformTemplate parses the variable's TransformTemplate, keeping the result so that the template is parsed
+// only once
+func (bv *BuilderVar) parseTransformTemplate() (*template.Template, error) {
+	if bv.transformTemplate != nil && bv.transformTemplate.source == bv.TransformTemplate {
+		return bv.transformTemplate.template, nil
+	}
+
+	parsed, err := template.New(bv.Name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{transformTemplateVarsFunc: func() map[string]string { return nil }}).
+		Parse(bv.TransformTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("variable %s: invalid transformTemplate %q: %w", bv.Name, bv.TransformTemplate, err)
+	}
+
+	bv.transformTemplate = &parsedTransformTemplate{source: bv.TransformTemplate, template: parsed}
+	return parsed, nil
+}
+
+// transformTemplateTransformer returns a transformer that executes the variable's TransformTemplate with the value as
+// dot. The vars function of the template returns the values of the other variables before their own transformation,
+// so executing a transform template doesn't transform other variables. path holds the names of the variables whose
+// values are being resolved, which vars doesn't resolve again, so a transform template that would be executed again
+// while it is running, through the defaults of the variables it reads, fails instead.
+func (d *DraftConfig) transformTemplateTransformer(variable *BuilderVar, path []string) VariableTransformer {
+	return func(value string) (any, error) {
+		parsed, err := variable.parseTransformTemplate()
+		if err != nil {
+			return "", err
+		}
+
+		tmpl, err := parsed.Clone()
+		if err != nil {
+			return "", fmt.Errorf("variable %s: cloning transformTemplate: %w", variable.Name, err)
+		}
+		tmpl.Funcs(template.FuncMap{transformTemplateVarsFunc: func() map[string]string {
+			return d.transformTemplateVars(variable, path)
+		}})
+
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, value); err != nil {
+			return "", fmt.Errorf("executing transformTemplate: %w", err)
+		}
+		return rendered.String(), nil
+	}
+}
+
+// transformTemplateVars returns the values the other variables are set or default to, leaving out variables whose
+// value can't be resolved, such as those that default to variable itself or to a variable in path
+func (d *DraftConfig) transformTemplateVars(variable *BuilderVar, path []string) map[string]string {
+	if !slices.Contains(path, variable.Name) {
+		path = append(slices.Clone(path), variable.Name)
+	}
+
+	vars := make(map[string]string)
+	for _, other := range d.Variables {
+		if other == variable {
+			continue
+		}
+
+		value, err := d.recurseReferenceVars(other, path)
+		if err == nil && value != "" {
+			vars[other.Name] = value
+		}
+	}
+
+	return vars
+}
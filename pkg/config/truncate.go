@@ -0,0 +1,11 @@
+package config
+
+import "github.com/Azure/draft/pkg/config/transformers"
+
+// TruncateWithHash shortens value to at most limit characters. Values within the limit are returned unchanged; longer
+// values are cut and end with "-" and a short hash of the full value, so different long values stay distinct after
+// truncation and the same value always truncates the same way. It is the truncation maxRenderedLength applies and
+// forwards to transformers.TruncateWithHash.
+func TruncateWithHash(value string, limit int) string {
+	return transformers.TruncateWithHash(value, limit)
+}
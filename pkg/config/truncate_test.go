@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateWithHash(t *testing.T) {
+	long := strings.Repeat("my-application-", 5) + "staging-svc"
+
+	truncated := TruncateWithHash(long, 63)
+	assert.Len(t, truncated, 63)
+	assert.True(t, strings.HasPrefix(truncated, long[:56]))
+	assert.Equal(t, truncated, TruncateWithHash(long, 63), "same input must truncate the same way")
+	assert.NotEqual(t, truncated, TruncateWithHash(long+"2", 63), "different inputs must stay distinct")
+
+	// rendered names must not change between releases
+	assert.Equal(t, "my-application-my-application-my-application-my-applicat-5a0401", truncated)
+
+	assert.Equal(t, "my-app", TruncateWithHash("my-app", 63))
+	assert.Equal(t, strings.Repeat("a", 63), TruncateWithHash(strings.Repeat("a", 63), 63))
+	assert.Equal(t, long, TruncateWithHash(long, 0))
+
+	// separators left at the end of the cut are dropped
+	assert.Regexp(t, "^abc-[0-9a-f]{6}$", TruncateWithHash("abc-------defghij", 11))
+	assert.Regexp(t, "^[0-9a-f]{5}$", TruncateWithHash(long, 5))
+}
+
+func TestMaxRenderedLength(t *testing.T) {
+	long := strings.Repeat("a", 70)
+	draftConfig := &DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "SERVICENAME", Value: long, MaxRenderedLength: 63},
+			{Name: "SHORTNAME", Value: "short", MaxRenderedLength: 63},
+			{Name: "UNLIMITED", Value: long},
+		},
+	}
+
+	value, err := draftConfig.GetVariableValue("SERVICENAME")
+	assert.Nil(t, err)
+	assert.Equal(t, TruncateWithHash(long, 63), value)
+	assert.Len(t, value, 63)
+
+	value, err = draftConfig.GetVariableValue("SHORTNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, "short", value)
+
+	value, err = draftConfig.GetVariableValue("UNLIMITED")
+	assert.Nil(t, err)
+	assert.Equal(t, long, value)
+
+	invalidConfig := DraftConfig{
+		TemplateName: "truncate",
+		Variables:    []*BuilderVar{{Name: "SERVICENAME", MaxRenderedLength: -1}},
+	}
+	assert.EqualError(t, invalidConfig.Validate(), "variable SERVICENAME: maxRenderedLength must not be negative")
+}
@@ -0,0 +1,39 @@
+package config
+
+// TrackVariableUsage starts recording which variables are read through GetVariableValue, GetVariableValueOrDefault,
+// GetVariableValues, GetVariableBool and GetVariableInt, discarding anything recorded before. Template generation
+// calls it before rendering so that UnusedVariables can report the inputs the templates never read.
+func (d *DraftConfig) TrackVariableUsage() {
+	d.usedVariables = make(map[string]bool)
+}
+
+// UnusedVariables returns, in declaration order, the variables that were given a value other than a default but were
+// not read since TrackVariableUsage was called. Unused inputs usually point to a misspelled variable name or a
+// variable the chosen template version doesn't use. It returns nil if usage isn't being tracked.
+func (d *DraftConfig) UnusedVariables() []string {
+	if d.usedVariables == nil {
+		return nil
+	}
+
+	var unused []string
+	for _, variable := range d.Variables {
+		if variable.Value == "" || d.usedVariables[variable.Name] {
+			continue
+		}
+
+		switch d.variableSources[variable.Name] {
+		case SourceDefault, SourceReferenceVar:
+			continue
+		}
+		unused = append(unused, variable.Name)
+	}
+
+	return unused
+}
+
+// markUsed records that the variable was read, if usage is being tracked
+func (d *DraftConfig) markUsed(variable *BuilderVar) {
+	if d.usedVariables != nil {
+		d.usedVariables[variable.Name] = true
+	}
+}
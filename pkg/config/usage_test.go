@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnusedVariables(t *testing.T) {
+	optional := false
+	draftConfig := &DraftConfig{
+		Variables: []*BuilderVar{
+			{Name: "APPNAME", Value: "my-app"},
+			{Name: "PORT", Type: "int", Value: "80"},
+			{Name: "HOSTS", Type: "array", Value: "a.example.com"},
+			{Name: "NAMESPACE", Default: BuilderVarDefault{ReferenceVar: "APPNAME"}},
+			{Name: "IMAGETAG", Default: BuilderVarDefault{Value: "latest"}},
+			{Name: "REPLICAS", Value: "3"},
+			{Name: "EMPTY", Required: &optional},
+		},
+	}
+	draftConfig.SetVariable("IMAGETGA", "v1")
+	assert.Nil(t, draftConfig.ApplyDefaultVariables())
+
+	assert.Nil(t, draftConfig.UnusedVariables(), "usage isn't tracked until TrackVariableUsage is called")
+
+	draftConfig.TrackVariableUsage()
+	_, err := draftConfig.GetVariableValue("APPNAME")
+	assert.Nil(t, err)
+	_, err = draftConfig.GetVariableInt("PORT")
+	assert.Nil(t, err)
+	_, err = draftConfig.GetVariableValues("HOSTS")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"REPLICAS", "IMAGETGA"}, draftConfig.UnusedVariables())
+
+	draftConfig.TrackVariableUsage()
+	assert.Equal(t, []string{"APPNAME", "PORT", "HOSTS", "REPLICAS", "IMAGETGA"}, draftConfig.UnusedVariables())
+}
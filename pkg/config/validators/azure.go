@@ -0,0 +1,129 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	minACRNameLength = 5
+	maxACRNameLength = 50
+
+	maxResourceGroupNameLength = 90
+
+	minStorageAccountNameLength = 3
+	maxStorageAccountNameLength = 24
+)
+
+// guidPattern matches a GUID in the canonical 8-4-4-4-12 hex format, optionally wrapped in braces
+var guidPattern = regexp.MustCompile(`^(\{[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}\}|[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12})$`)
+
+// acrLoginServerSuffixes are the domains of Azure Container Registry login servers in the public and sovereign clouds
+var acrLoginServerSuffixes = []string{".azurecr.io", ".azurecr.cn", ".azurecr.us"}
+
+// acrNameValidator accepts Azure Container Registry names: 5 to 50 lowercase alphanumeric characters
+func acrNameValidator(input string) error {
+	if err := validateACRName(input); err != nil {
+		return fmt.Errorf("invalid Azure Container Registry name %q: %w", input, err)
+	}
+	return nil
+}
+
+// acrNameOrLoginServerValidator accepts either an Azure Container Registry name or its login server, e.g. myacr or
+// myacr.azurecr.io. Login servers are host names, so they are matched case-insensitively.
+func acrNameOrLoginServerValidator(input string) error {
+	_, _, err := ParseACRLoginServer(input)
+	return err
+}
+
+// ParseACRLoginServer splits an Azure Container Registry name or login server into the registry name and the lowercase
+// login server suffix, e.g. myacr and .azurecr.cn for MyAcr.AzureCR.cn. The suffix is empty for a bare name.
+func ParseACRLoginServer(input string) (string, string, error) {
+	name, suffix := input, ""
+	if before, after, ok := strings.Cut(input, "."); ok {
+		if !isACRLoginServerSuffix("." + after) {
+			return "", "", fmt.Errorf("invalid Azure Container Registry login server %q: must end with one of %s", input, strings.Join(acrLoginServerSuffixes, ", "))
+		}
+		name, suffix = strings.ToLower(before), strings.ToLower("."+after)
+	}
+
+	if err := validateACRName(name); err != nil {
+		return "", "", fmt.Errorf("invalid Azure Container Registry name %q: %w", input, err)
+	}
+	return name, suffix, nil
+}
+
+func isACRLoginServerSuffix(suffix string) bool {
+	for _, acrSuffix := range acrLoginServerSuffixes {
+		if strings.EqualFold(suffix, acrSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateACRName(name string) error {
+	if len(name) < minACRNameLength || len(name) > maxACRNameLength {
+		return fmt.Errorf("must be between %d and %d characters, got %d", minACRNameLength, maxACRNameLength, len(name))
+	}
+
+	for _, r := range name {
+		if !isLowerAlphanumeric(r) {
+			return fmt.Errorf("must contain only lowercase letters and numbers, found %q", r)
+		}
+	}
+
+	return nil
+}
+
+// azureResourceGroupValidator accepts Azure resource group names: 1 to 90 characters that are letters or digits,
+// including non-ASCII ones such as é or 日, '_', '(', ')', '-' or '.', not ending with '.'. Azure accepts some other
+// Unicode characters too, but only this subset is enforced.
+func azureResourceGroupValidator(input string) error {
+	if length := utf8.RuneCountInString(input); length < 1 || length > maxResourceGroupNameLength {
+		return fmt.Errorf("invalid Azure resource group name %q: must be between 1 and %d characters, got %d", input, maxResourceGroupNameLength, length)
+	}
+
+	for _, r := range input {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_()-.", r) {
+			return fmt.Errorf("invalid Azure resource group name %q: must contain only letters, digits, '_', '(', ')', '-' or '.', found %q", input, r)
+		}
+	}
+
+	if strings.HasSuffix(input, ".") {
+		return fmt.Errorf("invalid Azure resource group name %q: must not end with '.'", input)
+	}
+
+	return nil
+}
+
+// storageAccountNameValidator accepts Azure storage account names: 3 to 24 lowercase ASCII letters and digits. Names
+// must also be globally unique, which can't be checked here.
+func storageAccountNameValidator(input string) error {
+	if length := utf8.RuneCountInString(input); length < minStorageAccountNameLength || length > maxStorageAccountNameLength {
+		return fmt.Errorf("invalid Azure storage account name %q: must be between %d and %d characters, got %d", input, minStorageAccountNameLength, maxStorageAccountNameLength, length)
+	}
+
+	for _, r := range input {
+		if r >= 'A' && r <= 'Z' {
+			return fmt.Errorf("invalid Azure storage account name %q: must not contain uppercase letters, found %q, use %s", input, r, strings.ToLower(input))
+		}
+		if !isLowerAlphanumeric(r) {
+			return fmt.Errorf("invalid Azure storage account name %q: must contain only lowercase letters and numbers, found %q", input, r)
+		}
+	}
+
+	return nil
+}
+
+// guidValidator accepts GUIDs such as Azure subscription, tenant and client IDs in the canonical 8-4-4-4-12 hex format,
+// with or without braces
+func guidValidator(input string) error {
+	if !guidPattern.MatchString(input) {
+		return fmt.Errorf("invalid GUID %q: must be 32 hex digits grouped 8-4-4-4-12, e.g. 00000000-0000-0000-0000-000000000000", input)
+	}
+	return nil
+}
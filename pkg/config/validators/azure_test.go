@@ -0,0 +1,173 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACRNameValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "myacr"},
+		{input: "myregistry2024"},
+		{input: strings.Repeat("a", 50)},
+		{input: "acr", wantErrMsg: `invalid Azure Container Registry name "acr": must be between 5 and 50 characters, got 3`},
+		{input: strings.Repeat("a", 51), wantErrMsg: "must be between 5 and 50 characters, got 51"},
+		{input: "MyRegistry", wantErrMsg: "must contain only lowercase letters and numbers, found 'M'"},
+		{input: "my-registry", wantErrMsg: "must contain only lowercase letters and numbers, found '-'"},
+		{input: "myacr.azurecr.io", wantErrMsg: "found '.'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("acrName")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestACRNameOrLoginServerValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "myacr"},
+		{input: "myacr.azurecr.io"},
+		{input: "MyAcr.AzureCR.io"},
+		{input: "myacr.azurecr.cn"},
+		{input: "myacr.azurecr.us"},
+		{input: "MyAcr", wantErrMsg: "must contain only lowercase letters and numbers, found 'M'"},
+		{input: "myacr.azurecr.com", wantErrMsg: `invalid Azure Container Registry login server "myacr.azurecr.com": must end with one of .azurecr.io, .azurecr.cn, .azurecr.us`},
+		{input: "myacr.example.io", wantErrMsg: "must end with one of"},
+		{input: "acr.azurecr.io", wantErrMsg: `invalid Azure Container Registry name "acr.azurecr.io": must be between 5 and 50 characters, got 3`},
+		{input: "my-acr.azurecr.io", wantErrMsg: "found '-'"},
+		{input: ".azurecr.io", wantErrMsg: "got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("acrNameOrLoginServer")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestParseACRLoginServer(t *testing.T) {
+	name, suffix, err := ParseACRLoginServer("myacr")
+	assert.Nil(t, err)
+	assert.Equal(t, "myacr", name)
+	assert.Equal(t, "", suffix)
+
+	name, suffix, err = ParseACRLoginServer("MyAcr.AzureCR.cn")
+	assert.Nil(t, err)
+	assert.Equal(t, "myacr", name)
+	assert.Equal(t, ".azurecr.cn", suffix)
+
+	_, _, err = ParseACRLoginServer("myacr.azurecr.io.example.com")
+	assert.ErrorContains(t, err, "must end with one of")
+}
+
+func TestAzureResourceGroupValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "myrg"},
+		{input: "testClusterRG"},
+		{input: "rg_(prod)-west.1"},
+		{input: "a"},
+		{input: "grupo-producción"},
+		{input: "リソース"},
+		{input: strings.Repeat("r", 90)},
+		{input: strings.Repeat("é", 90)},
+		{input: "", wantErrMsg: `invalid Azure resource group name "": must be between 1 and 90 characters, got 0`},
+		{input: strings.Repeat("r", 91), wantErrMsg: "must be between 1 and 90 characters, got 91"},
+		{input: "my-rg.", wantErrMsg: `invalid Azure resource group name "my-rg.": must not end with '.'`},
+		{input: "my rg", wantErrMsg: `must contain only letters, digits, '_', '(', ')', '-' or '.', found ' '`},
+		{input: "my/rg", wantErrMsg: "found '/'"},
+		{input: "rg😀", wantErrMsg: "found '😀'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("azureResourceGroup")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestStorageAccountNameValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "tfstate"},
+		{input: "abc"},
+		{input: "store2024"},
+		{input: strings.Repeat("s", 24)},
+		{input: "ab", wantErrMsg: `invalid Azure storage account name "ab": must be between 3 and 24 characters, got 2`},
+		{input: strings.Repeat("s", 25), wantErrMsg: "must be between 3 and 24 characters, got 25"},
+		{input: "", wantErrMsg: "got 0"},
+		{input: "TfState", wantErrMsg: `invalid Azure storage account name "TfState": must not contain uppercase letters, found 'T', use tfstate`},
+		{input: "tf-state", wantErrMsg: "must contain only lowercase letters and numbers, found '-'"},
+		{input: "tf_state", wantErrMsg: "found '_'"},
+		{input: "store٣", wantErrMsg: "found '٣'"},
+		{input: "store１", wantErrMsg: "found '１'"},
+		{input: "störe", wantErrMsg: "found 'ö'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("storageAccountName")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestGUIDValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "0a1b2c3d-4e5f-6789-abcd-ef0123456789"},
+		{input: "0A1B2C3D-4E5F-6789-ABCD-EF0123456789"},
+		{input: "{0a1b2c3d-4e5f-6789-abcd-ef0123456789}"},
+		{input: "0a1b2c3d-4e5f-6789-abcd-ef012345678g", wantErrMsg: `invalid GUID "0a1b2c3d-4e5f-6789-abcd-ef012345678g": must be 32 hex digits grouped 8-4-4-4-12`},
+		{input: "0a1b2c3d-4e5f-6789-abcd-ef012345678", wantErrMsg: "must be 32 hex digits"},
+		{input: "0a1b2c3d4e5f6789abcdef0123456789", wantErrMsg: "must be 32 hex digits"},
+		{input: "{0a1b2c3d-4e5f-6789-abcd-ef0123456789", wantErrMsg: "must be 32 hex digits"},
+		{input: "0a1b2c3d-4e5f-6789-abcd-ef0123456789}", wantErrMsg: "must be 32 hex digits"},
+		{input: " 0a1b2c3d-4e5f-6789-abcd-ef0123456789", wantErrMsg: "must be 32 hex digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("guid")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
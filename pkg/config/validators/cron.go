@@ -0,0 +1,120 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cronMacros are the predefined schedules accepted by Kubernetes CronJobs
+var cronMacros = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// cronField describes the allowed values of one field of a cron schedule
+type cronField struct {
+	name     string
+	min, max int
+	// names maps case-insensitive names such as JAN or MON to their values
+	names map[string]int
+	// allowQuestionMark accepts ? as a synonym of *, as Kubernetes does for the day fields
+	allowQuestionMark bool
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31, allowQuestionMark: true},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 6, allowQuestionMark: true, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// cronScheduleValidator accepts Kubernetes CronJob schedules: five fields for minute, hour, day of month, month and
+// day of week made of values, ranges, lists and steps, or one of the @hourly style macros
+func cronScheduleValidator(input string) error {
+	if err := validateCronSchedule(input); err != nil {
+		return fmt.Errorf("invalid cron schedule %q: %w", input, err)
+	}
+	return nil
+}
+
+func validateCronSchedule(input string) error {
+	if strings.HasPrefix(input, "@") {
+		if !slices.Contains(cronMacros, strings.ToLower(input)) {
+			return fmt.Errorf("unknown macro %s, must be one of %s", input, strings.Join(cronMacros, ", "))
+		}
+		return nil
+	}
+
+	fields := strings.Fields(input)
+	switch {
+	case len(fields) == 6 || len(fields) == 7:
+		return fmt.Errorf("found %d fields, but Kubernetes uses 5 fields (minute hour day-of-month month day-of-week) without seconds or years, e.g. */5 * * * *", len(fields))
+	case len(fields) != len(cronFields):
+		return fmt.Errorf("found %d fields, must be 5 fields (minute hour day-of-month month day-of-week) or a macro such as @hourly, e.g. */5 * * * *", len(fields))
+	}
+
+	for i, field := range fields {
+		if err := cronFields[i].validate(field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks one field of a schedule: a comma separated list of *, values or ranges, each with an optional /step
+func (f cronField) validate(field string) error {
+	for _, item := range strings.Split(field, ",") {
+		expr, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			stepValue, err := strconv.Atoi(step)
+			if err != nil || stepValue <= 0 {
+				return fmt.Errorf("%s field %q: step %q must be a positive number", f.name, field, step)
+			}
+		}
+
+		if expr == "*" || (expr == "?" && f.allowQuestionMark) {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(expr, "-")
+		startValue, err := f.value(start)
+		if err != nil {
+			return fmt.Errorf("%s field %q: %w", f.name, field, err)
+		}
+		if !isRange {
+			continue
+		}
+
+		endValue, err := f.value(end)
+		if err != nil {
+			return fmt.Errorf("%s field %q: %w", f.name, field, err)
+		}
+		if startValue > endValue {
+			return fmt.Errorf("%s field %q: range %s starts after it ends", f.name, field, expr)
+		}
+	}
+
+	return nil
+}
+
+// value parses a single value or name of the field and checks that it is in range
+func (f cronField) value(s string) (int, error) {
+	if value, ok := f.names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("%d is out of range, must be between %d and %d", value, f.min, f.max)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronScheduleValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "* * * * *"},
+		{input: "*/5 * * * *"},
+		{input: "0 9-17 * * 1-5"},
+		{input: "15,45 */2 1,15 * *"},
+		{input: "0 0 1-31/2 JAN-jun sun,SAT"},
+		{input: "30 6 ? * MON"},
+		{input: "5/10 * * * *"},
+		{input: "0 0 * * 0-6/2"},
+		{input: "@hourly"},
+		{input: "@Daily"},
+		{input: "@every 5m", wantErrMsg: `invalid cron schedule "@every 5m": unknown macro @every 5m`},
+		{input: "every 5 minutes", wantErrMsg: `invalid cron schedule "every 5 minutes": found 3 fields, must be 5 fields`},
+		{input: "0 */5 * * * *", wantErrMsg: "found 6 fields, but Kubernetes uses 5 fields (minute hour day-of-month month day-of-week) without seconds or years"},
+		{input: "0 0 12 * * ? 2025", wantErrMsg: "found 7 fields, but Kubernetes uses 5 fields"},
+		{input: "60 * * * *", wantErrMsg: `minute field "60": 60 is out of range, must be between 0 and 59`},
+		{input: "* 24 * * *", wantErrMsg: `hour field "24": 24 is out of range, must be between 0 and 23`},
+		{input: "* * 0 * *", wantErrMsg: `day of month field "0": 0 is out of range, must be between 1 and 31`},
+		{input: "* * * 13 *", wantErrMsg: "month field \"13\": 13 is out of range"},
+		{input: "* * * * 7", wantErrMsg: `day of week field "7": 7 is out of range, must be between 0 and 6`},
+		{input: "* * * * MON-FUN", wantErrMsg: `day of week field "MON-FUN": "FUN" is not a number`},
+		{input: "* * * * 5-1", wantErrMsg: `day of week field "5-1": range 5-1 starts after it ends`},
+		{input: "*/0 * * * *", wantErrMsg: `minute field "*/0": step "0" must be a positive number`},
+		{input: "1,,2 * * * *", wantErrMsg: `minute field "1,,2": "" is not a number`},
+		{input: "? * * * *", wantErrMsg: `minute field "?": "?" is not a number`},
+		{input: "* * * JAN-13 *", wantErrMsg: "13 is out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("cronSchedule")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package validators
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DurationValidatorOptions configures the validator returned by DurationValidatorOptions.Validator
+type DurationValidatorOptions struct {
+	// RejectZero rejects durations of zero, e.g. 0 or 0s
+	RejectZero bool
+	// RejectNegative rejects durations below zero, e.g. -30s
+	RejectNegative bool
+}
+
+// Validator returns a validator for durations parsed by ParseDuration using the options. Durations must be a whole
+// number of seconds, since that is what Kubernetes fields take.
+func (o DurationValidatorOptions) Validator() func(string) error {
+	return func(input string) error {
+		if err := o.validate(input); err != nil {
+			return fmt.Errorf("invalid duration %q: %w", input, err)
+		}
+		return nil
+	}
+}
+
+func (o DurationValidatorOptions) validate(input string) error {
+	duration, err := ParseDuration(input)
+	if err != nil {
+		return err
+	}
+
+	if duration%time.Second != 0 {
+		return fmt.Errorf("must be a whole number of seconds")
+	}
+	if o.RejectZero && duration == 0 {
+		return fmt.Errorf("must not be zero")
+	}
+	if o.RejectNegative && duration < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+
+	return nil
+}
+
+// ParseDuration parses a Go duration such as 30s or 1h30m, or a bare integer number of seconds such as 90
+func ParseDuration(input string) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(input, 10, 64); err == nil {
+		if seconds > int64(time.Duration(1<<63-1)/time.Second) || seconds < int64(time.Duration(-1<<63)/time.Second) {
+			return 0, fmt.Errorf("%d seconds is out of range", seconds)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	duration, err := time.ParseDuration(input)
+	if err != nil {
+		return 0, fmt.Errorf("must be a number of seconds such as 90 or a duration with units ns, us, ms, s, m or h such as 30s or 1h30m")
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,68 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationValidators(t *testing.T) {
+	tests := []struct {
+		kind       string
+		input      string
+		wantErrMsg string
+	}{
+		{kind: "duration", input: "1h30m"},
+		{kind: "duration", input: "30s"},
+		{kind: "duration", input: "90"},
+		{kind: "duration", input: "0"},
+		{kind: "duration", input: "-30s"},
+		{kind: "duration", input: "1500ms", wantErrMsg: `invalid duration "1500ms": must be a whole number of seconds`},
+		{kind: "duration", input: "1 min", wantErrMsg: `invalid duration "1 min": must be a number of seconds such as 90 or a duration with units ns, us, ms, s, m or h such as 30s or 1h30m`},
+		{kind: "duration", input: "1d", wantErrMsg: "units ns, us, ms, s, m or h"},
+		{kind: "duration", input: "1.5", wantErrMsg: "units ns, us, ms, s, m or h"},
+		{kind: "duration", input: "", wantErrMsg: "must be a number of seconds"},
+		{kind: "duration", input: "99999999999999999", wantErrMsg: "99999999999999999 seconds is out of range"},
+		{kind: "durationStrict", input: "1h30m"},
+		{kind: "durationStrict", input: "90"},
+		{kind: "durationStrict", input: "0", wantErrMsg: `invalid duration "0": must not be zero`},
+		{kind: "durationStrict", input: "0s", wantErrMsg: "must not be zero"},
+		{kind: "durationStrict", input: "-5", wantErrMsg: `invalid duration "-5": must not be negative`},
+		{kind: "durationStrict", input: "-1m", wantErrMsg: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.input, func(t *testing.T) {
+			err := GetValidator(tt.kind)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestDurationValidatorOptions(t *testing.T) {
+	rejectNegative := DurationValidatorOptions{RejectNegative: true}.Validator()
+	assert.Nil(t, rejectNegative("0"))
+	assert.ErrorContains(t, rejectNegative("-1s"), "must not be negative")
+
+	rejectZero := DurationValidatorOptions{RejectZero: true}.Validator()
+	assert.Nil(t, rejectZero("-1s"))
+	assert.ErrorContains(t, rejectZero("0s"), "must not be zero")
+}
+
+func TestParseDuration(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"90":    90 * time.Second,
+		"-5":    -5 * time.Second,
+		"1h30m": 90 * time.Minute,
+		"250ms": 250 * time.Millisecond,
+	} {
+		duration, err := ParseDuration(input)
+		assert.Nil(t, err)
+		assert.Equal(t, want, duration)
+	}
+}
@@ -0,0 +1,42 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultEnvironmentNames are the environments accepted by the environmentName kind unless it is registered with
+// other values
+var defaultEnvironmentNames = []string{"dev", "staging", "prod"}
+
+// NewEnumValidator returns a validator that accepts only the given values, matched case-sensitively. A value that
+// differs from an allowed one only in case is rejected with the allowed spelling as a suggestion. Validators for other
+// sets of values, e.g. an organization's environment names, can be registered with Register:
+//
+//	validators.Register("environmentName", validators.NewEnumValidator("test", "live"), validators.Override())
+func NewEnumValidator(values ...string) func(string) error {
+	return enumValidator("value", values...)
+}
+
+// enumValidator returns a validator that accepts only values, describing rejected input as description in errors,
+// e.g. image pull policy
+func enumValidator(description string, values ...string) func(string) error {
+	allowed := make([]string, len(values))
+	copy(allowed, values)
+
+	return func(input string) error {
+		for _, value := range allowed {
+			if input == value {
+				return nil
+			}
+		}
+
+		for _, value := range allowed {
+			if strings.EqualFold(input, value) {
+				return fmt.Errorf("invalid %s %q: values are case-sensitive, did you mean %s?", description, input, value)
+			}
+		}
+
+		return fmt.Errorf("invalid %s %q: must be one of %s", description, input, strings.Join(allowed, ", "))
+	}
+}
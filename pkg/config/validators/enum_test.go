@@ -0,0 +1,59 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEnumValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "gold"},
+		{input: "silver"},
+		{input: "Gold", wantErrMsg: `invalid value "Gold": values are case-sensitive, did you mean gold?`},
+		{input: "bronze", wantErrMsg: `invalid value "bronze": must be one of gold, silver`},
+		{input: "", wantErrMsg: `invalid value "": must be one of gold, silver`},
+	}
+
+	validator := NewEnumValidator("gold", "silver")
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := validator(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestNewEnumValidatorCopiesValues(t *testing.T) {
+	values := []string{"gold", "silver"}
+	validator := NewEnumValidator(values...)
+	values[0] = "bronze"
+
+	assert.Nil(t, validator("gold"))
+	assert.NotNil(t, validator("bronze"))
+}
+
+func TestEnvironmentNameValidator(t *testing.T) {
+	for _, input := range []string{"dev", "staging", "prod"} {
+		assert.Nil(t, GetValidator("environmentName")(input))
+	}
+
+	assert.EqualError(t, GetValidator("environmentName")("Prod"), `invalid environment name "Prod": values are case-sensitive, did you mean prod?`)
+	assert.EqualError(t, GetValidator("environmentName")("production"), `invalid environment name "production": must be one of dev, staging, prod`)
+	assert.EqualError(t, GetValidator("imagePullPolicy")("always"), `invalid image pull policy "always": values are case-sensitive, did you mean Always?`)
+}
+
+func TestEnvironmentNameOverride(t *testing.T) {
+	t.Cleanup(func() { unregister("environmentName") })
+
+	assert.Nil(t, Register("environmentName", NewEnumValidator("test", "live"), Override()))
+	assert.Nil(t, GetValidator("environmentName")("live"))
+	assert.EqualError(t, GetValidator("environmentName")("prod"), `invalid value "prod": must be one of test, live`)
+}
@@ -0,0 +1,58 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gitRefValidator accepts branch and tag names that follow the rules of git check-ref-format, e.g. main or
+// feature/foo
+func gitRefValidator(input string) error {
+	if err := validateGitRef(input); err != nil {
+		return fmt.Errorf("invalid git ref %q: %w", input, err)
+	}
+	return nil
+}
+
+func validateGitRef(input string) error {
+	switch {
+	case input == "":
+		return fmt.Errorf("must not be empty")
+	case input == "@":
+		return fmt.Errorf("must not be @")
+	case strings.HasPrefix(input, "-"):
+		return fmt.Errorf("must not start with -")
+	case strings.HasPrefix(input, "/") || strings.HasSuffix(input, "/"):
+		return fmt.Errorf("must not start or end with /")
+	case strings.HasSuffix(input, "."):
+		return fmt.Errorf("must not end with .")
+	case strings.Contains(input, "//"):
+		return fmt.Errorf("must not contain consecutive slashes")
+	case strings.Contains(input, ".."):
+		return fmt.Errorf("must not contain ..")
+	case strings.Contains(input, "@{"):
+		return fmt.Errorf("must not contain @{")
+	}
+
+	for _, r := range input {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			return fmt.Errorf("must not contain control characters")
+		case r == ' ':
+			return fmt.Errorf("must not contain spaces")
+		case strings.ContainsRune(`~^:?*[\`, r):
+			return fmt.Errorf(`must not contain %q, the characters ~ ^ : ? * [ \ are not allowed`, r)
+		}
+	}
+
+	for _, component := range strings.Split(input, "/") {
+		if strings.HasPrefix(component, ".") {
+			return fmt.Errorf("path component %q must not start with .", component)
+		}
+		if strings.HasSuffix(component, ".lock") {
+			return fmt.Errorf("path component %q must not end with .lock", component)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitRefValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "main"},
+		{input: "feature/foo"},
+		{input: "users/jane/fix-123"},
+		{input: "release-1.2"},
+		{input: "v1.0.0"},
+		{input: "foo@bar"},
+		{input: "", wantErrMsg: `invalid git ref "": must not be empty`},
+		{input: "@", wantErrMsg: "must not be @"},
+		{input: "-main", wantErrMsg: `invalid git ref "-main": must not start with -`},
+		{input: "/main", wantErrMsg: "must not start or end with /"},
+		{input: "feature/", wantErrMsg: "must not start or end with /"},
+		{input: "feature//foo", wantErrMsg: "must not contain consecutive slashes"},
+		{input: "main.", wantErrMsg: "must not end with ."},
+		{input: "main..dev", wantErrMsg: "must not contain .."},
+		{input: "main@{1}", wantErrMsg: "must not contain @{"},
+		{input: "my branch", wantErrMsg: "must not contain spaces"},
+		{input: "main\tdev", wantErrMsg: "must not contain control characters"},
+		{input: "main~1", wantErrMsg: `must not contain '~', the characters ~ ^ : ? * [ \ are not allowed`},
+		{input: "feat:x", wantErrMsg: `must not contain ':'`},
+		{input: "release/*", wantErrMsg: `must not contain '*'`},
+		{input: `feature\foo`, wantErrMsg: `must not contain '\\'`},
+		{input: "feature/.hidden", wantErrMsg: `path component ".hidden" must not start with .`},
+		{input: "main.lock", wantErrMsg: `path component "main.lock" must not end with .lock`},
+		{input: "feature.lock/foo", wantErrMsg: `path component "feature.lock" must not end with .lock`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("gitRef")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HostnameValidatorOptions configures the validator returned by HostnameValidatorOptions.Validator
+type HostnameValidatorOptions struct {
+	// AllowWildcard accepts a leading *. label, e.g. *.example.com
+	AllowWildcard bool
+	// AllowTrailingDot accepts fully qualified names ending with '.', e.g. example.com.
+	AllowTrailingDot bool
+}
+
+// Validator returns a validator for RFC 1123 host names using the options: dot separated lowercase labels of at most
+// 63 characters and at most 253 characters in total, without a scheme, port or path
+func (o HostnameValidatorOptions) Validator() func(string) error {
+	return func(input string) error {
+		if err := o.validate(input); err != nil {
+			return fmt.Errorf("invalid hostname %q: %w", input, err)
+		}
+		return nil
+	}
+}
+
+func (o HostnameValidatorOptions) validate(input string) error {
+	if scheme, _, ok := strings.Cut(input, "://"); ok {
+		return fmt.Errorf("remove the scheme %s://, only the host name is needed", scheme)
+	}
+	if strings.Contains(input, "/") {
+		return fmt.Errorf("must not contain a path")
+	}
+	if strings.Contains(input, ":") {
+		return fmt.Errorf("must not contain a port")
+	}
+
+	host := input
+	if o.AllowTrailingDot {
+		host = strings.TrimSuffix(host, ".")
+	} else if strings.HasSuffix(host, ".") {
+		return fmt.Errorf("must not end with '.'")
+	}
+
+	if o.AllowWildcard {
+		host = strings.TrimPrefix(host, "*.")
+	}
+	if strings.Contains(host, "*") {
+		if o.AllowWildcard {
+			return fmt.Errorf("'*' is only allowed as the whole first label, e.g. *.example.com")
+		}
+		return fmt.Errorf("must not contain wildcards")
+	}
+
+	if host == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if len(host) > dnsSubdomainMaxLength {
+		return fmt.Errorf("must be no more than %d characters, got %d", dnsSubdomainMaxLength, len(host))
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" {
+			return fmt.Errorf("must not start with '.' or contain '..'")
+		}
+		if err := validateDNSLabel(label, dnsLabelMaxLength); err != nil {
+			return fmt.Errorf("label %q %w", label, err)
+		}
+	}
+
+	return nil
+}
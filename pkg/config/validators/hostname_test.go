@@ -0,0 +1,69 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostnameValidator(t *testing.T) {
+	longLabel := strings.Repeat("a", 63)
+	tests := []struct {
+		input            string
+		wantErrMsg       string
+		wantWildcardErr  string
+		allowTrailingDot bool
+	}{
+		{input: "host"},
+		{input: "myapp.example.com"},
+		{input: "my-app.example.com"},
+		{input: longLabel + ".example.com"},
+		{input: "example.com.", allowTrailingDot: true},
+		{input: "*.example.com", wantErrMsg: `invalid hostname "*.example.com": must not contain wildcards`, wantWildcardErr: "-"},
+		{input: "http://myapp.example.com", wantErrMsg: `invalid hostname "http://myapp.example.com": remove the scheme http://, only the host name is needed`},
+		{input: "https://myapp.example.com/path", wantErrMsg: "remove the scheme https://"},
+		{input: "myapp.example.com/path", wantErrMsg: "must not contain a path"},
+		{input: "myapp.example.com:443", wantErrMsg: "must not contain a port"},
+		{input: "myapp_example", wantErrMsg: `label "myapp_example" must contain only lowercase alphanumeric characters or '-', found '_'`},
+		{input: "MyApp.example.com", wantErrMsg: `label "MyApp" must contain only lowercase`},
+		{input: "example.com.", wantErrMsg: "must not end with '.'"},
+		{input: ".example.com", wantErrMsg: "must not start with '.' or contain '..'"},
+		{input: "my..example.com", wantErrMsg: "must not start with '.' or contain '..'"},
+		{input: "-app.example.com", wantErrMsg: "must start with a lowercase alphanumeric character"},
+		{input: longLabel + "a.example.com", wantErrMsg: "must be no more than 63 characters, got 64"},
+		{input: strings.Repeat(longLabel+".", 4) + "com", wantErrMsg: "must be no more than 253 characters, got 259"},
+		{input: "app.*.example.com", wantErrMsg: "must not contain wildcards", wantWildcardErr: "'*' is only allowed as the whole first label, e.g. *.example.com"},
+		{input: "*", wantErrMsg: "must not contain wildcards", wantWildcardErr: "'*' is only allowed as the whole first label"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := HostnameValidatorOptions{AllowTrailingDot: tt.allowTrailingDot}.Validator()(tt.input)
+			wildcardErr := HostnameValidatorOptions{AllowWildcard: true, AllowTrailingDot: tt.allowTrailingDot}.Validator()(tt.input)
+
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+			}
+
+			switch tt.wantWildcardErr {
+			case "":
+				if tt.wantErrMsg == "" {
+					assert.Nil(t, wildcardErr)
+				} else {
+					assert.ErrorContains(t, wildcardErr, tt.wantErrMsg)
+				}
+			case "-":
+				assert.Nil(t, wildcardErr)
+			default:
+				assert.ErrorContains(t, wildcardErr, tt.wantWildcardErr)
+			}
+		})
+	}
+
+	assert.Nil(t, GetValidator("hostname")("myapp.example.com"))
+	assert.NotNil(t, GetValidator("hostname")("*.example.com"))
+	assert.Nil(t, GetValidator("wildcardHostname")("*.example.com"))
+}
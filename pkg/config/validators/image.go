@@ -0,0 +1,114 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxImageNameLength is the maximum length of an image name, including its registry host, per the OCI distribution spec
+const maxImageNameLength = 255
+
+var (
+	// imageHostPattern matches a registry host name with an optional port, e.g. myacr.azurecr.io or localhost:5000
+	imageHostPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*(:[0-9]+)?$`)
+	// imagePathComponentPattern matches one slash separated component of a repository path
+	imagePathComponentPattern = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*$`)
+	imageTagPattern           = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+	imageDigestPattern        = regexp.MustCompile(`^[a-z0-9]+([+._-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+	sha256DigestPattern       = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// latestImageTag is the tag used when an image reference has none
+const latestImageTag = "latest"
+
+// imageTagValidator accepts docker image tags: at most 128 characters of [A-Za-z0-9_.-], not starting with '.' or '-'
+func imageTagValidator(input string) error {
+	if !imageTagPattern.MatchString(input) {
+		return fmt.Errorf("invalid image tag %q: must be at most 128 alphanumeric characters, '_', '.' or '-', not starting with '.' or '-'", input)
+	}
+	return nil
+}
+
+// imageTagStrictValidator accepts the same tags as imageTagValidator except latest, for templates that require
+// deployments to be pinned to a specific image
+func imageTagStrictValidator(input string) error {
+	if err := imageTagValidator(input); err != nil {
+		return err
+	}
+	if input == latestImageTag {
+		return fmt.Errorf("invalid image tag %q: the latest tag is not allowed, use a specific version", input)
+	}
+	return nil
+}
+
+// imageReferenceValidator accepts container image references per the OCI distribution grammar: an optional registry
+// host, a lowercase repository path, an optional tag and an optional digest, e.g. nginx,
+// myacr.azurecr.io/team/app:1.2.3 or nginx@sha256:<digest>
+func imageReferenceValidator(input string) error {
+	if err := validateImageReference(input); err != nil {
+		return fmt.Errorf("invalid image reference %q: %w", input, err)
+	}
+	return nil
+}
+
+func validateImageReference(input string) error {
+	if input == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if strings.Contains(input, "::") {
+		return fmt.Errorf("must not contain '::'")
+	}
+
+	name := input
+	if before, digest, ok := strings.Cut(input, "@"); ok {
+		if !imageDigestPattern.MatchString(digest) {
+			return fmt.Errorf("digest %q must be an algorithm and encoded hash separated by ':'", digest)
+		}
+		if strings.HasPrefix(digest, "sha256:") && !sha256DigestPattern.MatchString(digest) {
+			return fmt.Errorf("sha256 digest %q must have 64 lowercase hex characters", digest)
+		}
+		name = before
+	}
+
+	if tagIndex := strings.LastIndex(name, ":"); tagIndex > strings.LastIndex(name, "/") {
+		tag := name[tagIndex+1:]
+		name = name[:tagIndex]
+		if !imageTagPattern.MatchString(tag) {
+			return fmt.Errorf("tag %q must be at most 128 alphanumeric characters, '_', '.' or '-', not starting with '.' or '-'", tag)
+		}
+	}
+
+	if name == "" {
+		return fmt.Errorf("repository must not be empty")
+	}
+	if len(name) > maxImageNameLength {
+		return fmt.Errorf("name must be no more than %d characters, got %d", maxImageNameLength, len(name))
+	}
+
+	components := strings.Split(name, "/")
+	if len(components) > 1 && isImageHost(components[0]) {
+		if !imageHostPattern.MatchString(components[0]) {
+			return fmt.Errorf("registry host %q must be a host name with an optional port", components[0])
+		}
+		components = components[1:]
+	}
+
+	for _, component := range components {
+		if imagePathComponentPattern.MatchString(component) {
+			continue
+		}
+		if imagePathComponentPattern.MatchString(strings.ToLower(component)) {
+			return fmt.Errorf("repository component %q must be lowercase", component)
+		}
+		return fmt.Errorf("repository component %q must be lowercase alphanumeric characters separated by '.', '_', '__' or '-'", component)
+	}
+
+	return nil
+}
+
+// isImageHost returns true if the first component of an image name is a registry host rather than part of the
+// repository path, following the same rules as docker
+func isImageHost(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost" || strings.ToLower(component) != component
+}
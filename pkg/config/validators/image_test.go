@@ -0,0 +1,93 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageReferenceValidator(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("f", 64)
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "nginx"},
+		{input: "nginx:1.25"},
+		{input: "library/nginx:latest"},
+		{input: "myacr.azurecr.io/team/app:1.2.3"},
+		{input: "localhost/app"},
+		{input: "localhost:5000/app:dev"},
+		{input: "registry.example.com:443/team/sub-team/my_app:v1.0.0-rc.1"},
+		{input: "team/my.app__x/a-b--c"},
+		{input: "nginx@" + digest},
+		{input: "myacr.azurecr.io/app:1.2.3@" + digest},
+		{input: "nginx@sha512:abcdef"},
+		{input: "", wantErrMsg: `invalid image reference "": must not be empty`},
+		{input: "MyRegistry/app:Latest!", wantErrMsg: `tag "Latest!" must be at most 128 alphanumeric characters`},
+		{input: "MyRegistry.io/app:latest"},
+		{input: "myregistry.io/Team/app", wantErrMsg: `repository component "Team" must be lowercase`},
+		{input: "App", wantErrMsg: `repository component "App" must be lowercase`},
+		{input: "app::latest", wantErrMsg: `invalid image reference "app::latest": must not contain '::'`},
+		{input: "app:", wantErrMsg: `tag "" must be at most 128`},
+		{input: "app:.hidden", wantErrMsg: `tag ".hidden"`},
+		{input: "app:" + strings.Repeat("t", 129), wantErrMsg: "must be at most 128"},
+		{input: ":latest", wantErrMsg: "repository must not be empty"},
+		{input: "team//app", wantErrMsg: `repository component "" must be lowercase alphanumeric characters`},
+		{input: "team/-app", wantErrMsg: `repository component "-app" must be lowercase alphanumeric characters`},
+		{input: "my_registry.io:port/app", wantErrMsg: `registry host "my_registry.io:port" must be a host name`},
+		{input: "nginx@sha256:abc", wantErrMsg: "sha256 digest \"sha256:abc\" must have 64 lowercase hex characters"},
+		{input: "nginx@" + strings.Repeat("f", 64), wantErrMsg: "must be an algorithm and encoded hash separated by ':'"},
+		{input: strings.Repeat("a", 256), wantErrMsg: "name must be no more than 255 characters, got 256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("imageReference")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestImageTagValidator(t *testing.T) {
+	tests := []struct {
+		input         string
+		wantErrMsg    string
+		wantStrictErr string
+	}{
+		{input: "latest", wantStrictErr: `invalid image tag "latest": the latest tag is not allowed, use a specific version`},
+		{input: "v1.0.0"},
+		{input: "1.2.3-rc.1_build"},
+		{input: "_internal"},
+		{input: strings.Repeat("t", 128)},
+		{input: "v1.0 beta", wantErrMsg: `invalid image tag "v1.0 beta": must be at most 128 alphanumeric characters, '_', '.' or '-', not starting with '.' or '-'`},
+		{input: strings.Repeat("t", 129), wantErrMsg: "must be at most 128"},
+		{input: ".hidden", wantErrMsg: "not starting with '.' or '-'"},
+		{input: "-rc", wantErrMsg: "not starting with '.' or '-'"},
+		{input: "v1:2", wantErrMsg: "must be at most 128"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("imageTag")(tt.input)
+			strictErr := GetValidator("imageTagStrict")(tt.input)
+			if tt.wantErrMsg != "" {
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+				assert.ErrorContains(t, strictErr, tt.wantErrMsg)
+				return
+			}
+
+			assert.Nil(t, err)
+			if tt.wantStrictErr != "" {
+				assert.EqualError(t, strictErr, tt.wantStrictErr)
+			} else {
+				assert.Nil(t, strictErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package validators
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	minReplicaCount = 0
+	maxReplicaCount = 1000
+)
+
+// integerValidator accepts whole numbers such as 3 or -1
+func integerValidator(input string) error {
+	if _, err := parseInteger(input); err != nil {
+		return fmt.Errorf("invalid integer %q: %w", input, err)
+	}
+	return nil
+}
+
+// IntegerRangeValidator returns a validator for whole numbers between min and max inclusive
+func IntegerRangeValidator(min, max int) func(string) error {
+	return func(input string) error {
+		value, err := parseInteger(input)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q: %w, must be between %d and %d", input, err, min, max)
+		}
+		if value < min || value > max {
+			return fmt.Errorf("invalid integer %q: out of range, must be between %d and %d", input, min, max)
+		}
+		return nil
+	}
+}
+
+func parseInteger(input string) (int, error) {
+	value, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, fmt.Errorf("not a whole number")
+	}
+	return value, nil
+}
@@ -0,0 +1,52 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegerValidator(t *testing.T) {
+	for _, input := range []string{"0", "3", "-1", "+7", "1000000"} {
+		assert.Nil(t, GetValidator("integer")(input))
+	}
+
+	for _, input := range []string{"three", "2.5", "", "1e3", "0x10"} {
+		assert.ErrorContains(t, GetValidator("integer")(input), "not a whole number")
+	}
+}
+
+func TestIntegerRangeValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "1"},
+		{input: "5"},
+		{input: "10"},
+		{input: "0", wantErrMsg: `invalid integer "0": out of range, must be between 1 and 10`},
+		{input: "11", wantErrMsg: `invalid integer "11": out of range, must be between 1 and 10`},
+		{input: "-3", wantErrMsg: "out of range"},
+		{input: "2.5", wantErrMsg: `invalid integer "2.5": not a whole number, must be between 1 and 10`},
+		{input: "three", wantErrMsg: "not a whole number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := IntegerRangeValidator(1, 10)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestReplicaCountValidator(t *testing.T) {
+	assert.Nil(t, GetValidator("replicaCount")("0"))
+	assert.Nil(t, GetValidator("replicaCount")("1000"))
+	assert.ErrorContains(t, GetValidator("replicaCount")("-1"), "out of range, must be between 0 and 1000")
+	assert.ErrorContains(t, GetValidator("replicaCount")("1001"), "out of range, must be between 0 and 1000")
+	assert.ErrorContains(t, GetValidator("replicaCount")("three"), "not a whole number")
+}
@@ -0,0 +1,188 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// dnsLabelMaxLength is the maximum length of an RFC 1123 label, used for most Kubernetes names
+	dnsLabelMaxLength = 63
+	// dnsSubdomainMaxLength is the maximum length of an RFC 1123 subdomain, used for names that may contain dots
+	dnsSubdomainMaxLength = 253
+)
+
+// kubernetesResourceNameValidator accepts names that are valid RFC 1123 labels, the rules for names of Services and
+// of most resources created from a single app name
+func kubernetesResourceNameValidator(input string) error {
+	if err := validateDNSLabel(input, dnsLabelMaxLength); err != nil {
+		return fmt.Errorf("invalid kubernetes resource name %q: %w", input, err)
+	}
+	return nil
+}
+
+// kubeNameValidator accepts free text that the kubeName transformer can turn into a Kubernetes name, which is any text
+// containing an ASCII letter or digit, e.g. My Cool App!
+func kubeNameValidator(input string) error {
+	for _, r := range input {
+		if isAlphanumeric(r) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid name %q: must contain at least one ASCII letter or digit to make a kubernetes name from", input)
+}
+
+// kubernetesSubdomainNameValidator accepts names that are valid RFC 1123 subdomains, the rules for names of resources
+// such as Secrets, ConfigMaps and ServiceAccounts, which may contain dots and be up to 253 characters long
+func kubernetesSubdomainNameValidator(input string) error {
+	if err := validateDNSSubdomain(input); err != nil {
+		return fmt.Errorf("invalid kubernetes resource name %q: %w", input, err)
+	}
+	return nil
+}
+
+// helmReleaseNameMaxLength is the maximum length of a Helm release name, shorter than other Kubernetes names because
+// Helm appends suffixes to the names of resources it creates from the release
+const helmReleaseNameMaxLength = 53
+
+// helmReleaseNameValidator accepts Helm release names: RFC 1123 labels of at most 53 characters
+func helmReleaseNameValidator(input string) error {
+	if len(input) > helmReleaseNameMaxLength {
+		return fmt.Errorf("invalid helm release name %q: Helm limits release names to %d characters, got %d, which is less than the %d characters allowed for other Kubernetes names", input, helmReleaseNameMaxLength, len(input), dnsLabelMaxLength)
+	}
+	if err := validateDNSLabel(input, helmReleaseNameMaxLength); err != nil {
+		return fmt.Errorf("invalid helm release name %q: %w", input, err)
+	}
+	return nil
+}
+
+// reservedNamespacePrefix starts the names of namespaces reserved for Kubernetes system components
+const reservedNamespacePrefix = "kube-"
+
+// KubernetesNamespaceValidator returns a validator for namespace names, which must be RFC 1123 labels. If
+// rejectReserved is true, names starting with the reserved kube- prefix are rejected too.
+func KubernetesNamespaceValidator(rejectReserved bool) func(string) error {
+	return func(input string) error {
+		if err := validateDNSLabel(input, dnsLabelMaxLength); err != nil {
+			return fmt.Errorf("invalid kubernetes namespace %q: %w", input, err)
+		}
+
+		if rejectReserved && strings.HasPrefix(input, reservedNamespacePrefix) {
+			return fmt.Errorf("invalid kubernetes namespace %q: the %s prefix is reserved for Kubernetes system namespaces", input, reservedNamespacePrefix)
+		}
+
+		return nil
+	}
+}
+
+// kubernetesLabelKeyValidator accepts label and annotation keys: a qualified name made of an optional DNS subdomain
+// prefix and '/' followed by a name of at most 63 characters, e.g. app.kubernetes.io/name
+func kubernetesLabelKeyValidator(input string) error {
+	name := input
+	if prefix, suffix, ok := strings.Cut(input, "/"); ok {
+		if err := validateDNSSubdomain(prefix); err != nil {
+			return fmt.Errorf("invalid kubernetes label key %q: prefix %w", input, err)
+		}
+		name = suffix
+	}
+
+	if name == "" {
+		return fmt.Errorf("invalid kubernetes label key %q: name must not be empty", input)
+	}
+	if err := validateQualifiedNamePart(name); err != nil {
+		return fmt.Errorf("invalid kubernetes label key %q: name %w", input, err)
+	}
+
+	return nil
+}
+
+// kubernetesLabelValueValidator accepts label values: empty, or at most 63 alphanumeric characters, '-', '_' or '.',
+// starting and ending with an alphanumeric character
+func kubernetesLabelValueValidator(input string) error {
+	if input == "" {
+		return nil
+	}
+
+	if err := validateQualifiedNamePart(input); err != nil {
+		return fmt.Errorf("invalid kubernetes label value %q: %w", input, err)
+	}
+	return nil
+}
+
+// validateQualifiedNamePart checks the name part of a qualified name, which is also the format of label values
+func validateQualifiedNamePart(input string) error {
+	if len(input) > dnsLabelMaxLength {
+		return fmt.Errorf("must be no more than %d characters, got %d", dnsLabelMaxLength, len(input))
+	}
+
+	for _, r := range input {
+		if !isAlphanumeric(r) && r != '-' && r != '_' && r != '.' {
+			return fmt.Errorf("must contain only alphanumeric characters, '-', '_' or '.', found %q", r)
+		}
+	}
+
+	if !isAlphanumeric(rune(input[0])) {
+		return fmt.Errorf("must start with an alphanumeric character")
+	}
+	if !isAlphanumeric(rune(input[len(input)-1])) {
+		return fmt.Errorf("must end with an alphanumeric character")
+	}
+
+	return nil
+}
+
+// validateDNSSubdomain checks input against the RFC 1123 subdomain rules: at most 253 characters made of dot
+// separated labels
+func validateDNSSubdomain(input string) error {
+	if input == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if len(input) > dnsSubdomainMaxLength {
+		return fmt.Errorf("must be no more than %d characters, got %d", dnsSubdomainMaxLength, len(input))
+	}
+
+	for _, label := range strings.Split(input, ".") {
+		if label == "" {
+			return fmt.Errorf("must not start or end with '.' or contain '..'")
+		}
+		if err := validateDNSLabel(label, dnsSubdomainMaxLength); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateDNSLabel checks input against the RFC 1123 label rules with the given length limit: lowercase alphanumeric
+// characters or '-', starting and ending with an alphanumeric character
+func validateDNSLabel(input string, maxLength int) error {
+	if input == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if len(input) > maxLength {
+		return fmt.Errorf("must be no more than %d characters, got %d", maxLength, len(input))
+	}
+
+	for _, r := range input {
+		if !isLowerAlphanumeric(r) && r != '-' {
+			return fmt.Errorf("must contain only lowercase alphanumeric characters or '-', found %q", r)
+		}
+	}
+
+	if !isLowerAlphanumeric(rune(input[0])) {
+		return fmt.Errorf("must start with a lowercase alphanumeric character")
+	}
+	if !isLowerAlphanumeric(rune(input[len(input)-1])) {
+		return fmt.Errorf("must end with a lowercase alphanumeric character")
+	}
+
+	return nil
+}
+
+func isLowerAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
+
+func isAlphanumeric(r rune) bool {
+	return isLowerAlphanumeric(r) || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,196 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesResourceNameValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "my-app"},
+		{input: "app1"},
+		{input: "1app"},
+		{input: strings.Repeat("a", 63)},
+		{input: "", wantErrMsg: `invalid kubernetes resource name "": must not be empty`},
+		{input: strings.Repeat("a", 64), wantErrMsg: "must be no more than 63 characters, got 64"},
+		{input: "My_App!", wantErrMsg: "must contain only lowercase alphanumeric characters or '-', found 'M'"},
+		{input: "my_app", wantErrMsg: "must contain only lowercase alphanumeric characters or '-', found '_'"},
+		{input: "my.app", wantErrMsg: "must contain only lowercase alphanumeric characters or '-', found '.'"},
+		{input: "-my-app", wantErrMsg: "must start with a lowercase alphanumeric character"},
+		{input: "my-app-", wantErrMsg: "must end with a lowercase alphanumeric character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("kubernetesResourceName")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestKubeNameValidator(t *testing.T) {
+	for _, input := range []string{"My Cool App!", "my-app", "a", "--7--"} {
+		assert.Nil(t, GetValidator("kubeName")(input))
+	}
+	for _, input := range []string{"", "!!!", "日本語"} {
+		assert.ErrorContains(t, GetValidator("kubeName")(input), "must contain at least one ASCII letter or digit")
+	}
+}
+
+func TestKubernetesSubdomainNameValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "my-app"},
+		{input: "my-app.secrets"},
+		{input: strings.Repeat("a", 64)},
+		{input: strings.Repeat("a.", 126) + "a"},
+		{input: "", wantErrMsg: "must not be empty"},
+		{input: strings.Repeat("a", 254), wantErrMsg: "must be no more than 253 characters, got 254"},
+		{input: "my..app", wantErrMsg: "must not start or end with '.' or contain '..'"},
+		{input: ".my-app", wantErrMsg: "must not start or end with '.' or contain '..'"},
+		{input: "my-app.-secrets", wantErrMsg: "must start with a lowercase alphanumeric character"},
+		{input: "My-App", wantErrMsg: "must contain only lowercase alphanumeric characters or '-', found 'M'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("kubernetesSubdomainName")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestHelmReleaseNameValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "my-app"},
+		{input: strings.Repeat("a", 53)},
+		{input: strings.Repeat("a", 54), wantErrMsg: "Helm limits release names to 53 characters, got 54, which is less than the 63 characters allowed for other Kubernetes names"},
+		{input: strings.Repeat("a", 60), wantErrMsg: "got 60"},
+		{input: "", wantErrMsg: `invalid helm release name "": must not be empty`},
+		{input: "My-App", wantErrMsg: "must contain only lowercase alphanumeric characters or '-', found 'M'"},
+		{input: "my-app-", wantErrMsg: "must end with a lowercase alphanumeric character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("helmReleaseName")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestKubernetesNamespaceValidator(t *testing.T) {
+	tests := []struct {
+		input          string
+		rejectReserved bool
+		wantErrMsg     string
+	}{
+		{input: "default"},
+		{input: "team-platform"},
+		{input: "a"},
+		{input: strings.Repeat("n", 63)},
+		{input: "kube-system"},
+		{input: "kubernetes", rejectReserved: true},
+		{input: strings.Repeat("n", 64), wantErrMsg: `invalid kubernetes namespace "` + strings.Repeat("n", 64) + `": must be no more than 63 characters, got 64`},
+		{input: "team.platform", wantErrMsg: `invalid kubernetes namespace "team.platform": must contain only lowercase alphanumeric characters or '-', found '.'`},
+		{input: "Team", wantErrMsg: `invalid kubernetes namespace "Team": must contain only lowercase alphanumeric characters or '-', found 'T'`},
+		{input: "team-", wantErrMsg: `invalid kubernetes namespace "team-": must end with a lowercase alphanumeric character`},
+		{input: "kube-system", rejectReserved: true, wantErrMsg: `invalid kubernetes namespace "kube-system": the kube- prefix is reserved for Kubernetes system namespaces`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := KubernetesNamespaceValidator(tt.rejectReserved)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+
+	assert.Nil(t, GetValidator("kubernetesNamespace")("kube-public"), "reserved names are allowed by default")
+	assert.NotNil(t, GetValidator("kubernetesNamespace")("team.platform"))
+}
+
+func TestKubernetesLabelKeyValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "app"},
+		{input: "cost-center"},
+		{input: "app.kubernetes.io/name"},
+		{input: "example.com/Team_Name"},
+		{input: strings.Repeat("k", 63)},
+		{input: "", wantErrMsg: `invalid kubernetes label key "": name must not be empty`},
+		{input: "app.kubernetes.io/", wantErrMsg: `invalid kubernetes label key "app.kubernetes.io/": name must not be empty`},
+		{input: "/name", wantErrMsg: `invalid kubernetes label key "/name": prefix must not be empty`},
+		{input: "App.io/name", wantErrMsg: `invalid kubernetes label key "App.io/name": prefix must contain only lowercase alphanumeric characters or '-', found 'A'`},
+		{input: "a/b/c", wantErrMsg: `invalid kubernetes label key "a/b/c": name must contain only alphanumeric characters, '-', '_' or '.', found '/'`},
+		{input: strings.Repeat("k", 64), wantErrMsg: "name must be no more than 63 characters, got 64"},
+		{input: "cost center", wantErrMsg: `name must contain only alphanumeric characters, '-', '_' or '.', found ' '`},
+		{input: "_app", wantErrMsg: "name must start with an alphanumeric character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("kubernetesLabelKey")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestKubernetesLabelValueValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: ""},
+		{input: "draft"},
+		{input: "My_App.v1"},
+		{input: strings.Repeat("v", 63)},
+		{input: strings.Repeat("v", 64), wantErrMsg: "must be no more than 63 characters, got 64"},
+		{input: "my app", wantErrMsg: `invalid kubernetes label value "my app": must contain only alphanumeric characters, '-', '_' or '.', found ' '`},
+		{input: "team/app", wantErrMsg: "found '/'"},
+		{input: "-app", wantErrMsg: "must start with an alphanumeric character"},
+		{input: "app.", wantErrMsg: "must end with an alphanumeric character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("kubernetesLabelValue")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
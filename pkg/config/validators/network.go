@@ -0,0 +1,89 @@
+package validators
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IPFamily restricts the addresses accepted by IPAddressValidator and CIDRValidator
+type IPFamily int
+
+const (
+	// AnyIPFamily accepts IPv4 and IPv6 addresses
+	AnyIPFamily IPFamily = iota
+	// IPv4Only accepts only IPv4 addresses
+	IPv4Only
+	// IPv6Only accepts only IPv6 addresses, including IPv4-mapped IPv6 addresses such as ::ffff:10.0.0.1
+	IPv6Only
+)
+
+// IPAddressValidator returns a validator for IP addresses of the family, e.g. 10.0.0.1 or 2001:db8::1
+func IPAddressValidator(family IPFamily) func(string) error {
+	return func(input string) error {
+		if err := validateIPAddress(input, family); err != nil {
+			return fmt.Errorf("invalid ip address %q: %w", input, err)
+		}
+		return nil
+	}
+}
+
+// CIDRValidator returns a validator for CIDR ranges of the family, e.g. 10.0.0.0/16 or 2001:db8::/32, whose address
+// has no bits set outside the mask
+func CIDRValidator(family IPFamily) func(string) error {
+	return func(input string) error {
+		if err := validateCIDR(input, family); err != nil {
+			return fmt.Errorf("invalid cidr %q: %w", input, err)
+		}
+		return nil
+	}
+}
+
+func validateIPAddress(input string, family IPFamily) error {
+	if strings.Contains(input, "/") {
+		return fmt.Errorf("must be a single address without a prefix length")
+	}
+
+	addr, err := netip.ParseAddr(input)
+	if err != nil {
+		return fmt.Errorf("must be an IPv4 address such as 10.0.0.1 or an IPv6 address such as 2001:db8::1")
+	}
+	if addr.Zone() != "" {
+		return fmt.Errorf("must not have a zone")
+	}
+
+	return checkIPFamily(addr, family)
+}
+
+func validateCIDR(input string, family IPFamily) error {
+	if !strings.Contains(input, "/") {
+		if addr, err := netip.ParseAddr(input); err == nil {
+			return fmt.Errorf("missing prefix length, e.g. %s/%d for a single address", input, addr.BitLen())
+		}
+	}
+
+	prefix, err := netip.ParsePrefix(input)
+	if err != nil {
+		return fmt.Errorf("must be an address and prefix length such as 10.0.0.0/16 or 2001:db8::/32")
+	}
+	if err := checkIPFamily(prefix.Addr(), family); err != nil {
+		return err
+	}
+	if masked := prefix.Masked(); masked != prefix {
+		return fmt.Errorf("address has bits set outside the /%d mask, did you mean %s?", prefix.Bits(), masked)
+	}
+
+	return nil
+}
+
+func checkIPFamily(addr netip.Addr, family IPFamily) error {
+	switch {
+	case family == IPv4Only && addr.Is4In6():
+		return fmt.Errorf("must be an IPv4 address, not the IPv4-mapped IPv6 address of %s", addr.Unmap())
+	case family == IPv4Only && !addr.Is4():
+		return fmt.Errorf("must be an IPv4 address")
+	case family == IPv6Only && !addr.Is6():
+		return fmt.Errorf("must be an IPv6 address")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPAddressValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		family     IPFamily
+		wantErrMsg string
+	}{
+		{input: "10.0.0.1"},
+		{input: "2001:db8::1"},
+		{input: "::1"},
+		{input: "::"},
+		{input: "::ffff:10.0.0.1"},
+		{input: "2001:DB8:0:0:0:0:0:1"},
+		{input: "10.0.0", wantErrMsg: `invalid ip address "10.0.0": must be an IPv4 address such as 10.0.0.1 or an IPv6 address such as 2001:db8::1`},
+		{input: "256.0.0.1", wantErrMsg: "must be an IPv4 address such as 10.0.0.1"},
+		{input: "010.0.0.1", wantErrMsg: "must be an IPv4 address such as 10.0.0.1"},
+		{input: "2001:db8:::1", wantErrMsg: "must be an IPv4 address such as 10.0.0.1"},
+		{input: "10.0.0.0/8", wantErrMsg: "must be a single address without a prefix length"},
+		{input: "fe80::1%eth0", wantErrMsg: "must not have a zone"},
+		{input: "", wantErrMsg: "must be an IPv4 address"},
+		{input: "10.0.0.1", family: IPv4Only},
+		{input: "2001:db8::1", family: IPv4Only, wantErrMsg: `invalid ip address "2001:db8::1": must be an IPv4 address`},
+		{input: "::ffff:10.0.0.1", family: IPv4Only, wantErrMsg: "must be an IPv4 address, not the IPv4-mapped IPv6 address of 10.0.0.1"},
+		{input: "::ffff:10.0.0.1", family: IPv6Only},
+		{input: "10.0.0.1", family: IPv6Only, wantErrMsg: "must be an IPv6 address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := IPAddressValidator(tt.family)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestCIDRValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		family     IPFamily
+		wantErrMsg string
+	}{
+		{input: "10.0.0.0/16"},
+		{input: "0.0.0.0/0"},
+		{input: "10.0.0.1/32"},
+		{input: "2001:db8::/32"},
+		{input: "::/0"},
+		{input: "::ffff:10.0.0.0/104"},
+		{input: "10.0.0/16", wantErrMsg: `invalid cidr "10.0.0/16": must be an address and prefix length such as 10.0.0.0/16 or 2001:db8::/32`},
+		{input: "10.0.0.0/33", wantErrMsg: "must be an address and prefix length"},
+		{input: "10.0.0.0/", wantErrMsg: "must be an address and prefix length"},
+		{input: "10.0.0.1", wantErrMsg: `invalid cidr "10.0.0.1": missing prefix length, e.g. 10.0.0.1/32 for a single address`},
+		{input: "2001:db8::1", wantErrMsg: "missing prefix length, e.g. 2001:db8::1/128 for a single address"},
+		{input: "10.0.1.0/16", wantErrMsg: `invalid cidr "10.0.1.0/16": address has bits set outside the /16 mask, did you mean 10.0.0.0/16?`},
+		{input: "2001:db8::1/32", wantErrMsg: "address has bits set outside the /32 mask, did you mean 2001:db8::/32?"},
+		{input: "10.0.0.0/16", family: IPv4Only},
+		{input: "2001:db8::/32", family: IPv4Only, wantErrMsg: "must be an IPv4 address"},
+		{input: "::ffff:10.0.0.0/104", family: IPv4Only, wantErrMsg: "not the IPv4-mapped IPv6 address of 10.0.0.0"},
+		{input: "2001:db8::/32", family: IPv6Only},
+		{input: "10.0.0.0/16", family: IPv6Only, wantErrMsg: `invalid cidr "10.0.0.0/16": must be an IPv6 address`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := CIDRValidator(tt.family)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestNetworkKinds(t *testing.T) {
+	assert.Nil(t, GetValidator("ipAddress")("2001:db8::1"))
+	assert.ErrorContains(t, GetValidator("ipAddress")("10.0.0"), "invalid ip address")
+	assert.Nil(t, GetValidator("cidr")("10.0.0.0/16"))
+	assert.ErrorContains(t, GetValidator("cidr")("10.0.0.1"), "missing prefix length")
+}
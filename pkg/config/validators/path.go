@@ -0,0 +1,67 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RepoPathValidatorOptions configures the validator returned by RepoPathValidatorOptions.Validator
+type RepoPathValidatorOptions struct {
+	// MustExist requires the path to exist relative to Root
+	MustExist bool
+	// Root is the directory paths are resolved against when MustExist is set, the current directory if empty
+	Root string
+}
+
+// Validator returns a validator for paths relative to the repository root, e.g. ./Dockerfile or charts/production.yaml,
+// which must use forward slashes and stay inside the repository
+func (o RepoPathValidatorOptions) Validator() func(string) error {
+	return func(input string) error {
+		if err := o.validate(input); err != nil {
+			return fmt.Errorf("invalid repository path %q: %w", input, err)
+		}
+		return nil
+	}
+}
+
+func (o RepoPathValidatorOptions) validate(input string) error {
+	if input == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if strings.Contains(input, `\`) {
+		return fmt.Errorf(`use / instead of \ to separate directories, e.g. %s`, path.Clean(strings.ReplaceAll(input, `\`, "/")))
+	}
+
+	cleaned := path.Clean(input)
+	if path.IsAbs(cleaned) || hasDriveLetter(cleaned) {
+		return fmt.Errorf("%s is absolute, use a path relative to the repository root", cleaned)
+	}
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("%s is outside the repository", cleaned)
+	}
+
+	if o.MustExist {
+		root := o.Root
+		if root == "" {
+			root = "."
+		}
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(cleaned))); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("%s does not exist", cleaned)
+			}
+			return fmt.Errorf("checking %s: %w", cleaned, err)
+		}
+	}
+
+	return nil
+}
+
+// hasDriveLetter reports whether p starts with a Windows drive such as C:
+func hasDriveLetter(p string) bool {
+	return len(p) >= 2 && p[1] == ':' && (p[0] >= 'a' && p[0] <= 'z' || p[0] >= 'A' && p[0] <= 'Z')
+}
@@ -0,0 +1,53 @@
+package validators
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoRelativePathValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "."},
+		{input: "./Dockerfile"},
+		{input: "charts/production.yaml"},
+		{input: "./overlays/production/"},
+		{input: "src/../Dockerfile"},
+		{input: "", wantErrMsg: `invalid repository path "": must not be empty`},
+		{input: "/etc/passwd", wantErrMsg: `invalid repository path "/etc/passwd": /etc/passwd is absolute, use a path relative to the repository root`},
+		{input: "C:/src/Dockerfile", wantErrMsg: "C:/src/Dockerfile is absolute"},
+		{input: "../../etc/passwd", wantErrMsg: `invalid repository path "../../etc/passwd": ../../etc/passwd is outside the repository`},
+		{input: "./src/../../other", wantErrMsg: "../other is outside the repository"},
+		{input: "..", wantErrMsg: ".. is outside the repository"},
+		{input: `src\Dockerfile`, wantErrMsg: `use / instead of \ to separate directories, e.g. src/Dockerfile`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("repoRelativePath")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestExistingPathValidator(t *testing.T) {
+	root := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "charts"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "Dockerfile"), []byte("FROM scratch"), 0644))
+
+	validator := RepoPathValidatorOptions{MustExist: true, Root: root}.Validator()
+	assert.Nil(t, validator("./Dockerfile"))
+	assert.Nil(t, validator("charts"))
+	assert.Nil(t, validator("."))
+	assert.ErrorContains(t, validator("./charts/../missing.yaml"), `invalid repository path "./charts/../missing.yaml": missing.yaml does not exist`)
+	assert.ErrorContains(t, validator("../Dockerfile"), "../Dockerfile is outside the repository")
+}
@@ -0,0 +1,59 @@
+package validators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	maxPort = 65535
+	// minUnprivilegedPort is the lowest port a container running as a non-root user can bind by default
+	minUnprivilegedPort = 1024
+)
+
+// PortValidatorOptions configures the validator returned by PortValidatorOptions.Validator
+type PortValidatorOptions struct {
+	// MinPort is the lowest accepted port. Values below 1 are treated as 1 since port 0 can't be exposed.
+	MinPort int
+	// WarnPrivileged logs a warning for accepted ports below 1024, which images running as non-root can't bind
+	WarnPrivileged bool
+}
+
+// NewPortValidator returns a validator for container ports between minPort and 65535, e.g. NewPortValidator(1024) to
+// reject privileged ports
+func NewPortValidator(minPort int) func(string) error {
+	return PortValidatorOptions{MinPort: minPort}.Validator()
+}
+
+// Validator returns a validator for container ports using the options
+func (o PortValidatorOptions) Validator() func(string) error {
+	minPort := max(o.MinPort, 1)
+	return func(input string) error {
+		if strings.TrimSpace(input) != input {
+			return fmt.Errorf("invalid port %q: must not have leading or trailing whitespace", input)
+		}
+
+		port, err := strconv.Atoi(input)
+		if err != nil {
+			return fmt.Errorf("invalid port %q: must be a whole number", input)
+		}
+
+		switch {
+		case port < 0:
+			return fmt.Errorf("invalid port %d: must not be negative", port)
+		case port < minPort:
+			return fmt.Errorf("invalid port %d: must be at least %d", port, minPort)
+		case port > maxPort:
+			return fmt.Errorf("invalid port %d: must be at most %d", port, maxPort)
+		}
+
+		if o.WarnPrivileged && port < minUnprivilegedPort {
+			log.Warnf("Port %d is below %d and can't be bound by containers running as a non-root user", port, minUnprivilegedPort)
+		}
+
+		return nil
+	}
+}
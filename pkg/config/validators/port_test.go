@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		minPort    int
+		input      string
+		wantErrMsg string
+	}{
+		{name: "lowest port", input: "1"},
+		{name: "privileged port", input: "80"},
+		{name: "highest port", input: "65535"},
+		{name: "unprivileged port with min", minPort: 1024, input: "1024"},
+		{name: "zero", input: "0", wantErrMsg: "invalid port 0: must be at least 1"},
+		{name: "too high", input: "65536", wantErrMsg: "invalid port 65536: must be at most 65535"},
+		{name: "negative", input: "-80", wantErrMsg: "invalid port -80: must not be negative"},
+		{name: "not a number", input: "http", wantErrMsg: `invalid port "http": must be a whole number`},
+		{name: "decimal", input: "80.5", wantErrMsg: `invalid port "80.5": must be a whole number`},
+		{name: "whitespace", input: " 80 ", wantErrMsg: `invalid port " 80 ": must not have leading or trailing whitespace`},
+		{name: "privileged port with min", minPort: 1024, input: "80", wantErrMsg: "invalid port 80: must be at least 1024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPortValidator(tt.minPort)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErrMsg)
+		})
+	}
+
+	assert.Nil(t, GetValidator("containerPort")("8080"))
+	assert.NotNil(t, GetValidator("containerPort")("0"))
+	assert.Nil(t, PortValidatorOptions{WarnPrivileged: true}.Validator()("443"), "privileged ports only warn")
+}
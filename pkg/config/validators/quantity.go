@@ -0,0 +1,266 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	decimalSISuffixes = []string{"m", "k", "M", "G", "T", "P", "E"}
+	binarySISuffixes  = []string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+)
+
+// cpuUnits and milliCPUUnits are common ways of writing cpu amounts that Kubernetes doesn't accept
+var (
+	cpuUnits      = []string{"core", "cores", "cpu", "cpus", "vcpu", "vcpus"}
+	milliCPUUnits = []string{"millicore", "millicores", "millicpu", "millicpus", "mcpu"}
+)
+
+// quantityKind selects the resource a quantity is validated for
+type quantityKind int
+
+const (
+	anyQuantity quantityKind = iota
+	cpuQuantity
+	memoryQuantity
+)
+
+// resourceQuantityValidator accepts non-negative Kubernetes quantities such as 500m, 2, 1.5G or 512Mi
+func resourceQuantityValidator(input string) error {
+	return validateQuantity(input, anyQuantity)
+}
+
+// cpuQuantityValidator accepts cpu amounts in cores such as 0.5 or millicores such as 500m
+func cpuQuantityValidator(input string) error {
+	return validateQuantity(input, cpuQuantity)
+}
+
+// memoryQuantityValidator accepts memory amounts in bytes such as 512Mi, 1Gi or 500M
+func memoryQuantityValidator(input string) error {
+	return validateQuantity(input, memoryQuantity)
+}
+
+func validateQuantity(input string, kind quantityKind) error {
+	err := checkQuantity(input, kind)
+	if err == nil {
+		return nil
+	}
+
+	if suggestion := suggestQuantity(input, kind); suggestion != "" {
+		return fmt.Errorf("invalid %s %q: %w, did you mean %s?", kind, input, err, suggestion)
+	}
+	return fmt.Errorf("invalid %s %q: %w", kind, input, err)
+}
+
+func (k quantityKind) String() string {
+	switch k {
+	case cpuQuantity:
+		return "cpu quantity"
+	case memoryQuantity:
+		return "memory quantity"
+	default:
+		return "resource quantity"
+	}
+}
+
+// checkQuantity checks input against the Kubernetes quantity grammar, a number followed by an optional decimal SI
+// suffix, binary SI suffix or exponent, and the restrictions of the resource kind
+func checkQuantity(input string, kind quantityKind) error {
+	number, suffix := splitQuantity(input)
+	if strings.HasPrefix(number, "-") {
+		return fmt.Errorf("must not be negative")
+	}
+	if !isQuantityNumber(strings.TrimPrefix(number, "+")) {
+		return fmt.Errorf("must start with a number such as 500, 0.5 or .5")
+	}
+
+	switch {
+	case suffix == "" || isQuantityExponent(suffix):
+	case slices.Contains(decimalSISuffixes, suffix):
+		if kind == memoryQuantity && suffix == "m" {
+			return fmt.Errorf("suffix m means thousandths of a byte, use Mi for mebibytes or M for megabytes")
+		}
+	case slices.Contains(binarySISuffixes, suffix):
+		if kind == cpuQuantity {
+			return fmt.Errorf("binary suffix %s is not allowed for cpu, use cores such as 0.5 or millicores such as 500m", suffix)
+		}
+	default:
+		switch kind {
+		case cpuQuantity:
+			return fmt.Errorf("unknown suffix %q, use cores such as 0.5 or millicores such as 500m", suffix)
+		case memoryQuantity:
+			return fmt.Errorf("unknown suffix %q, use bytes with a suffix such as 512Mi, 1Gi or 500M", suffix)
+		default:
+			return fmt.Errorf("unknown suffix %q, must be one of %s, %s or an exponent such as e3", suffix, strings.Join(decimalSISuffixes, ", "), strings.Join(binarySISuffixes, ", "))
+		}
+	}
+
+	return nil
+}
+
+// splitQuantity splits a quantity into its signed number and the suffix that follows it
+func splitQuantity(input string) (string, string) {
+	end := strings.IndexFunc(input, func(r rune) bool {
+		return !(r >= '0' && r <= '9') && r != '.' && r != '+' && r != '-'
+	})
+	if end == -1 {
+		return input, ""
+	}
+	return input[:end], input[end:]
+}
+
+// isQuantityNumber reports whether s is digits, digits.digits, digits. or .digits
+func isQuantityNumber(s string) bool {
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if !isDigits(whole) && !(whole == "" && hasPoint) {
+		return false
+	}
+	if hasPoint && !isDigits(fraction) && !(fraction == "" && whole != "") {
+		return false
+	}
+	return true
+}
+
+// isQuantityExponent reports whether s is an exponent such as e3 or E-2. E alone is the exa suffix.
+func isQuantityExponent(s string) bool {
+	if s == "" || (s[0] != 'e' && s[0] != 'E') {
+		return false
+	}
+	exponent := s[1:]
+	if exponent != "" && (exponent[0] == '+' || exponent[0] == '-') {
+		exponent = exponent[1:]
+	}
+	return isDigits(exponent)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeCPUQuantity returns the canonical Kubernetes form of a cpu amount: fractional cores become millicores, e.g.
+// 500m for 0.5, and units such as cores or millicores are replaced with the Kubernetes suffix, e.g. 250m for 250
+// millicores. Other valid quantities are returned unchanged.
+func NormalizeCPUQuantity(input string) (string, error) {
+	number, unit := splitQuantity(strings.Join(strings.Fields(input), ""))
+	lowerUnit := strings.ToLower(unit)
+
+	normalized := number + unit
+	switch {
+	case slices.Contains(milliCPUUnits, lowerUnit):
+		normalized = number + "m"
+	case unit == "" || slices.Contains(cpuUnits, lowerUnit):
+		normalized = number
+		whole, fraction, isFractional := strings.Cut(strings.TrimPrefix(number, "+"), ".")
+		if isFractional && isQuantityNumber(strings.TrimPrefix(number, "+")) {
+			fraction = strings.TrimRight(fraction, "0")
+			if len(fraction) > 3 {
+				return "", fmt.Errorf("invalid %s %q: must not be more precise than 1m, a thousandth of a core", cpuQuantity, input)
+			}
+
+			normalized = trimLeadingZeros(whole)
+			if fraction != "" {
+				normalized = trimLeadingZeros(whole+fraction+strings.Repeat("0", 3-len(fraction))) + "m"
+			}
+		}
+	}
+
+	return checkNormalizedQuantity(input, normalized, cpuQuantity)
+}
+
+// memoryUnitInterpretations maps lowercase memory units that Kubernetes doesn't accept to the binary suffix they are
+// read as. Units such as mb, which are written for both decimal and binary sizes, also have the decimal suffix they
+// could have meant, so that the interpretation can be noted.
+var memoryUnitInterpretations = map[string]struct {
+	binary  string
+	decimal string
+}{
+	"ki": {"Ki", ""}, "kib": {"Ki", ""}, "kb": {"Ki", "k"}, "k": {"Ki", "k"},
+	"mi": {"Mi", ""}, "mib": {"Mi", ""}, "mb": {"Mi", "M"}, "m": {"Mi", "M"},
+	"gi": {"Gi", ""}, "gib": {"Gi", ""}, "gb": {"Gi", "G"}, "g": {"Gi", "G"},
+	"ti": {"Ti", ""}, "tib": {"Ti", ""}, "tb": {"Ti", "T"}, "t": {"Ti", "T"},
+	"pi": {"Pi", ""}, "pib": {"Pi", ""}, "pb": {"Pi", "P"}, "p": {"Pi", "P"},
+	"ei": {"Ei", ""}, "eib": {"Ei", ""}, "eb": {"Ei", "E"},
+}
+
+// NormalizeMemoryQuantity returns the canonical Kubernetes form of a memory amount. Lowercase units are given their
+// Kubernetes suffix, e.g. 512Mi for 512mi, and units such as mb or gb, which are often written for binary sizes, are
+// read as Mi or Gi with a note in the log. A number without a unit is an error, since it is more likely a mistake than
+// an amount in bytes. Other valid quantities are returned unchanged.
+func NormalizeMemoryQuantity(input string) (string, error) {
+	number, unit := splitQuantity(strings.Join(strings.Fields(input), ""))
+	if unit == "" && isQuantityNumber(strings.TrimPrefix(number, "+")) {
+		return "", fmt.Errorf("invalid %s %q: add a unit such as %sMi or %sGi", memoryQuantity, input, number, number)
+	}
+
+	normalized := number + unit
+	if interpretation, ok := memoryUnitInterpretations[strings.ToLower(unit)]; ok && checkQuantity(normalized, memoryQuantity) != nil {
+		normalized = number + interpretation.binary
+		if interpretation.decimal != "" {
+			log.Infof("Reading memory quantity %q as %s, use %s%s if decimal units were meant", input, normalized, number, interpretation.decimal)
+		}
+	}
+
+	return checkNormalizedQuantity(input, normalized, memoryQuantity)
+}
+
+// checkNormalizedQuantity returns normalized if it is a valid quantity of kind, or otherwise the error of input
+func checkNormalizedQuantity(input, normalized string, kind quantityKind) (string, error) {
+	if err := validateQuantity(normalized, kind); err != nil {
+		if inputErr := validateQuantity(input, kind); inputErr != nil {
+			return "", inputErr
+		}
+		return "", err
+	}
+	return normalized, nil
+}
+
+func trimLeadingZeros(digits string) string {
+	if trimmed := strings.TrimLeft(digits, "0"); trimmed != "" {
+		return trimmed
+	}
+	return "0"
+}
+
+// suggestQuantity returns the valid quantity closest to an invalid input such as 512mb, 0.5 cores or 500 millicores,
+// or an empty string if there is none
+func suggestQuantity(input string, kind quantityKind) string {
+	number, unit := splitQuantity(strings.Join(strings.Fields(input), ""))
+	if !isQuantityNumber(strings.TrimPrefix(number, "+")) {
+		return ""
+	}
+
+	lowerUnit := strings.ToLower(unit)
+	suggestion := ""
+	switch {
+	case kind != memoryQuantity && slices.Contains(cpuUnits, lowerUnit):
+		suggestion = number
+	case kind != memoryQuantity && slices.Contains(milliCPUUnits, lowerUnit):
+		suggestion = number + "m"
+	case kind != cpuQuantity:
+		prefix := strings.TrimSuffix(strings.TrimSuffix(lowerUnit, "b"), "i")
+		for _, binarySuffix := range binarySISuffixes {
+			if strings.ToLower(binarySuffix[:1]) == prefix {
+				suggestion = number + binarySuffix
+			}
+		}
+		if unit == "" || slices.Contains([]string{"b", "byte", "bytes"}, lowerUnit) {
+			suggestion = number
+		}
+	}
+
+	if suggestion == "" || suggestion == input || checkQuantity(suggestion, kind) != nil {
+		return ""
+	}
+	return suggestion
+}
@@ -0,0 +1,65 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityValidators(t *testing.T) {
+	tests := []struct {
+		kind       string
+		input      string
+		wantErrMsg string
+	}{
+		{kind: "resourceQuantity", input: "1"},
+		{kind: "resourceQuantity", input: "500m"},
+		{kind: "resourceQuantity", input: "0.5"},
+		{kind: "resourceQuantity", input: ".5"},
+		{kind: "resourceQuantity", input: "1."},
+		{kind: "resourceQuantity", input: "+2"},
+		{kind: "resourceQuantity", input: "1.5G"},
+		{kind: "resourceQuantity", input: "512Mi"},
+		{kind: "resourceQuantity", input: "1E"},
+		{kind: "resourceQuantity", input: "129e6"},
+		{kind: "resourceQuantity", input: "1E-3"},
+		{kind: "resourceQuantity", input: "", wantErrMsg: "must start with a number"},
+		{kind: "resourceQuantity", input: ".", wantErrMsg: "must start with a number"},
+		{kind: "resourceQuantity", input: "1.2.3", wantErrMsg: "must start with a number"},
+		{kind: "resourceQuantity", input: "-1", wantErrMsg: `invalid resource quantity "-1": must not be negative`},
+		{kind: "resourceQuantity", input: "1e", wantErrMsg: `unknown suffix "e"`},
+		{kind: "resourceQuantity", input: "512mb", wantErrMsg: `invalid resource quantity "512mb": unknown suffix "mb", must be one of m, k, M, G, T, P, E, Ki, Mi, Gi, Ti, Pi, Ei or an exponent such as e3, did you mean 512Mi?`},
+		{kind: "resourceQuantity", input: "0.5 cores", wantErrMsg: "did you mean 0.5?"},
+		{kind: "resourceQuantity", input: "1 Gi", wantErrMsg: "did you mean 1Gi?"},
+		{kind: "resourceQuantity", input: "abc", wantErrMsg: `invalid resource quantity "abc": must start with a number such as 500, 0.5 or .5`},
+		{kind: "cpuQuantity", input: "250m"},
+		{kind: "cpuQuantity", input: "2"},
+		{kind: "cpuQuantity", input: "1Gi", wantErrMsg: `invalid cpu quantity "1Gi": binary suffix Gi is not allowed for cpu, use cores such as 0.5 or millicores such as 500m`},
+		{kind: "cpuQuantity", input: "0.5 cores", wantErrMsg: `invalid cpu quantity "0.5 cores": unknown suffix " cores", use cores such as 0.5 or millicores such as 500m, did you mean 0.5?`},
+		{kind: "cpuQuantity", input: "2vCPU", wantErrMsg: "did you mean 2?"},
+		{kind: "cpuQuantity", input: "500 millicores", wantErrMsg: "did you mean 500m?"},
+		{kind: "cpuQuantity", input: "512mb", wantErrMsg: `unknown suffix "mb", use cores such as 0.5 or millicores such as 500m`},
+		{kind: "memoryQuantity", input: "512Mi"},
+		{kind: "memoryQuantity", input: "0.5Gi"},
+		{kind: "memoryQuantity", input: "500M"},
+		{kind: "memoryQuantity", input: "1073741824"},
+		{kind: "memoryQuantity", input: "512mb", wantErrMsg: `invalid memory quantity "512mb": unknown suffix "mb", use bytes with a suffix such as 512Mi, 1Gi or 500M, did you mean 512Mi?`},
+		{kind: "memoryQuantity", input: "512MB", wantErrMsg: "did you mean 512Mi?"},
+		{kind: "memoryQuantity", input: "2GiB", wantErrMsg: "did you mean 2Gi?"},
+		{kind: "memoryQuantity", input: "64kb", wantErrMsg: "did you mean 64Ki?"},
+		{kind: "memoryQuantity", input: "1024 bytes", wantErrMsg: "did you mean 1024?"},
+		{kind: "memoryQuantity", input: "512m", wantErrMsg: "suffix m means thousandths of a byte, use Mi for mebibytes or M for megabytes, did you mean 512Mi?"},
+		{kind: "memoryQuantity", input: "1 core", wantErrMsg: `unknown suffix " core"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.input, func(t *testing.T) {
+			err := GetValidator(tt.kind)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
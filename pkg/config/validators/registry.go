@@ -0,0 +1,89 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// registry holds the validators registered with Register. It is safe for concurrent use, so kinds can be registered
+// while templates are validated, though registering kinds once at startup is expected.
+var registry = struct {
+	sync.RWMutex
+	validators map[string]func(string) error
+}{
+	validators: make(map[string]func(string) error),
+}
+
+// RegisterOption configures how Register adds a validator
+type RegisterOption func(*registerOptions)
+
+type registerOptions struct {
+	override bool
+}
+
+// Override lets Register replace the validator of a built-in kind or a kind that is already registered
+func Override() RegisterOption {
+	return func(o *registerOptions) {
+		o.override = true
+	}
+}
+
+// Register adds a validator for kind, e.g. an organization specific kind referenced by custom templates, which is then
+// returned by GetValidator and accepted as a known kind when draft configs are validated. Registering a built-in kind
+// or a kind that is already registered returns an error unless Override is passed. Register is safe for concurrent use.
+func Register(kind string, validator func(string) error, opts ...RegisterOption) error {
+	if kind == "" {
+		return fmt.Errorf("register validator: kind must not be empty")
+	}
+	if validator == nil {
+		return fmt.Errorf("register validator for kind %s: validator must not be nil", kind)
+	}
+
+	options := &registerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if !options.override {
+		if _, ok := builtinValidators[kind]; ok {
+			return fmt.Errorf("register validator for kind %s: kind is built in, pass Override to replace its validator", kind)
+		}
+		if _, ok := registry.validators[kind]; ok {
+			return fmt.Errorf("register validator for kind %s: kind is already registered, pass Override to replace its validator", kind)
+		}
+	}
+
+	registry.validators[kind] = validator
+	return nil
+}
+
+// List returns the sorted kinds that have a validator, built in or registered with Register. It is safe for
+// concurrent use.
+func List() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	kinds := make([]string, 0, len(builtinValidators)+len(registry.validators))
+	for kind := range builtinValidators {
+		kinds = append(kinds, kind)
+	}
+	for kind := range registry.validators {
+		if _, ok := builtinValidators[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+func registeredValidator(kind string) (func(string) error, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	validator, ok := registry.validators[kind]
+	return validator, ok
+}
@@ -0,0 +1,93 @@
+package validators
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unregister removes kind from the registry so tests don't leak registrations
+func unregister(kind string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.validators, kind)
+}
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() { unregister("internalServiceTier") })
+
+	errTier := errors.New("invalid service tier")
+	tierValidator := func(input string) error {
+		if input != "gold" && input != "silver" {
+			return errTier
+		}
+		return nil
+	}
+
+	assert.Nil(t, Register("internalServiceTier", tierValidator))
+	assert.Nil(t, GetValidator("internalServiceTier")("gold"))
+	assert.ErrorIs(t, GetValidator("internalServiceTier")("bronze"), errTier)
+	assert.True(t, slices.Contains(List(), "internalServiceTier"))
+
+	err := Register("internalServiceTier", defaultValidator)
+	assert.EqualError(t, err, "register validator for kind internalServiceTier: kind is already registered, pass Override to replace its validator")
+
+	assert.Nil(t, Register("internalServiceTier", defaultValidator, Override()))
+	assert.Nil(t, GetValidator("internalServiceTier")("bronze"))
+}
+
+func TestRegisterBuiltinKind(t *testing.T) {
+	t.Cleanup(func() { unregister("semver") })
+
+	err := Register("semver", defaultValidator)
+	assert.EqualError(t, err, "register validator for kind semver: kind is built in, pass Override to replace its validator")
+	assert.NotNil(t, GetValidator("semver")("not-a-version"))
+
+	assert.Nil(t, Register("semver", defaultValidator, Override()))
+	assert.Nil(t, GetValidator("semver")("not-a-version"))
+	assert.Equal(t, 1, len(slices.Compact(slices.DeleteFunc(List(), func(kind string) bool { return kind != "semver" }))))
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	assert.EqualError(t, Register("", defaultValidator), "register validator: kind must not be empty")
+	assert.EqualError(t, Register("someKind", nil), "register validator for kind someKind: validator must not be nil")
+}
+
+func TestList(t *testing.T) {
+	kinds := List()
+	assert.True(t, slices.IsSorted(kinds))
+	assert.Contains(t, kinds, "semver")
+	assert.Contains(t, kinds, "acrName")
+	assert.Len(t, kinds, len(builtinValidators))
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	kinds := []string{"concurrentKindA", "concurrentKindB", "concurrentKindC"}
+	t.Cleanup(func() {
+		for _, kind := range kinds {
+			unregister(kind)
+		}
+	})
+
+	var wg sync.WaitGroup
+	for _, kind := range kinds {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, Register(kind, defaultValidator))
+		}()
+		go func() {
+			defer wg.Done()
+			GetValidator(kind)("value")
+			List()
+		}()
+	}
+	wg.Wait()
+
+	for _, kind := range kinds {
+		assert.Contains(t, List(), kind)
+	}
+}
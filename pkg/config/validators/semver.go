@@ -0,0 +1,47 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// semverExample is shown in semver validation errors
+const semverExample = "e.g. 1.2.3, 1.2.3-rc.1 or 1.2.3+build.5"
+
+// semverValidator accepts a concrete semantic version with an optional leading v, such as a Helm chart version.
+// Ranges and wildcards are rejected.
+func semverValidator(input string) error {
+	version := input
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') {
+		version = version[1:]
+	}
+
+	if isVersionRange(version) {
+		return fmt.Errorf("invalid semantic version %q: must be a concrete version, not a range or wildcard, %s", input, semverExample)
+	}
+
+	if _, err := semver.Parse(version); err != nil {
+		return fmt.Errorf("invalid semantic version %q: %s, %s", input, err, semverExample)
+	}
+
+	return nil
+}
+
+// isVersionRange returns true if version uses range operators or wildcards
+func isVersionRange(version string) bool {
+	if strings.ContainsAny(version, "<>=~^*| ") {
+		return true
+	}
+
+	core, _, _ := strings.Cut(version, "-")
+	core, _, _ = strings.Cut(core, "+")
+	for _, part := range strings.Split(core, ".") {
+		if part == "x" || part == "X" {
+			return true
+		}
+	}
+
+	return false
+}
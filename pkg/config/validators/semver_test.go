@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemverValidator(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErrMsg string
+	}{
+		{input: "0.1.0"},
+		{input: "1.16.0"},
+		{input: "v1.2.3"},
+		{input: "V1.2.3"},
+		{input: "1.2.3-rc.1"},
+		{input: "1.2.3-alpha.beta.1"},
+		{input: "1.2.3+build.5"},
+		{input: "1.2.3-rc.1+build.5"},
+		{input: "v1.0.0-x.7.z.92"},
+		{input: "1.2", wantErrMsg: `invalid semantic version "1.2": No Major.Minor.Patch elements found, e.g. 1.2.3, 1.2.3-rc.1 or 1.2.3+build.5`},
+		{input: "v", wantErrMsg: "e.g. 1.2.3"},
+		{input: "latest", wantErrMsg: "e.g. 1.2.3"},
+		{input: "01.2.3", wantErrMsg: "e.g. 1.2.3"},
+		{input: ">=1.2.3", wantErrMsg: `invalid semantic version ">=1.2.3": must be a concrete version, not a range or wildcard`},
+		{input: "^1.2.3", wantErrMsg: "not a range or wildcard"},
+		{input: "~1.2.3", wantErrMsg: "not a range or wildcard"},
+		{input: "1.2.x", wantErrMsg: "not a range or wildcard"},
+		{input: "1.*", wantErrMsg: "not a range or wildcard"},
+		{input: "1.0.0 - 2.0.0", wantErrMsg: "not a range or wildcard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("semver")(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONValidator returns a validator for JSON values such as a JSON patch. If requireObject is true, the value must be
+// a JSON object.
+func JSONValidator(requireObject bool) func(string) error {
+	return func(input string) error {
+		if err := validateJSON(input, requireObject); err != nil {
+			return fmt.Errorf("invalid JSON value: %w", err)
+		}
+		return nil
+	}
+}
+
+// YAMLValidator returns a validator for single YAML documents such as extra pod annotations. If requireMapping is
+// true, the document must be a YAML mapping.
+func YAMLValidator(requireMapping bool) func(string) error {
+	return func(input string) error {
+		if err := validateYAML(input, requireMapping); err != nil {
+			return fmt.Errorf("invalid YAML value: %w", err)
+		}
+		return nil
+	}
+}
+
+func validateJSON(input string, requireObject bool) error {
+	var value any
+	if err := json.Unmarshal([]byte(input), &value); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := offsetPosition(input, syntaxErr.Offset)
+			return fmt.Errorf("line %d, column %d: %s", line, column, syntaxErr)
+		}
+		return err
+	}
+
+	if _, ok := value.(map[string]any); requireObject && !ok {
+		return fmt.Errorf("must be a JSON object, e.g. {\"key\": \"value\"}")
+	}
+
+	return nil
+}
+
+// offsetPosition returns the 1-based line and column of the byte before offset, where encoding/json reports errors
+func offsetPosition(input string, offset int64) (int, int) {
+	before := input[:max(0, min(int(offset)-1, len(input)))]
+	line := strings.Count(before, "\n") + 1
+	column := len(before) - strings.LastIndex(before, "\n")
+	return line, column
+}
+
+func validateYAML(input string, requireMapping bool) error {
+	decoder := yaml.NewDecoder(bytes.NewBufferString(input))
+
+	var document yaml.Node
+	if err := decoder.Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	var next yaml.Node
+	if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("line %d: must be a single YAML document, found another document", next.Line)
+	}
+
+	if requireMapping && (len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode) {
+		return fmt.Errorf("must be a YAML mapping, e.g. key: value")
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONValidator(t *testing.T) {
+	tests := []struct {
+		input         string
+		requireObject bool
+		wantErrMsg    string
+	}{
+		{input: `{"key": "value"}`},
+		{input: `[{"op": "add", "path": "/spec/replicas", "value": 3}]`},
+		{input: `"text"`},
+		{input: `3`},
+		{input: `{"key": "value"}`, requireObject: true},
+		{input: `{"a": 1,}`, wantErrMsg: "invalid JSON value: line 1, column 9: invalid character '}' looking for beginning of object key string"},
+		{input: "{\n  \"a\": 1,\n  \"b\": 2,\n}", wantErrMsg: "line 4, column 1: invalid character '}'"},
+		{input: `{"a": 1} {"b": 2}`, wantErrMsg: "line 1, column 10: invalid character '{' after top-level value"},
+		{input: `{"a": 1`, wantErrMsg: "unexpected end of JSON input"},
+		{input: ``, wantErrMsg: "unexpected end of JSON input"},
+		{input: `[1, 2]`, requireObject: true, wantErrMsg: `invalid JSON value: must be a JSON object, e.g. {"key": "value"}`},
+		{input: `null`, requireObject: true, wantErrMsg: "must be a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := JSONValidator(tt.requireObject)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestYAMLValidator(t *testing.T) {
+	tests := []struct {
+		input          string
+		requireMapping bool
+		wantErrMsg     string
+	}{
+		{input: "key: value"},
+		{input: "prometheus.io/scrape: \"true\"\nprometheus.io/port: \"9090\""},
+		{input: "- a\n- b"},
+		{input: "---\nkey: value\n"},
+		{input: ""},
+		{input: "key: value", requireMapping: true},
+		{input: "a:\n\tb: 1", wantErrMsg: "invalid YAML value: yaml: line 2: found character that cannot start any token"},
+		{input: "key: [a, b", wantErrMsg: "yaml: line 1: did not find expected ',' or ']'"},
+		{input: "a: 1\n---\nb: 2", wantErrMsg: "invalid YAML value: line 2: must be a single YAML document, found another document"},
+		{input: "a: 1\n---\nb: [", wantErrMsg: "did not find expected node content"},
+		{input: "- a", requireMapping: true, wantErrMsg: "invalid YAML value: must be a YAML mapping, e.g. key: value"},
+		{input: "", requireMapping: true, wantErrMsg: "must be a YAML mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := YAMLValidator(tt.requireMapping)(tt.input)
+			if tt.wantErrMsg == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestStructuredValueKinds(t *testing.T) {
+	assert.Nil(t, GetValidator("jsonValue")(`[1, 2]`))
+	assert.ErrorContains(t, GetValidator("jsonValue")(`{"a": 1,}`), "invalid JSON value")
+	assert.Nil(t, GetValidator("yamlValue")("- a"))
+	assert.ErrorContains(t, GetValidator("yamlValue")("a: 1\n---\nb: 2"), "must be a single YAML document")
+}
@@ -0,0 +1,37 @@
+package validators
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// urlValidator accepts absolute http and https URLs with a host, e.g. https://example.com:8443/hooks?id=1
+func urlValidator(input string) error {
+	return validateURL(input, []string{"http", "https"})
+}
+
+// httpsURLValidator accepts absolute https URLs with a host
+func httpsURLValidator(input string) error {
+	return validateURL(input, []string{"https"})
+}
+
+func validateURL(input string, schemes []string) error {
+	u, err := url.Parse(input)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", input, err)
+	}
+
+	if u.Scheme == "" {
+		return fmt.Errorf("invalid URL %q: missing scheme, e.g. %s://%s", input, schemes[len(schemes)-1], input)
+	}
+	if !slices.Contains(schemes, u.Scheme) {
+		return fmt.Errorf("invalid URL %q: unsupported scheme %s, must be %s", input, u.Scheme, strings.Join(schemes, " or "))
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return fmt.Errorf("invalid URL %q: missing host", input)
+	}
+
+	return nil
+}
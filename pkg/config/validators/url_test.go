@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLValidator(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantErrMsg   string
+		wantHTTPSErr string
+	}{
+		{input: "https://example.com"},
+		{input: "https://example.com:8443/hooks/build?id=1&force=true#top"},
+		{input: "https://[2001:db8::1]:443/issuer"},
+		{input: "https://login.microsoftonline.com/tenant/v2.0"},
+		{input: "http://localhost:8080/path", wantHTTPSErr: `invalid URL "http://localhost:8080/path": unsupported scheme http, must be https`},
+		{input: "http://[::1]", wantHTTPSErr: "unsupported scheme http"},
+		{input: "example.com", wantErrMsg: `invalid URL "example.com": missing scheme, e.g. https://example.com`},
+		{input: "/hooks/build", wantErrMsg: "missing scheme"},
+		{input: "ftp://example.com/file", wantErrMsg: `invalid URL "ftp://example.com/file": unsupported scheme ftp, must be `},
+		{input: "https://", wantErrMsg: `invalid URL "https://": missing host`},
+		{input: "https:///path", wantErrMsg: "missing host"},
+		{input: "https://:443", wantErrMsg: "missing host"},
+		{input: "https://exa mple.com", wantErrMsg: "invalid URL"},
+		{input: "https://[::1", wantErrMsg: "missing ']' in host"},
+	}
+
+	assert.EqualError(t, GetValidator("url")("ftp://example.com"), `invalid URL "ftp://example.com": unsupported scheme ftp, must be http or https`)
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := GetValidator("url")(tt.input)
+			httpsErr := GetValidator("httpsUrl")(tt.input)
+			if tt.wantErrMsg != "" {
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+				assert.ErrorContains(t, httpsErr, tt.wantErrMsg)
+				return
+			}
+
+			assert.Nil(t, err)
+			if tt.wantHTTPSErr != "" {
+				assert.ErrorContains(t, httpsErr, tt.wantHTTPSErr)
+			} else {
+				assert.Nil(t, httpsErr)
+			}
+		})
+	}
+}
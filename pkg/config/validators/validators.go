@@ -3,47 +3,85 @@ package validators
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
+// builtinValidators are the validators of the kinds built into draft
+var builtinValidators = map[string]func(string) error{
+	"acrLoginServer":          acrNameOrLoginServerValidator,
+	"acrName":                 acrNameValidator,
+	"acrNameOrLoginServer":    acrNameOrLoginServerValidator,
+	"azureResourceGroup":      azureResourceGroupValidator,
+	"storageAccountName":      storageAccountNameValidator,
+	"boolean":                 booleanValidator,
+	"cidr":                    CIDRValidator(AnyIPFamily),
+	"containerPort":           NewPortValidator(1),
+	"cpuQuantity":             cpuQuantityValidator,
+	"cronSchedule":            cronScheduleValidator,
+	"duration":                DurationValidatorOptions{}.Validator(),
+	"durationStrict":          DurationValidatorOptions{RejectZero: true, RejectNegative: true}.Validator(),
+	"envVarMap":               keyValueMapValidator,
+	"environmentName":         enumValidator("environment name", defaultEnvironmentNames...),
+	"existingPath":            RepoPathValidatorOptions{MustExist: true}.Validator(),
+	"gitRef":                  gitRefValidator,
+	"guid":                    guidValidator,
+	"helmReleaseName":         helmReleaseNameValidator,
+	"hostname":                HostnameValidatorOptions{}.Validator(),
+	"wildcardHostname":        HostnameValidatorOptions{AllowWildcard: true}.Validator(),
+	"httpsUrl":                httpsURLValidator,
+	"imageTag":                imageTagValidator,
+	"imageTagStrict":          imageTagStrictValidator,
+	"imageReference":          imageReferenceValidator,
+	"imagePullPolicy":         enumValidator("image pull policy", "Always", "IfNotPresent", "Never"),
+	"integer":                 integerValidator,
+	"ipAddress":               IPAddressValidator(AnyIPFamily),
+	"jsonValue":               JSONValidator(false),
+	"kubeName":                kubeNameValidator,
+	"kubernetesLabelKey":      kubernetesLabelKeyValidator,
+	"kubernetesLabelValue":    kubernetesLabelValueValidator,
+	"kubernetesNamespace":     KubernetesNamespaceValidator(false),
+	"kubernetesProbeType":     enumValidator("probe type", "httpGet", "tcpSocket"),
+	"kubernetesResourceName":  kubernetesResourceNameValidator,
+	"kubernetesSubdomainName": kubernetesSubdomainNameValidator,
+	"memoryQuantity":          memoryQuantityValidator,
+	"resourceQuantity":        resourceQuantityValidator,
+	"replicaCount":            IntegerRangeValidator(minReplicaCount, maxReplicaCount),
+	"repoRelativePath":        RepoPathValidatorOptions{}.Validator(),
+	"scalingResourceType":     enumValidator("scaling resource type", "cpu", "memory"),
+	"semver":                  semverValidator,
+	"url":                     urlValidator,
+	"yamlValue":               YAMLValidator(false),
+}
+
+// GetValidator returns the validator of a kind registered with Register, or else of a built-in kind. Kinds without a
+// validator accept any value.
 func GetValidator(variableKind string) func(string) error {
-	switch variableKind {
-	case "envVarMap":
-		return keyValueMapValidator
-	case "imagePullPolicy":
-		return imagePullPolicyValidator
-	case "kubernetesProbeType":
-		return kubernetesProbeTypeValidator
-	case "scalingResourceType":
-		return scalingResourceTypeValidator
-	default:
-		return defaultValidator
+	if validator, ok := registeredValidator(variableKind); ok {
+		return validator
 	}
-}
 
-func imagePullPolicyValidator(input string) error {
-	switch input {
-	case "Always", "IfNotPresent", "Never":
-		return nil
-	default:
-		return fmt.Errorf("invalid image pull policy: %s. valid values: Always, IfNotPresent, Never", input)
+	if validator, ok := builtinValidators[variableKind]; ok {
+		return validator
 	}
+
+	return defaultValidator
 }
 
-func scalingResourceTypeValidator(input string) error {
-	switch input {
-	case "cpu", "memory":
-		return nil
-	default:
-		return fmt.Errorf("invalid scaling resource type: %s. valid values: cpu, memory", input)
-	}
+func booleanValidator(input string) error {
+	_, err := ParseBoolean(input)
+	return err
 }
 
-func kubernetesProbeTypeValidator(input string) error {
-	switch input {
-	case "httpGet", "tcpSocket":
-		return nil
+// ParseBoolean parses true, false, yes, no, y, n, on, off, 1 or 0 in any case. It is the parser of both the boolean
+// kind and the bool type.
+func ParseBoolean(input string) (bool, error) {
+	switch strings.ToLower(input) {
+	case "true", "yes", "y", "on", "1":
+		return true, nil
+	case "false", "no", "n", "off", "0":
+		return false, nil
 	default:
-		return fmt.Errorf("invalid probe type: %s. valid values: httpGet, tcpSocket", input)
+		return false, fmt.Errorf("invalid boolean: %s. valid values: true, false, yes, no, y, n, on, off, 1, 0 in any case", input)
 	}
 }
 
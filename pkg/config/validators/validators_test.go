@@ -15,9 +15,9 @@ func TestDefaultValidator(t *testing.T) {
 }
 
 func TestKubernetesProbeTypeValidator(t *testing.T) {
-	assert.Nil(t, kubernetesProbeTypeValidator("httpGet"))
-	assert.Nil(t, kubernetesProbeTypeValidator("tcpSocket"))
-	assert.NotNil(t, kubernetesProbeTypeValidator("exec"))
+	assert.Nil(t, GetValidator("kubernetesProbeType")("httpGet"))
+	assert.Nil(t, GetValidator("kubernetesProbeType")("tcpSocket"))
+	assert.NotNil(t, GetValidator("kubernetesProbeType")("exec"))
 }
 
 func TestKeyValueMapValidator(t *testing.T) {
@@ -26,14 +26,23 @@ func TestKeyValueMapValidator(t *testing.T) {
 }
 
 func TestScalingResourceTypeValidator(t *testing.T) {
-	assert.Nil(t, scalingResourceTypeValidator("cpu"))
-	assert.Nil(t, scalingResourceTypeValidator("memory"))
-	assert.NotNil(t, scalingResourceTypeValidator("disk"))
+	assert.Nil(t, GetValidator("scalingResourceType")("cpu"))
+	assert.Nil(t, GetValidator("scalingResourceType")("memory"))
+	assert.NotNil(t, GetValidator("scalingResourceType")("disk"))
 }
 
 func TestImagePullPolicyValidator(t *testing.T) {
-	assert.Nil(t, imagePullPolicyValidator("Always"))
-	assert.Nil(t, imagePullPolicyValidator("IfNotPresent"))
-	assert.Nil(t, imagePullPolicyValidator("Never"))
-	assert.NotNil(t, imagePullPolicyValidator("Sometimes"))
+	assert.Nil(t, GetValidator("imagePullPolicy")("Always"))
+	assert.Nil(t, GetValidator("imagePullPolicy")("IfNotPresent"))
+	assert.Nil(t, GetValidator("imagePullPolicy")("Never"))
+	assert.NotNil(t, GetValidator("imagePullPolicy")("Sometimes"))
+}
+
+func TestBooleanValidator(t *testing.T) {
+	for _, input := range []string{"true", "True", "TRUE", "false", "yes", "YES", "no", "No", "y", "N", "on", "Off", "1", "0"} {
+		assert.Nil(t, GetValidator("boolean")(input))
+	}
+	for _, input := range []string{"enabled", "", "2", " true"} {
+		assert.ErrorContains(t, GetValidator("boolean")(input), "valid values: true, false, yes, no, y, n, on, off, 1, 0 in any case")
+	}
 }
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// destinationPattern returns the destinations pattern of the draft config matching relativePath, a slash separated
+// path relative to the template directory, or false if none matches. More than one matching pattern is an error.
+func destinationPattern(draftTemplate *Template, relativePath string) (string, bool, error) {
+	var matches []string
+	for pattern := range draftTemplate.Config.Destinations {
+		if matchGlob(pattern, relativePath) {
+			matches = append(matches, pattern)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return matches[0], true, nil
+	default:
+		slices.Sort(matches)
+		return "", false, fmt.Errorf("destinations patterns %s all match %s", strings.Join(matches, ", "), relativePath)
+	}
+}
+
+// applyDestination moves outputFile, the slash separated output path of the template file at relativePath, into the
+// directory its destinations pattern maps to. The fixed directories the pattern starts with are replaced by the
+// destination directory, so workflows/*.yaml to .github/workflows writes workflows/deploy.yaml to
+// .github/workflows/deploy.yaml and charts/** to charts/myapp keeps the directories below charts. An output path
+// that doesn't start with those directories, e.g. because of a file name override, is placed below the destination
+// directory as a whole.
+func applyDestination(draftTemplate *Template, relativePath, outputFile string) (string, error) {
+	pattern, ok, err := destinationPattern(draftTemplate, relativePath)
+	if err != nil || !ok {
+		return outputFile, err
+	}
+
+	prefix := fixedPatternDir(pattern)
+	if prefix != "" {
+		if trimmed, ok := strings.CutPrefix(outputFile, prefix+"/"); ok {
+			outputFile = trimmed
+		}
+	}
+
+	return path.Join(draftTemplate.Config.Destinations[pattern], outputFile), nil
+}
+
+// fixedPatternDir returns the leading directories of pattern that contain no wildcards, e.g. charts for
+// charts/**/*.yaml and workflows for workflows/deploy.yaml
+func fixedPatternDir(pattern string) string {
+	elements := strings.Split(pattern, "/")
+	var fixed []string
+	for _, element := range elements[:len(elements)-1] {
+		if strings.ContainsAny(element, `*?[\`) {
+			break
+		}
+		fixed = append(fixed, element)
+	}
+	return strings.Join(fixed, "/")
+}
+
+// validateDestinations checks that no template file matches more than one destinations pattern
+func (t *Template) validateDestinations() error {
+	if len(t.Config.Destinations) == 0 {
+		return nil
+	}
+
+	return fs.WalkDir(t.templateFiles, t.src, func(inputFile string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		_, _, err = destinationPattern(t, sourceRelativePath(t, inputFile))
+		return err
+	})
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FileDiffStatus tells how a generated file compares with the file at the destination
+type FileDiffStatus string
+
+const (
+	// FileDiffNew is a generated file that doesn't exist at the destination
+	FileDiffNew FileDiffStatus = "new"
+	// FileDiffModified is a generated file whose content differs from the file at the destination
+	FileDiffModified FileDiffStatus = "modified"
+	// FileDiffUnchanged is a generated file whose content matches the file at the destination
+	FileDiffUnchanged FileDiffStatus = "unchanged"
+	// FileDiffDeletedFromTemplate is a file the generation manifest records as generated that the template no longer
+	// generates
+	FileDiffDeletedFromTemplate FileDiffStatus = "deleted-from-template"
+)
+
+// FileDiff describes how one file would change if the template was generated
+type FileDiff struct {
+	// Path is the path of the file relative to the destination, like the keys returned by Render
+	Path   string
+	Status FileDiffStatus
+	// Diff is a unified diff from the file at the destination to the generated file. It is only set for modified text
+	// files.
+	Diff string
+}
+
+// Diff renders the template with Render and compares the files with the destination, read from destFS, without
+// writing anything. Files recorded for this template in the generation manifest at GenerationManifestPath, or
+// DefaultGenerationManifestPath if it is empty, that still exist but that the template doesn't render anymore are
+// reported as deleted from the template, e.g. a manifest the template used to generate under another name. Without
+// such a manifest no file is reported as deleted, since other files at the destination weren't generated by draft.
+// The diffs are sorted by path.
+func (t *Template) Diff(destFS fs.FS) ([]FileDiff, error) {
+	files, err := t.Render()
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []FileDiff
+	for outputFile, content := range files {
+		diff, err := diffFile(destFS, outputFile, content)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+
+	deleted, err := t.deletedFromTemplate(destFS, files)
+	if err != nil {
+		return nil, err
+	}
+	diffs = append(diffs, deleted...)
+
+	slices.SortFunc(diffs, func(a, b FileDiff) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return diffs, nil
+}
+
+// deletedFromTemplate returns the files the generation manifest records for this template that exist at the
+// destination but are neither rendered nor left out on purpose
+func (t *Template) deletedFromTemplate(destFS fs.FS, files map[string][]byte) ([]FileDiff, error) {
+	manifestPath := t.GenerationManifestPath
+	if manifestPath == "" {
+		manifestPath = DefaultGenerationManifestPath
+	}
+
+	manifest, err := LoadGenerationManifest(destFS, manifestPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if manifest.TemplateName != t.Config.TemplateName {
+		return nil, nil
+	}
+
+	// files left out on purpose aren't deleted from the template
+	skippedFiles := make(map[string]bool)
+	for _, skipped := range t.skippedFiles {
+		skippedFiles[skipped.Path] = true
+	}
+
+	var deleted []FileDiff
+	for _, file := range manifest.Files {
+		if !fs.ValidPath(file.Path) {
+			return nil, fmt.Errorf("generation manifest %s: %s is not a relative path inside the destination", manifestPath, file.Path)
+		}
+
+		generatedFile := filepath.FromSlash(file.Path)
+		if _, ok := files[generatedFile]; ok || skippedFiles[generatedFile] {
+			continue
+		}
+
+		info, err := fs.Stat(destFS, file.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", generatedFile, err)
+		}
+		if info.Mode().IsRegular() {
+			deleted = append(deleted, FileDiff{Path: generatedFile, Status: FileDiffDeletedFromTemplate})
+		}
+	}
+
+	return deleted, nil
+}
+
+func diffFile(destFS fs.FS, outputFile string, content []byte) (FileDiff, error) {
+	existing, err := fs.ReadFile(destFS, filepath.ToSlash(outputFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return FileDiff{Path: outputFile, Status: FileDiffNew}, nil
+	}
+	if err != nil {
+		return FileDiff{}, fmt.Errorf("reading %s: %w", outputFile, err)
+	}
+
+	if bytes.Equal(existing, content) {
+		return FileDiff{Path: outputFile, Status: FileDiffUnchanged}, nil
+	}
+
+	diff := FileDiff{Path: outputFile, Status: FileDiffModified}
+	if isBinary(existing) || isBinary(content) {
+		return diff, nil
+	}
+
+	diff.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(string(existing)),
+		B:        splitLines(string(content)),
+		FromFile: "a/" + filepath.ToSlash(outputFile),
+		ToFile:   "b/" + filepath.ToSlash(outputFile),
+		Context:  3,
+	})
+	if err != nil {
+		return FileDiff{}, fmt.Errorf("diffing %s: %w", outputFile, err)
+	}
+	return diff, nil
+}
+
+// splitLines splits text into lines that all end with a newline, which the unified diff expects
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+// isBinary returns true if content isn't UTF-8 text
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Azure/draft/pkg/config"
+	"github.com/Azure/draft/pkg/templatewriter/writers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	writer := &countingWriter{}
+	testTemplate := &Template{
+		Config: &config.DraftConfig{
+			TemplateName: "diff",
+			Versions:     []string{"0.0.1"},
+			Variables:    []*config.BuilderVar{{Name: "APPNAME", Value: "myapp"}},
+		},
+		templateFiles: fstest.MapFS{
+			"src/Dockerfile":             {Data: []byte(`FROM {{ .Config.GetVariableValue "APPNAME" }}`)},
+			"src/workflow.yaml":          {Data: []byte("name: {{ .Config.GetVariableValue \"APPNAME\" }}\non: push\n")},
+			"src/manifests/service.yaml": {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}-svc`)},
+			"src/manifests/logo.bin":     {Data: []byte{0x89, 0x50, 0x4e, 0x47, 0, 4, 5, 6}},
+		},
+		templateWriter: writer,
+		src:            "src",
+		dest:           "out",
+		version:        "0.0.1",
+	}
+
+	diffs, err := testTemplate.Diff(os.DirFS(filepath.Join("testdata", "diff")))
+	assert.Nil(t, err)
+	assert.Empty(t, writer.writes)
+	assert.Equal(t, []FileDiff{
+		{Path: "Dockerfile", Status: FileDiffUnchanged},
+		{Path: filepath.Join("manifests", "logo.bin"), Status: FileDiffModified},
+		{Path: filepath.Join("manifests", "old-configmap.yaml"), Status: FileDiffDeletedFromTemplate},
+		{Path: filepath.Join("manifests", "service.yaml"), Status: FileDiffNew},
+		{
+			Path:   "workflow.yaml",
+			Status: FileDiffModified,
+			Diff:   "--- a/workflow.yaml\n+++ b/workflow.yaml\n@@ -1,2 +1,2 @@\n-name: oldapp\n+name: myapp\n on: push\n",
+		},
+	}, diffs)
+
+	// excluded files aren't reported as deleted from the template
+	testTemplate.ExcludeGlobs = []string{"manifests/*.bin", "workflow.yaml"}
+	diffs, err = testTemplate.Diff(os.DirFS(filepath.Join("testdata", "diff")))
+	assert.Nil(t, err)
+	assert.Equal(t, []FileDiff{
+		{Path: "Dockerfile", Status: FileDiffUnchanged},
+		{Path: filepath.Join("manifests", "old-configmap.yaml"), Status: FileDiffDeletedFromTemplate},
+		{Path: filepath.Join("manifests", "service.yaml"), Status: FileDiffNew},
+	}, diffs)
+
+	// files not recorded as generated by this template aren't reported as deleted from the template
+	testTemplate.ExcludeGlobs = nil
+	destFS := fstest.MapFS{
+		"manifests/old-configmap.yaml": {Data: []byte("kind: ConfigMap\n")},
+		".draft/manifest.yaml":         {Data: []byte("templateName: other\nversion: 0.0.1\nfiles:\n- path: manifests/old-configmap.yaml\n  sha256: abc\n")},
+	}
+	for _, fileSys := range []fstest.MapFS{destFS, {"manifests/old-configmap.yaml": destFS["manifests/old-configmap.yaml"]}} {
+		diffs, err = testTemplate.Diff(fileSys)
+		assert.Nil(t, err)
+		for _, diff := range diffs {
+			assert.NotEqual(t, FileDiffDeletedFromTemplate, diff.Status, diff.Path)
+		}
+	}
+}
+
+func TestDiffEmptyDestination(t *testing.T) {
+	testTemplate := newFileNameOverrideTemplate(&writers.FileMapWriter{})
+
+	diffs, err := testTemplate.Diff(fstest.MapFS{})
+	assert.Nil(t, err)
+	assert.Len(t, diffs, 4)
+	for _, diff := range diffs {
+		assert.Equal(t, FileDiffNew, diff.Status, diff.Path)
+	}
+}
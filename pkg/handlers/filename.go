@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	tmpl "text/template"
+	"text/template/parse"
+)
+
+// windowsReservedFileNames can't be used as file names on Windows, with or without an extension
+var windowsReservedFileNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// templateVariables returns the value of every variable by name, validated and transformed as GetVariableValue
+// returns it, and an empty string for variables without a value. Output file names are executed with it as data and
+// template files read it as .Variables.
+func templateVariables(draftTemplate *Template) (map[string]any, error) {
+	variables := make(map[string]any)
+	for name, value := range draftTemplate.Config.GetVariableMap() {
+		variables[name] = value
+	}
+
+	validated, err := draftTemplate.Config.GetValidatedVariableMap()
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range validated {
+		variables[name] = value
+	}
+
+	return variables, nil
+}
+
+// expandOutputFileName executes outputFile, a path relative to the destination, as a template with the delimiters
+// and functions of the template files and variables as data, e.g. deploy-{{ .APPNAME }}.yaml. Names without the left
+// delimiter are returned unchanged; a literal delimiter can be written as {{`{{`}}. The expanded path must stay inside
+// the destination and be a valid path on Windows.
+func expandOutputFileName(draftTemplate *Template, inputFile, outputFile string, variables map[string]any) (string, error) {
+	leftDelim := draftTemplate.Config.LeftDelim
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+	name := filepath.ToSlash(outputFile)
+	if !strings.Contains(name, leftDelim) {
+		return outputFile, nil
+	}
+
+	fileNameTemplate, err := tmpl.New("filename").
+		Option("missingkey=error").
+		Delims(draftTemplate.Config.LeftDelim, draftTemplate.Config.RightDelim).
+		Funcs(TemplateFuncs()).
+		Parse(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid output file name %q for %s: %w", name, inputFile, err)
+	}
+
+	var expanded bytes.Buffer
+	if err := fileNameTemplate.Execute(&expanded, variables); err != nil {
+		return "", fmt.Errorf("expanding output file name %q for %s: %w", name, inputFile, err)
+	}
+	markVariablesUsed(draftTemplate, fileNameTemplate.Tree.Root, nil)
+
+	expandedPath := filepath.FromSlash(expanded.String())
+	if !filepath.IsLocal(expandedPath) {
+		return "", fmt.Errorf("output file name %q for %s expands to %q, which is outside the destination", name, inputFile, expanded.String())
+	}
+	if err := checkWindowsFileName(expanded.String()); err != nil {
+		return "", fmt.Errorf("output file name %q for %s expands to %q: %w", name, inputFile, expanded.String(), err)
+	}
+
+	return filepath.Clean(expandedPath), nil
+}
+
+// checkWindowsFileName rejects slash separated paths with names that can't be created on Windows
+func checkWindowsFileName(name string) error {
+	for _, element := range strings.Split(name, "/") {
+		if i := strings.IndexFunc(element, func(r rune) bool { return r < ' ' || strings.ContainsRune(`<>:"\|?*`, r) }); i >= 0 {
+			return fmt.Errorf("%q is not allowed in file names on Windows", element[i])
+		}
+		if element != "." && element != ".." && strings.TrimRight(element, ". ") != element {
+			return fmt.Errorf("file name %q ends with a dot or space, which Windows removes", element)
+		}
+
+		base, _, _ := strings.Cut(element, ".")
+		for _, reserved := range windowsReservedFileNames {
+			if strings.EqualFold(base, reserved) {
+				return fmt.Errorf("file name %q is reserved on Windows", element)
+			}
+		}
+	}
+	return nil
+}
+
+// markVariablesUsed marks the variables a template reads from a map of variables at the field path prefix as used,
+// e.g. APPNAME for {{ .APPNAME }} in a file name with no prefix or {{ .Variables.APPNAME }} in a file with the prefix
+// Variables
+func markVariablesUsed(draftTemplate *Template, node parse.Node, prefix []string) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			markVariablesUsed(draftTemplate, child, prefix)
+		}
+	case *parse.ActionNode:
+		markVariablesUsed(draftTemplate, node.Pipe, prefix)
+	case *parse.IfNode:
+		markBranchVariablesUsed(draftTemplate, &node.BranchNode, prefix)
+	case *parse.RangeNode:
+		markBranchVariablesUsed(draftTemplate, &node.BranchNode, prefix)
+	case *parse.WithNode:
+		markBranchVariablesUsed(draftTemplate, &node.BranchNode, prefix)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+		for _, command := range node.Cmds {
+			markVariablesUsed(draftTemplate, command, prefix)
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			markVariablesUsed(draftTemplate, arg, prefix)
+		}
+	case *parse.FieldNode:
+		if len(node.Ident) > len(prefix) && slices.Equal(node.Ident[:len(prefix)], prefix) {
+			// reading the variable through the config records its use
+			_, _ = draftTemplate.Config.GetVariableValue(node.Ident[len(prefix)])
+		}
+	}
+}
+
+func markBranchVariablesUsed(draftTemplate *Template, node *parse.BranchNode, prefix []string) {
+	markVariablesUsed(draftTemplate, node.Pipe, prefix)
+	markVariablesUsed(draftTemplate, node.List, prefix)
+	markVariablesUsed(draftTemplate, node.ElseList, prefix)
+}
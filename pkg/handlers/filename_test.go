@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Azure/draft/pkg/config"
+	"github.com/Azure/draft/pkg/templatewriter/writers"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFileNameTemplate(writer *writers.FileMapWriter, files fstest.MapFS, variables map[string]string) *Template {
+	draftConfig := &config.DraftConfig{
+		TemplateName:        "filenames",
+		Versions:            []string{"0.0.1"},
+		FileNameOverrideMap: map[string]string{"workflow.yaml": ".github/workflows/deploy-{{ .APPNAME }}.yaml"},
+	}
+	for _, name := range []string{"APPNAME", "ENVIRONMENT"} {
+		draftConfig.Variables = append(draftConfig.Variables, &config.BuilderVar{Name: name, Value: variables[name]})
+	}
+
+	return &Template{
+		Config:         draftConfig,
+		templateFiles:  files,
+		templateWriter: writer,
+		src:            "src",
+		dest:           "out",
+		version:        "0.0.1",
+	}
+}
+
+func TestGenerateTemplatedFileNames(t *testing.T) {
+	files := fstest.MapFS{
+		"src/workflow.yaml": {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}`)},
+		"src/overlays/{{ .ENVIRONMENT }}/kustomization.yaml": {Data: []byte(`namespace: {{ .Config.GetVariableValue "APPNAME" }}`)},
+		"src/{{ .APPNAME | upper }}.md":                      {Data: []byte(`# app`)},
+		"src/literal-{{`{{`}}name}}.txt":                     {Data: []byte(`literal`)},
+	}
+
+	writer := &writers.FileMapWriter{}
+	devTemplate := newFileNameTemplate(writer, files, map[string]string{"APPNAME": "myapp", "ENVIRONMENT": "dev"})
+	assert.Nil(t, devTemplate.Generate())
+	assert.Empty(t, devTemplate.Config.UnusedVariables())
+	prodTemplate := newFileNameTemplate(writer, files, map[string]string{"APPNAME": "otherapp", "ENVIRONMENT": "prod"})
+	prodTemplate.Overwrite = OverwriteForce
+	assert.Nil(t, prodTemplate.Generate())
+
+	var written []string
+	for file := range writer.FileMap {
+		written = append(written, filepath.ToSlash(file))
+	}
+	assert.ElementsMatch(t, []string{
+		"out/.github/workflows/deploy-myapp.yaml",
+		"out/.github/workflows/deploy-otherapp.yaml",
+		"out/overlays/dev/kustomization.yaml",
+		"out/overlays/prod/kustomization.yaml",
+		"out/MYAPP.md",
+		"out/OTHERAPP.md",
+		"out/literal-{{name}}.txt",
+	}, written)
+	assert.Equal(t, "namespace: otherapp", string(writer.FileMap[filepath.Join("out", "overlays", "prod", "kustomization.yaml")]))
+}
+
+func TestGenerateRejectsInvalidTemplatedFileNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		appName  string
+		wantErr  string
+	}{
+		{
+			name:     "outside destination",
+			fileName: "src/{{ .APPNAME }}/app.yaml",
+			appName:  "../..",
+			wantErr:  `output file name "{{ .APPNAME }}/app.yaml" for src/{{ .APPNAME }}/app.yaml expands to "../../app.yaml", which is outside the destination`,
+		},
+		{
+			name:     "invalid character",
+			fileName: "src/{{ .APPNAME }}.yaml",
+			appName:  "my:app",
+			wantErr:  `output file name "{{ .APPNAME }}.yaml" for src/{{ .APPNAME }}.yaml expands to "my:app.yaml": ':' is not allowed in file names on Windows`,
+		},
+		{
+			name:     "reserved name",
+			fileName: "src/{{ .APPNAME }}.yaml",
+			appName:  "con",
+			wantErr:  `output file name "{{ .APPNAME }}.yaml" for src/{{ .APPNAME }}.yaml expands to "con.yaml": file name "con.yaml" is reserved on Windows`,
+		},
+		{
+			name:     "trailing dot",
+			fileName: "src/{{ .APPNAME }}/app.yaml",
+			appName:  "myapp.",
+			wantErr:  `file name "myapp." ends with a dot or space, which Windows removes`,
+		},
+		{
+			name:     "unknown variable",
+			fileName: "src/{{ .APPNAM }}.yaml",
+			appName:  "myapp",
+			wantErr:  `expanding output file name "{{ .APPNAM }}.yaml" for src/{{ .APPNAM }}.yaml`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &writers.FileMapWriter{}
+			files := fstest.MapFS{tt.fileName: {Data: []byte("content")}}
+			err := newFileNameTemplate(writer, files, map[string]string{"APPNAME": tt.appName, "ENVIRONMENT": "dev"}).Generate()
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Empty(t, writer.FileMap)
+		})
+	}
+}
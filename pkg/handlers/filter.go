@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// WithIncludeGlobs limits generation to the template files matching one of the patterns, see Template.IncludeGlobs
+func WithIncludeGlobs(patterns ...string) TemplateOption {
+	return func(t *Template) {
+		t.IncludeGlobs = patterns
+	}
+}
+
+// WithExcludeGlobs leaves the template files matching one of the patterns out of generation, see Template.ExcludeGlobs
+func WithExcludeGlobs(patterns ...string) TemplateOption {
+	return func(t *Template) {
+		t.ExcludeGlobs = patterns
+	}
+}
+
+// filterFile checks relativePath, a slash separated path relative to the template directory, against ExcludeGlobs and
+// IncludeGlobs. It returns whether the file is generated, why it isn't, and whether it matched an include pattern.
+func (t *Template) filterFile(relativePath string) (bool, string, bool) {
+	matchedInclude := len(t.IncludeGlobs) == 0
+	for _, pattern := range t.IncludeGlobs {
+		if matchGlob(pattern, relativePath) {
+			matchedInclude = true
+			break
+		}
+	}
+
+	for _, pattern := range t.ExcludeGlobs {
+		if matchGlob(pattern, relativePath) {
+			return false, fmt.Sprintf("excluded by pattern %q", pattern), matchedInclude
+		}
+	}
+
+	if !matchedInclude {
+		return false, "not matched by any include pattern", false
+	}
+	return true, "", true
+}
+
+// validateGlobs checks the syntax of IncludeGlobs and ExcludeGlobs
+func (t *Template) validateGlobs() error {
+	for _, pattern := range append(append([]string{}, t.IncludeGlobs...), t.ExcludeGlobs...) {
+		for _, element := range strings.Split(pattern, "/") {
+			if _, err := path.Match(element, ""); err != nil {
+				return fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchGlob reports whether the slash separated name matches pattern. Path elements are matched with path.Match, and
+// an element of ** matches any number of path elements, including none, e.g. manifests/**/*.yaml matches
+// manifests/hpa.yaml and manifests/overlays/dev/hpa.yaml.
+func matchGlob(pattern, name string) bool {
+	return matchGlobElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlobElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/draft/pkg/templatewriter/writers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "workflow.yaml", name: "workflow.yaml", want: true},
+		{pattern: "*.yaml", name: "workflow.yaml", want: true},
+		{pattern: "*.yaml", name: "manifests/service.yaml", want: false},
+		{pattern: "manifests/*", name: "manifests/service.yaml", want: true},
+		{pattern: "manifests/*", name: "manifests/overlays/service.yaml", want: false},
+		{pattern: "manifests/**", name: "manifests/overlays/dev/service.yaml", want: true},
+		{pattern: "manifests/**", name: "manifests", want: true},
+		{pattern: "**/service.yaml", name: "service.yaml", want: true},
+		{pattern: "**/service.yaml", name: "manifests/overlays/service.yaml", want: true},
+		{pattern: "manifests/**/hpa*.yaml", name: "manifests/hpa.yaml", want: true},
+		{pattern: "manifests/**/hpa*.yaml", name: "manifests/overlays/prod/hpa-cpu.yaml", want: true},
+		{pattern: "manifests/**/hpa*.yaml", name: "workflows/hpa.yaml", want: false},
+		{pattern: "**", name: "anything/at/all", want: true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchGlob(tt.pattern, tt.name), "%s matching %s", tt.pattern, tt.name)
+	}
+}
+
+func TestRenderFileFilters(t *testing.T) {
+	tests := []struct {
+		name        string
+		include     []string
+		exclude     []string
+		wantFiles   []string
+		wantSkipped []SkippedFile
+		wantErr     string
+	}{
+		{
+			name:      "exclude wins over include",
+			include:   []string{"manifests/*"},
+			exclude:   []string{"**/configmap.yaml"},
+			wantFiles: []string{filepath.Join("manifests", "service.yaml")},
+			wantSkipped: []SkippedFile{
+				{Path: "dockerfile", Reason: "not matched by any include pattern"},
+				{Path: filepath.Join("manifests", "configmap.yaml"), Reason: `excluded by pattern "**/configmap.yaml"`},
+				{Path: "workflow.yaml", Reason: "not matched by any include pattern"},
+			},
+		},
+		{
+			name:      "overlapping includes",
+			include:   []string{"manifests/**", "*.yaml", "manifests/service.yaml"},
+			wantFiles: []string{filepath.Join("manifests", "configmap.yaml"), filepath.Join("manifests", "service.yaml"), "workflow.yaml"},
+			wantSkipped: []SkippedFile{
+				{Path: "dockerfile", Reason: "not matched by any include pattern"},
+			},
+		},
+		{
+			name:      "overlapping excludes",
+			exclude:   []string{"manifests/*.yaml", "**/service.yaml"},
+			wantFiles: []string{"dockerfile", "workflow.yaml"},
+			wantSkipped: []SkippedFile{
+				{Path: filepath.Join("manifests", "configmap.yaml"), Reason: `excluded by pattern "manifests/*.yaml"`},
+				{Path: filepath.Join("manifests", "service.yaml"), Reason: `excluded by pattern "manifests/*.yaml"`},
+			},
+		},
+		{
+			name:    "everything included is excluded",
+			include: []string{"manifests/*.yaml"},
+			exclude: []string{"manifests/**"},
+			wantSkipped: []SkippedFile{
+				{Path: "dockerfile", Reason: "not matched by any include pattern"},
+				{Path: filepath.Join("manifests", "configmap.yaml"), Reason: `excluded by pattern "manifests/**"`},
+				{Path: filepath.Join("manifests", "service.yaml"), Reason: `excluded by pattern "manifests/**"`},
+				{Path: "workflow.yaml", Reason: "not matched by any include pattern"},
+			},
+		},
+		{
+			name:    "include matching nothing",
+			include: []string{"charts/**", "*.json"},
+			wantErr: "include patterns charts/**, *.json match no files of template file-name-overrides",
+		},
+		{
+			name:    "invalid pattern",
+			exclude: []string{"manifests/[a-"},
+			wantErr: `generating template: invalid file pattern "manifests/[a-": syntax error in pattern`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testTemplate := newFileNameOverrideTemplate(&writers.FileMapWriter{})
+			WithIncludeGlobs(tt.include...)(testTemplate)
+			WithExcludeGlobs(tt.exclude...)(testTemplate)
+
+			files, err := testTemplate.Render()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.Nil(t, err)
+
+			var rendered []string
+			for file := range files {
+				rendered = append(rendered, file)
+			}
+			assert.ElementsMatch(t, tt.wantFiles, rendered)
+			assert.Equal(t, tt.wantSkipped, testTemplate.SkippedFiles())
+		})
+	}
+}
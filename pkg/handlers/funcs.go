@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"strings"
+	tmpl "text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// excludedTemplateFuncs are the sprig functions not available in templates. Some read the environment or the network,
+// the others return a different result on every call, which would make generated files change on each run and keep
+// Update from rebuilding what was generated before.
+var excludedTemplateFuncs = []string{
+	// environment and network
+	"env", "expandenv", "getHostByName",
+	// time
+	"now", "ago",
+	// randomness
+	"randAlphaNum", "randAlpha", "randAscii", "randNumeric", "randBytes", "randInt", "shuffle", "uuidv4",
+	"bcrypt", "htpasswd", "encryptAES", "genPrivateKey", "genCA", "genCAWithKey", "genSelfSignedCert",
+	"genSelfSignedCertWithKey", "genSignedCert", "genSignedCertWithKey",
+}
+
+// TemplateFuncs returns the functions available in template files: the text functions of sprig
+// (https://masterminds.github.io/sprig/), such as upper, default, trimSuffix, replace, b64enc, indent and nindent,
+// without those that have side effects or nondeterministic results, and Helm's toYaml and mustToYaml. The map is a
+// new copy on each call.
+func TemplateFuncs() tmpl.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	for _, name := range excludedTemplateFuncs {
+		delete(funcs, name)
+	}
+
+	funcs["toYaml"] = toYaml
+	funcs["mustToYaml"] = mustToYaml
+	return funcs
+}
+
+// toYaml marshals v to YAML without the final newline, with the keys of maps sorted. Like in Helm, a value that can't
+// be marshalled gives an empty string.
+func toYaml(v any) string {
+	out, err := mustToYaml(v)
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// mustToYaml is toYaml returning marshalling errors, which fail the template execution
+func mustToYaml(v any) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Azure/draft/pkg/config"
+	"github.com/Azure/draft/pkg/templatewriter/writers"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	optional := false
+	testTemplate := &Template{
+		Config: &config.DraftConfig{
+			TemplateName: "funcs",
+			Versions:     []string{"0.0.1"},
+			Variables: []*config.BuilderVar{
+				{Name: "APPNAME", Value: "my-app-service"},
+				{Name: "IMAGE", Required: &optional},
+				{Name: "MODULE", Value: "github.com/Azure/draftSample"},
+				{Name: "VERSION", Value: "1.4.0"},
+			},
+		},
+		templateFiles:  os.DirFS(filepath.Join("testdata", "funcs")),
+		templateWriter: &writers.FileMapWriter{},
+		src:            "template",
+		dest:           ".",
+		version:        "0.0.1",
+	}
+
+	files, err := testTemplate.Render()
+	assert.Nil(t, err)
+	expected, err := os.ReadFile(filepath.Join("testdata", "funcs", "expected.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(files["values.yaml"]))
+}
+
+func TestTemplateFuncsExcludeSideEffects(t *testing.T) {
+	funcs := TemplateFuncs()
+	for _, name := range excludedTemplateFuncs {
+		assert.NotContains(t, funcs, name)
+	}
+
+	// the map is a copy
+	delete(funcs, "upper")
+	assert.Contains(t, TemplateFuncs(), "upper")
+
+	testTemplate := &Template{
+		Config: &config.DraftConfig{TemplateName: "env", Versions: []string{"0.0.1"}},
+		templateFiles: fstest.MapFS{
+			"src/env.yaml": {Data: []byte(`home: {{ env "HOME" }}`)},
+		},
+		templateWriter: &writers.FileMapWriter{},
+		src:            "src",
+		dest:           ".",
+		version:        "0.0.1",
+	}
+	_, err := testTemplate.Render()
+	assert.ErrorContains(t, err, `function "env" not defined`)
+}
+
+func TestTemplateFuncsToYaml(t *testing.T) {
+	annotations := `{"team": "payments", "prometheus.io/scrape": "true", "example.com/limits": {"memory": "1Gi", "cpu": "500m"}}`
+	testTemplate := &Template{
+		Config: &config.DraftConfig{
+			TemplateName: "toyaml",
+			Versions:     []string{"0.0.1"},
+			Variables:    []*config.BuilderVar{{Name: "ANNOTATIONS", Value: annotations}},
+		},
+		templateFiles: fstest.MapFS{
+			"src/deployment.yaml": {Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+  annotations:
+    {{- .Config.GetVariableValue "ANNOTATIONS" | fromJson | toYaml | nindent 4 }}
+spec:
+  template:
+    metadata:
+      annotations:
+{{ .Config.GetVariableValue "ANNOTATIONS" | fromJson | toYaml | indent 8 }}
+`)},
+		},
+		templateWriter: &writers.FileMapWriter{},
+		src:            "src",
+		dest:           ".",
+		version:        "0.0.1",
+	}
+
+	files, err := testTemplate.Render()
+	assert.Nil(t, err)
+
+	wantAnnotations := `
+    example.com/limits:
+      cpu: 500m
+      memory: 1Gi
+    prometheus.io/scrape: "true"
+    team: payments`
+	assert.Contains(t, string(files["deployment.yaml"]), "  annotations:"+wantAnnotations+"\nspec:")
+	assert.Contains(t, string(files["deployment.yaml"]), "      annotations:"+strings.ReplaceAll(wantAnnotations, "\n", "\n    ")+"\n")
+
+	var deployment struct {
+		Metadata struct {
+			Annotations map[string]any `yaml:"annotations"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Annotations map[string]any `yaml:"annotations"`
+				} `yaml:"metadata"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	assert.Nil(t, yaml.Unmarshal(files["deployment.yaml"], &deployment))
+	want := map[string]any{
+		"team":                 "payments",
+		"prometheus.io/scrape": "true",
+		"example.com/limits":   map[string]any{"cpu": "500m", "memory": "1Gi"},
+	}
+	assert.Equal(t, want, deployment.Metadata.Annotations)
+	assert.Equal(t, want, deployment.Spec.Template.Metadata.Annotations)
+}
+
+func TestToYaml(t *testing.T) {
+	assert.Equal(t, "a: 1\nb:\n- x\n- \"y\"\nc:\n  d: true", toYaml(map[string]any{"c": map[string]bool{"d": true}, "b": []string{"x", "y"}, "a": 1}))
+	assert.Equal(t, "hello", toYaml("hello"))
+	assert.Equal(t, "", toYaml(func() {}))
+
+	_, err := mustToYaml(func() {})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultGenerationManifestPath is where the generation manifest is usually written, relative to the destination
+const DefaultGenerationManifestPath = ".draft/manifest.yaml"
+
+// GenerationManifest records what Generate wrote, so the files can later be updated or cleaned up safely. Field names
+// are part of the manifest file format and must stay stable.
+type GenerationManifest struct {
+	// TemplateName is the name of the generated template
+	TemplateName string `yaml:"templateName"`
+	// Version is the generated template version
+	Version string `yaml:"version"`
+	// Variables holds the value of every variable that had one, except sensitive variables
+	Variables map[string]string `yaml:"variables,omitempty"`
+	// Files holds every file written, sorted by path
+	Files []GeneratedFile `yaml:"files"`
+}
+
+// GeneratedFile is a file written by Generate
+type GeneratedFile struct {
+	// Path is the slash-separated path of the file relative to the destination
+	Path string `yaml:"path"`
+	// SHA256 is the hex encoded sha256 hash of the content written
+	SHA256 string `yaml:"sha256"`
+}
+
+// WithGenerationManifest makes Generate write a GenerationManifest to path, relative to the destination, e.g.
+// DefaultGenerationManifestPath. Each generation replaces the manifest at path.
+func WithGenerationManifest(path string) TemplateOption {
+	return func(t *Template) {
+		t.GenerationManifestPath = path
+	}
+}
+
+// LoadGenerationManifest reads a manifest written by Generate from path in fileSys
+func LoadGenerationManifest(fileSys fs.FS, path string) (*GenerationManifest, error) {
+	manifestBytes, err := fs.ReadFile(fileSys, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading generation manifest: %w", err)
+	}
+
+	manifest := &GenerationManifest{}
+	if err := yaml.UnmarshalStrict(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("parsing generation manifest %s: %w", path, err)
+	}
+
+	return manifest, nil
+}
+
+// Clean removes the files listed in the manifest from dest whose content still has the recorded hash. Files changed
+// since they were generated are kept and listed in the returned error, and files that no longer exist are ignored. The
+// removed paths are returned; the manifest file itself is left in place.
+func (m *GenerationManifest) Clean(dest string) ([]string, error) {
+	var removed, modified []string
+	for _, file := range m.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+			return removed, fmt.Errorf("cleaning generated files: %s is not a relative path inside the destination", file.Path)
+		}
+
+		path := filepath.Join(dest, filepath.FromSlash(file.Path))
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("cleaning generated files: %w", err)
+		}
+
+		if contentHash(content) != file.SHA256 {
+			modified = append(modified, path)
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("cleaning generated files: %w", err)
+		}
+		removed = append(removed, path)
+	}
+
+	if len(modified) > 0 {
+		return removed, fmt.Errorf("cleaning generated files: kept files modified since they were generated: %s", strings.Join(modified, ", "))
+	}
+	return removed, nil
+}
+
+// newGenerationManifest records the files in outputFiles, paths relative to the destination, with their content
+func (t *Template) newGenerationManifest(outputFiles []string, files map[string][]byte) *GenerationManifest {
+	manifest := &GenerationManifest{
+		TemplateName: t.Config.TemplateName,
+		Version:      t.version,
+		Variables:    make(map[string]string),
+		Files:        make([]GeneratedFile, 0, len(outputFiles)),
+	}
+
+	for _, variable := range t.Config.Variables {
+		if variable.Value != "" && !variable.Sensitive {
+			manifest.Variables[variable.Name] = variable.Value
+		}
+	}
+
+	for _, outputFile := range outputFiles {
+		manifest.Files = append(manifest.Files, GeneratedFile{
+			Path:   filepath.ToSlash(outputFile),
+			SHA256: contentHash(files[outputFile]),
+		})
+	}
+
+	return manifest
+}
+
+// writeGenerationManifest writes manifest to path, relative to the destination, with the template writer
+func (t *Template) writeGenerationManifest(path string, manifest *GenerationManifest) error {
+	manifestBytes, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("marshalling generation manifest: %w", err)
+	}
+
+	manifestPath := filepath.Join(t.dest, filepath.FromSlash(path))
+	if err := t.templateWriter.EnsureDirectory(filepath.Dir(manifestPath)); err != nil {
+		return fmt.Errorf("writing generation manifest: %w", err)
+	}
+	if err := t.templateWriter.WriteFile(manifestPath, manifestBytes); err != nil {
+		return fmt.Errorf("writing generation manifest: %w", err)
+	}
+
+	return nil
+}
+
+func contentHash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Azure/draft/pkg/config"
+	"github.com/Azure/draft/pkg/templatewriter/writers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerationManifest(t *testing.T) {
+	writer := &writers.FileMapWriter{}
+	testTemplate := newFileNameOverrideTemplate(writer)
+	WithGenerationManifest(DefaultGenerationManifestPath)(testTemplate)
+	testTemplate.Config.SetFileNameOverride("workflow.yaml", ".github/workflows/deploy.yaml")
+	testTemplate.Config.Variables = append(testTemplate.Config.Variables, &config.BuilderVar{Name: "TOKEN", Value: "secret", Sensitive: true})
+	assert.Nil(t, testTemplate.Generate())
+
+	manifestFS := fstest.MapFS{}
+	for path, content := range writer.FileMap {
+		manifestFS[filepath.ToSlash(path)] = &fstest.MapFile{Data: content}
+	}
+	manifest, err := LoadGenerationManifest(manifestFS, "out/.draft/manifest.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, &GenerationManifest{
+		TemplateName: "file-name-overrides",
+		Version:      "0.0.1",
+		Variables:    map[string]string{"APPNAME": "myapp"},
+		Files: []GeneratedFile{
+			{Path: ".github/workflows/deploy.yaml", SHA256: contentHash([]byte("name: myapp"))},
+			{Path: "dockerfile", SHA256: contentHash([]byte("FROM myapp"))},
+			{Path: "manifests/configmap.yaml", SHA256: contentHash([]byte("name: myapp-config"))},
+			{Path: "manifests/service.yaml", SHA256: contentHash([]byte("name: myapp-svc"))},
+		},
+	}, manifest)
+
+	_, err = LoadGenerationManifest(manifestFS, "out/.draft/missing.yaml")
+	assert.ErrorContains(t, err, "reading generation manifest")
+
+	testTemplate = newFileNameOverrideTemplate(writer)
+	testTemplate.GenerationManifestPath = "../manifest.yaml"
+	assert.ErrorContains(t, testTemplate.Generate(), `invalid generation manifest path "../manifest.yaml"`)
+}
+
+func TestGenerationManifestClean(t *testing.T) {
+	dest := t.TempDir()
+	testTemplate := newFileNameOverrideTemplate(&writers.LocalFSWriter{})
+	testTemplate.dest = dest
+	testTemplate.GenerationManifestPath = DefaultGenerationManifestPath
+	assert.Nil(t, testTemplate.Generate())
+
+	manifest, err := LoadGenerationManifest(os.DirFS(dest), DefaultGenerationManifestPath)
+	assert.Nil(t, err)
+	assert.Len(t, manifest.Files, 4)
+
+	// a file edited by the user is kept, and a file already deleted is ignored
+	assert.Nil(t, os.WriteFile(filepath.Join(dest, "workflow.yaml"), []byte("name: edited"), 0644))
+	assert.Nil(t, os.Remove(filepath.Join(dest, "dockerfile")))
+
+	removed, err := manifest.Clean(dest)
+	assert.EqualError(t, err, "cleaning generated files: kept files modified since they were generated: "+filepath.Join(dest, "workflow.yaml"))
+	assert.Equal(t, []string{filepath.Join(dest, "manifests", "configmap.yaml"), filepath.Join(dest, "manifests", "service.yaml")}, removed)
+
+	for path, exists := range map[string]bool{
+		"workflow.yaml":               true,
+		DefaultGenerationManifestPath: true,
+		"manifests/configmap.yaml":    false,
+		"manifests/service.yaml":      false,
+	} {
+		_, err := os.Stat(filepath.Join(dest, filepath.FromSlash(path)))
+		assert.Equal(t, exists, err == nil, path)
+	}
+
+	outside := &GenerationManifest{Files: []GeneratedFile{{Path: "../outside.yaml"}}}
+	_, err = outside.Clean(dest)
+	assert.EqualError(t, err, "cleaning generated files: ../outside.yaml is not a relative path inside the destination")
+}
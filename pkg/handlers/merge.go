@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"slices"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	conflictMarkerCurrent   = "<<<<<<< current\n"
+	conflictMarkerSeparator = "=======\n"
+	conflictMarkerGenerated = ">>>>>>> generated\n"
+)
+
+// mergeHunk replaces the base lines from baseStart up to baseEnd with lines
+type mergeHunk struct {
+	baseStart, baseEnd int
+	lines              []string
+}
+
+// diffHunks returns the changes that turn base into other
+func diffHunks(base, other []string) []mergeHunk {
+	var hunks []mergeHunk
+	for _, opCode := range difflib.NewMatcher(base, other).GetOpCodes() {
+		if opCode.Tag == 'e' {
+			continue
+		}
+		hunks = append(hunks, mergeHunk{baseStart: opCode.I1, baseEnd: opCode.I2, lines: other[opCode.J1:opCode.J2]})
+	}
+	return hunks
+}
+
+// mergeLines merges the changes made to base in current and in generated. Changes that overlap or touch are
+// conflicts, unless both sides made the same change, and are written between conflict markers with the current lines
+// first. mergeLines returns true if there were conflicts.
+func mergeLines(base, current, generated []string) ([]string, bool) {
+	currentHunks := diffHunks(base, current)
+	generatedHunks := diffHunks(base, generated)
+
+	var merged []string
+	conflicted := false
+	position := 0
+	for len(currentHunks) > 0 || len(generatedHunks) > 0 {
+		// start a region at the first remaining hunk and grow it while hunks from either side touch it
+		start, end := regionStart(currentHunks, generatedHunks)
+		var regionCurrent, regionGenerated []mergeHunk
+		for {
+			var grown bool
+			currentHunks, regionCurrent, end, grown = takeTouching(currentHunks, regionCurrent, start, end)
+			var generatedGrown bool
+			generatedHunks, regionGenerated, end, generatedGrown = takeTouching(generatedHunks, regionGenerated, start, end)
+			if !grown && !generatedGrown {
+				break
+			}
+		}
+
+		merged = append(merged, base[position:start]...)
+		position = end
+
+		currentLines := applyHunks(base, regionCurrent, start, end)
+		generatedLines := applyHunks(base, regionGenerated, start, end)
+		switch {
+		case len(regionGenerated) == 0:
+			merged = append(merged, currentLines...)
+		case len(regionCurrent) == 0 || slices.Equal(currentLines, generatedLines):
+			merged = append(merged, generatedLines...)
+		default:
+			conflicted = true
+			merged = append(merged, conflictMarkerCurrent)
+			merged = append(merged, currentLines...)
+			merged = append(merged, conflictMarkerSeparator)
+			merged = append(merged, generatedLines...)
+			merged = append(merged, conflictMarkerGenerated)
+		}
+	}
+
+	return append(merged, base[position:]...), conflicted
+}
+
+// regionStart returns the base range of the first hunk in either list
+func regionStart(current, generated []mergeHunk) (int, int) {
+	switch {
+	case len(current) == 0:
+		return generated[0].baseStart, generated[0].baseEnd
+	case len(generated) == 0 || current[0].baseStart <= generated[0].baseStart:
+		return current[0].baseStart, current[0].baseEnd
+	default:
+		return generated[0].baseStart, generated[0].baseEnd
+	}
+}
+
+// takeTouching moves the hunks at the front of hunks that touch the region from start to end into region, extending
+// the end of the region to cover them
+func takeTouching(hunks, region []mergeHunk, start, end int) ([]mergeHunk, []mergeHunk, int, bool) {
+	grown := false
+	for len(hunks) > 0 && hunks[0].baseStart <= end && hunks[0].baseEnd >= start {
+		end = max(end, hunks[0].baseEnd)
+		region = append(region, hunks[0])
+		hunks = hunks[1:]
+		grown = true
+	}
+	return hunks, region, end, grown
+}
+
+// applyHunks returns the base lines from start up to end with hunks applied
+func applyHunks(base []string, hunks []mergeHunk, start, end int) []string {
+	var lines []string
+	position := start
+	for _, hunk := range hunks {
+		lines = append(lines, base[position:hunk.baseStart]...)
+		lines = append(lines, hunk.lines...)
+		position = hunk.baseEnd
+	}
+	return append(lines, base[position:end]...)
+}
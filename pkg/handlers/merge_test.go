@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeLines(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+	tests := []struct {
+		name           string
+		current        string
+		generated      string
+		want           string
+		wantConflicted bool
+	}{
+		{name: "unchanged", current: base, generated: base, want: base},
+		{name: "current only", current: "a\nb\nc\nd\ne\nf\n", generated: base, want: "a\nb\nc\nd\ne\nf\n"},
+		{name: "generated only", current: base, generated: "a\nB\nc\nd\ne\n", want: "a\nB\nc\nd\ne\n"},
+		{name: "separate changes", current: "a\nb\nc\nc2\nd\ne\n", generated: "A\nb\nc\nd\ne\n", want: "A\nb\nc\nc2\nd\ne\n"},
+		{name: "same change", current: "a\nb\nX\nd\ne\n", generated: "a\nb\nX\nd\ne\n", want: "a\nb\nX\nd\ne\n"},
+		{
+			name:           "conflict",
+			current:        "a\nb\nmine\nd\ne\n",
+			generated:      "a\nb\ntheirs\nd\ne\n",
+			want:           "a\nb\n<<<<<<< current\nmine\n=======\ntheirs\n>>>>>>> generated\nd\ne\n",
+			wantConflicted: true,
+		},
+		{
+			name:           "touching changes conflict",
+			current:        "a\nB\nc\nd\ne\n",
+			generated:      "a\nb\nC\nd\ne\n",
+			want:           "a\n<<<<<<< current\nB\nc\n=======\nb\nC\n>>>>>>> generated\nd\ne\n",
+			wantConflicted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicted := mergeLines(splitLines(base), splitLines(tt.current), splitLines(tt.generated))
+			assert.Equal(t, tt.want, strings.Join(merged, ""))
+			assert.Equal(t, tt.wantConflicted, conflicted)
+		})
+	}
+
+	// without a base every difference conflicts as a whole
+	merged, conflicted := mergeLines(nil, splitLines("a\n"), splitLines("b\n"))
+	assert.True(t, conflicted)
+	assert.Equal(t, "<<<<<<< current\na\n=======\nb\n>>>>>>> generated\n", strings.Join(merged, ""))
+}
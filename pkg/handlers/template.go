@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	tmpl "text/template"
 
@@ -15,30 +18,106 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// OverwritePolicy decides what Generate does with files that already exist at the destination with other content.
+// Existing files are only found by template writers that implement templatewriter.TemplateReader; other writers always
+// write every file.
+type OverwritePolicy int
+
+const (
+	// OverwriteFail makes Generate return an error listing every file that would be overwritten, before writing any file
+	OverwriteFail OverwritePolicy = iota
+	// OverwriteSkip writes only the files that don't exist yet and logs the others as skipped
+	OverwriteSkip
+	// OverwriteForce replaces existing files
+	OverwriteForce
+)
+
+var overwritePolicyNames = map[OverwritePolicy]string{
+	OverwriteFail:  "fail",
+	OverwriteSkip:  "skip",
+	OverwriteForce: "force",
+}
+
+func (p OverwritePolicy) String() string {
+	if name, ok := overwritePolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("OverwritePolicy(%d)", int(p))
+}
+
+// ParseOverwritePolicy returns the policy named fail, skip or force, e.g. from a command line flag
+func ParseOverwritePolicy(name string) (OverwritePolicy, error) {
+	for policy, policyName := range overwritePolicyNames {
+		if strings.EqualFold(name, policyName) {
+			return policy, nil
+		}
+	}
+	return OverwriteFail, fmt.Errorf("invalid overwrite policy %q: expected fail, skip or force", name)
+}
+
+// templateFileSuffixes mark template files so editors don't mistake them for their output type. They are removed from
+// the output file names.
+var templateFileSuffixes = []string{".tmpl", ".tpl"}
+
+// TemplateOption configures a template returned by GetTemplate
+type TemplateOption func(*Template)
+
+// WithOverwrite sets the policy for files that already exist at the destination, OverwriteFail by default
+func WithOverwrite(policy OverwritePolicy) TemplateOption {
+	return func(t *Template) {
+		t.Overwrite = policy
+	}
+}
+
 type Template struct {
 	Config *config.DraftConfig
+	// Idempotent skips writing files whose destination already has the same content, for template writers that
+	// implement templatewriter.TemplateReader
+	Idempotent bool
+	// Overwrite decides what happens to files that already exist at the destination with other content
+	Overwrite OverwritePolicy
+	// GenerationManifestPath is where Generate records the files it writes, relative to the destination. No manifest is
+	// written when it is empty.
+	GenerationManifestPath string
+	// IncludeGlobs limits generation to the template files whose path relative to the template directory matches one
+	// of the patterns, e.g. workflows/*.yaml or manifests/**. Generation fails if no file matches. All files are
+	// generated when it is empty.
+	IncludeGlobs []string
+	// ExcludeGlobs leaves out the template files whose path relative to the template directory matches one of the
+	// patterns, even if they match IncludeGlobs
+	ExcludeGlobs []string
 
 	templateFiles  fs.FS
 	templateWriter templatewriter.TemplateWriter
 	src            string
 	dest           string
 	version        string
+	skippedFiles   []SkippedFile
 }
 
-// GetTemplate returns a template by name, version, and destination
-func GetTemplate(name, version, dest string, templateWriter templatewriter.TemplateWriter) (*Template, error) {
-	template, ok := templateConfigs[strings.ToLower(name)]
+// SkippedFile is a template file left out of the generated files because of IncludeGlobs or ExcludeGlobs, or because
+// a conditionalFiles condition isn't met
+type SkippedFile struct {
+	// Path is where the file would have been written, relative to the destination, before its name is expanded
+	Path string
+	// Reason names the pattern or condition that left the file out
+	Reason string
+}
+
+// GetTemplate returns a template by name, version, and destination. The template is a copy of the registered one, so
+// variables set on it and the parameters passed here don't affect other callers.
+func GetTemplate(name, version, dest string, templateWriter templatewriter.TemplateWriter, opts ...TemplateOption) (*Template, error) {
+	template, ok := registeredTemplate(name)
 	if !ok {
 		return nil, fmt.Errorf("template not found: %s", name)
 	}
 
-	template = template.DeepCopy()
-
 	if version == "" {
 		version = template.Config.DefaultVersion
 		log.Println("version not provided, using default version: ", version)
 	}
 
+	version = config.NormalizeVersion(version)
 	if !IsValidVersion(template.Config.Versions, version) {
 		return nil, fmt.Errorf("invalid version: %s", version)
 	}
@@ -55,21 +134,100 @@ func GetTemplate(name, version, dest string, templateWriter templatewriter.Templ
 	template.dest = dest
 	template.version = version
 	template.templateWriter = templateWriter
+	for _, opt := range opts {
+		opt(template)
+	}
 
 	return template, nil
 }
 
+// Generate renders the template with Render and writes the files with the template writer. Files that already exist
+// with other content are handled according to Overwrite.
 func (t *Template) Generate() error {
+	files, err := t.Render()
+	if err != nil {
+		return err
+	}
+
+	outputFiles := make([]string, 0, len(files))
+	for outputFile := range files {
+		outputFiles = append(outputFiles, outputFile)
+	}
+	slices.Sort(outputFiles)
+
+	existingFiles := make(map[string]bool)
+	if t.Overwrite != OverwriteForce {
+		var conflicts []string
+		for _, outputFile := range outputFiles {
+			outputPath := filepath.Join(t.dest, outputFile)
+			existing, ok, err := readExistingFile(t.templateWriter, outputPath)
+			if err != nil {
+				return fmt.Errorf("generating template: %w", err)
+			}
+			if ok && !bytes.Equal(existing, files[outputFile]) {
+				conflicts = append(conflicts, outputPath)
+			}
+			existingFiles[outputFile] = ok
+		}
+
+		if t.Overwrite == OverwriteFail && len(conflicts) > 0 {
+			return fmt.Errorf("generating template: files already exist with different content, set an overwrite policy to replace or skip them: %s", strings.Join(conflicts, ", "))
+		}
+	}
+
+	for _, skipped := range t.skippedFiles {
+		log.Infof("Skipping %s, %s", filepath.Join(t.dest, skipped.Path), skipped.Reason)
+	}
+
+	writtenFiles := make([]string, 0, len(outputFiles))
+	for _, outputFile := range outputFiles {
+		if t.Overwrite == OverwriteSkip && existingFiles[outputFile] {
+			log.Infof("Skipping %s, it already exists", filepath.Join(t.dest, outputFile))
+			continue
+		}
+
+		if err := writeFile(t, outputFile, files[outputFile]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outputFile, err)
+		}
+		writtenFiles = append(writtenFiles, outputFile)
+	}
+
+	if t.GenerationManifestPath != "" {
+		return t.writeGenerationManifest(t.GenerationManifestPath, t.newGenerationManifest(writtenFiles, files))
+	}
+
+	return nil
+}
+
+// Render validates the template, applies default values and executes the template files like Generate, but returns the
+// content of each file keyed by its path relative to the destination instead of writing it
+func (t *Template) Render() (map[string][]byte, error) {
 	if err := t.validate(); err != nil {
 		log.Printf("template validation failed: %s", err.Error())
-		return fmt.Errorf("generating template: %w", err)
+		return nil, fmt.Errorf("generating template: %w", err)
 	}
 
 	if err := t.Config.ApplyDefaultVariablesForVersion(t.version); err != nil {
-		return fmt.Errorf("create workflow files: %w", err)
+		return nil, fmt.Errorf("create workflow files: %w", err)
+	}
+
+	t.Config.TrackVariableUsage()
+	files, err := renderTemplate(t)
+	if err != nil {
+		return nil, err
 	}
 
-	return generateTemplate(t)
+	if unused := t.Config.UnusedVariables(); len(unused) > 0 {
+		log.Warnf("Variables not used by template %s version %s: %s", t.Config.TemplateName, t.version, strings.Join(unused, ", "))
+	}
+
+	return files, nil
+}
+
+// SkippedFiles returns the files the last Render, Generate, Diff or Update left out because of IncludeGlobs,
+// ExcludeGlobs or the conditionalFiles of the draft config, sorted by path
+func (t *Template) SkippedFiles() []SkippedFile {
+	return slices.Clone(t.skippedFiles)
 }
 
 func (t *Template) validate() error {
@@ -97,17 +255,41 @@ func (t *Template) validate() error {
 		return fmt.Errorf("template version is empty")
 	}
 
-	return nil
+	if err := t.Config.ValidatePromptDisabledDefaults(); err != nil {
+		return err
+	}
+
+	for input, override := range t.Config.FileNameOverrideMap {
+		if err := validateFileNameOverride(input, override); err != nil {
+			return err
+		}
+	}
+
+	if t.GenerationManifestPath != "" && !filepath.IsLocal(filepath.FromSlash(t.GenerationManifestPath)) {
+		return fmt.Errorf("invalid generation manifest path %q: must be a relative path inside the destination", t.GenerationManifestPath)
+	}
+
+	if err := t.validateGlobs(); err != nil {
+		return err
+	}
+
+	return t.validateDestinations()
 }
 
 func (t *Template) DeepCopy() *Template {
 	return &Template{
-		Config:         t.Config.DeepCopy(),
-		templateFiles:  t.templateFiles,
-		templateWriter: t.templateWriter,
-		src:            t.src,
-		dest:           t.dest,
-		version:        t.version,
+		Config:                 t.Config.DeepCopy(),
+		Idempotent:             t.Idempotent,
+		Overwrite:              t.Overwrite,
+		GenerationManifestPath: t.GenerationManifestPath,
+		IncludeGlobs:           slices.Clone(t.IncludeGlobs),
+		ExcludeGlobs:           slices.Clone(t.ExcludeGlobs),
+		templateFiles:          t.templateFiles,
+		templateWriter:         t.templateWriter,
+		src:                    t.src,
+		dest:                   t.dest,
+		version:                t.version,
+		skippedFiles:           slices.Clone(t.skippedFiles),
 	}
 }
 
@@ -140,59 +322,219 @@ func (l *Template) ExtractDefaults(lowerLang string, r reporeader.RepoReader) (m
 	return extractedValues, nil
 }
 
-func generateTemplate(template *Template) error {
-	err := fs.WalkDir(template.templateFiles, template.src, func(path string, d fs.DirEntry, err error) error {
-		if d.IsDir() {
-			return template.templateWriter.EnsureDirectory(strings.Replace(path, template.src, template.dest, 1))
+func renderTemplate(template *Template) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	inputFiles := make(map[string]string)
+	template.skippedFiles = nil
+	variables, err := templateVariables(template)
+	if err != nil {
+		return nil, fmt.Errorf("generating template: %w", err)
+	}
+
+	includedFiles := 0
+
+	err = fs.WalkDir(template.templateFiles, template.src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || config.IsConfigFileName(d.Name()) {
+			return nil
+		}
+
+		outputFile, err := getOutputFileName(template, path)
+		if err != nil {
+			return err
 		}
 
-		if strings.EqualFold(d.Name(), "draft.yaml") {
+		relativePath := sourceRelativePath(template, path)
+		included, reason, matchedInclude := template.filterFile(relativePath)
+		if matchedInclude {
+			includedFiles++
+		}
+		if included {
+			included, reason, err = template.Config.IsFileIncluded(relativePath)
+			if err != nil {
+				return err
+			}
+		}
+		if !included {
+			template.skippedFiles = append(template.skippedFiles, SkippedFile{Path: outputFile, Reason: reason})
 			return nil
 		}
 
-		if err := writeTemplate(template, path); err != nil {
-			return fmt.Errorf("failed to write template %s: %w", path, err)
+		outputFile, err = expandOutputFileName(template, path, outputFile, variables)
+		if err != nil {
+			return err
+		}
+		if inputFile, ok := inputFiles[outputFile]; ok {
+			return fmt.Errorf("template files %s and %s are both written to %s", inputFile, path, outputFile)
 		}
 
+		content, err := renderFile(template, path, variables)
+		if err != nil {
+			return fmt.Errorf("failed to render template %s: %w", path, err)
+		}
+
+		files[outputFile] = content
+		inputFiles[outputFile] = path
 		return nil
 	})
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+	if len(template.IncludeGlobs) > 0 && includedFiles == 0 {
+		return nil, fmt.Errorf("include patterns %s match no files of template %s", strings.Join(template.IncludeGlobs, ", "), template.Config.TemplateName)
+	}
+
+	slices.SortFunc(template.skippedFiles, func(a, b SkippedFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return files, nil
+}
+
+// templateData is the data template files are executed with. Files read variables through .Config.GetVariableValue
+// or from Variables, which holds the validated and transformed value of every variable, e.g. {{ .Variables.APPNAME }}.
+type templateData struct {
+	*Template
+	Variables map[string]any
 }
 
-func writeTemplate(draftTemplate *Template, inputFile string) error {
+// templateDataVariablesField is the field of templateData holding the variables
+var templateDataVariablesField = []string{"Variables"}
+
+// renderFile executes inputFile as a template with variables as its Variables. Files matching the rawFiles patterns
+// of the draft config and binary files, detected by a NUL byte, are returned as they are.
+func renderFile(draftTemplate *Template, inputFile string, variables map[string]any) ([]byte, error) {
 	file, err := fs.ReadFile(draftTemplate.templateFiles, inputFile)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if draftTemplate.Config.IsRawFile(sourceRelativePath(draftTemplate, inputFile)) || bytes.IndexByte(file, 0) >= 0 {
+		return file, nil
 	}
 
 	// Parse the template file, missingkey=error ensures an error will be returned if any variable is missing during template execution.
-	tmpl, err := tmpl.New("template").Option("missingkey=error").Parse(string(file))
+	// Empty delimiters select the default {{ and }}.
+	tmpl, err := tmpl.New("template").
+		Option("missingkey=error").
+		Delims(draftTemplate.Config.LeftDelim, draftTemplate.Config.RightDelim).
+		Funcs(TemplateFuncs()).
+		Parse(string(file))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Execute the template with variableMap
 	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, draftTemplate)
+	err = tmpl.Execute(&buf, templateData{Template: draftTemplate, Variables: variables})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	markVariablesUsed(draftTemplate, tmpl.Tree.Root, templateDataVariablesField)
+
+	return buf.Bytes(), nil
+}
 
-	if err = draftTemplate.templateWriter.WriteFile(getOutputFileName(draftTemplate, inputFile), buf.Bytes()); err != nil {
+// writeFile writes content rendered for outputFile, a path relative to the destination
+func writeFile(draftTemplate *Template, outputFile string, content []byte) error {
+	outputPath := filepath.Join(draftTemplate.dest, outputFile)
+	if err := draftTemplate.templateWriter.EnsureDirectory(filepath.Dir(outputPath)); err != nil {
 		return err
 	}
 
-	return nil
+	if draftTemplate.Idempotent && isUnchanged(draftTemplate.templateWriter, outputPath, content) {
+		log.Infof("%s unchanged", outputPath)
+		return nil
+	}
+
+	return draftTemplate.templateWriter.WriteFile(outputPath, content)
+}
+
+// isUnchanged returns true if templateWriter can read back outputFile and it already holds content
+func isUnchanged(templateWriter templatewriter.TemplateWriter, outputFile string, content []byte) bool {
+	existing, ok, err := readExistingFile(templateWriter, outputFile)
+	return err == nil && ok && bytes.Equal(existing, content)
 }
 
-func getOutputFileName(draftTemplate *Template, inputFile string) string {
-	outputName := filepath.Clean(strings.Replace(inputFile, draftTemplate.src, draftTemplate.dest, 1))
+// readExistingFile reads outputFile back through templateWriter, returning false if it doesn't exist or templateWriter
+// can't read files
+func readExistingFile(templateWriter templatewriter.TemplateWriter, outputFile string) ([]byte, bool, error) {
+	reader, ok := templateWriter.(templatewriter.TemplateReader)
+	if !ok {
+		return nil, false, nil
+	}
+
+	existing, err := reader.ReadFile(outputFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading existing file %s: %w", outputFile, err)
+	}
+	return existing, true, nil
+}
 
-	fileName := filepath.Base(inputFile)
-	if overrideName, ok := draftTemplate.Config.FileNameOverrideMap[fileName]; ok {
-		return strings.Replace(outputName, fileName, overrideName, 1)
+// getOutputFileName returns the path inputFile is written to, relative to the destination, before it is expanded by
+// expandOutputFileName. A .tmpl or .tpl suffix is removed from the name first, e.g. Dockerfile.tmpl is written to
+// Dockerfile. FileNameOverrideMap is then looked up by the path of inputFile relative to the template source without
+// the suffix, e.g. workflows/workflow.yaml, whose override is relative to the destination, and then by its base name,
+// e.g. workflow.yaml, whose override replaces the base name. Either override may contain directories. Finally the
+// destinations of the draft config move the file to another directory.
+func getOutputFileName(draftTemplate *Template, inputFile string) (string, error) {
+	sourcePath := sourceRelativePath(draftTemplate, inputFile)
+	relativePath := trimTemplateSuffix(sourcePath)
+	outputFile := relativePath
+	fileName := path.Base(relativePath)
+	if override, ok := draftTemplate.Config.FileNameOverrideMap[relativePath]; ok {
+		if err := validateFileNameOverride(relativePath, override); err != nil {
+			return "", err
+		}
+		outputFile = override
+	} else if override, ok := draftTemplate.Config.FileNameOverrideMap[fileName]; ok {
+		if err := validateFileNameOverride(fileName, override); err != nil {
+			return "", err
+		}
+		outputFile = path.Join(path.Dir(relativePath), override)
 	}
 
-	return outputName
+	outputFile, err := applyDestination(draftTemplate, sourcePath, path.Clean(outputFile))
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsLocal(filepath.FromSlash(outputFile)) {
+		return "", fmt.Errorf("invalid destination %q for %s: must be a relative path inside the destination", outputFile, inputFile)
+	}
+
+	return filepath.FromSlash(outputFile), nil
+}
+
+// trimTemplateSuffix removes one of templateFileSuffixes from the end of a slash separated path, unless that would leave
+// an empty file name
+func trimTemplateSuffix(name string) string {
+	for _, suffix := range templateFileSuffixes {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok && trimmed != "" && !strings.HasSuffix(trimmed, "/") {
+			return trimmed
+		}
+	}
+	return name
+}
+
+// sourceRelativePath returns the slash separated path of inputFile relative to the template directory. A src of "."
+// is the root of the template files, whose paths are already relative to it, e.g. .github/workflows/deploy.yaml.
+func sourceRelativePath(draftTemplate *Template, inputFile string) string {
+	if draftTemplate.src == "." {
+		return inputFile
+	}
+	relativePath, _ := strings.CutPrefix(inputFile, draftTemplate.src+"/")
+	return relativePath
+}
+
+// validateFileNameOverride rejects overrides that would write outside the template destination
+func validateFileNameOverride(input, override string) error {
+	if !filepath.IsLocal(filepath.FromSlash(override)) {
+		return fmt.Errorf("invalid file name override %q for %s: must be a relative path inside the destination", override, input)
+	}
+	return nil
 }
@@ -1,13 +1,41 @@
 package handlers
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+
+	"github.com/Azure/draft/pkg/config"
+	"github.com/Azure/draft/pkg/templatewriter"
 
 	"github.com/Azure/draft/pkg/templatewriter/writers"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
+// countingWriter counts the writes of every file passed to the FileMapWriter it wraps
+type countingWriter struct {
+	writers.FileMapWriter
+	writes map[string]int
+}
+
+var _ templatewriter.TemplateReader = &countingWriter{}
+
+func (w *countingWriter) WriteFile(path string, data []byte) error {
+	if w.writes == nil {
+		w.writes = map[string]int{}
+	}
+
+	w.writes[path]++
+	return w.FileMapWriter.WriteFile(path, data)
+}
+
 func TestDeepCopy(t *testing.T) {
 	// This will fail on adding a new field to the undelying structs that arent handled in DeepCopy
 	testTemplate, err := GetTemplate("deployment-manifests", "0.0.1", ".", &writers.FileMapWriter{})
@@ -17,3 +45,542 @@ func TestDeepCopy(t *testing.T) {
 
 	assert.True(t, reflect.DeepEqual(deepCopy, testTemplate))
 }
+
+func TestGenerateTracksUnusedVariables(t *testing.T) {
+	testTemplate, err := GetTemplate("deployment-manifests", "0.0.1", ".", &writers.FileMapWriter{})
+	assert.Nil(t, err)
+
+	for name, value := range map[string]string{
+		"APPNAME":        "testapp",
+		"NAMESPACE":      "default",
+		"PORT":           "80",
+		"IMAGENAME":      "testimage",
+		"IMAGETAG":       "latest",
+		"GENERATORLABEL": "draft",
+		"SERVICEPORT":    "80",
+		"ENVVARS":        `{"key1":"value1","key2":"value2"}`,
+		"IMAGETGA":       "v2",
+	} {
+		testTemplate.Config.SetVariable(name, value)
+	}
+
+	assert.Nil(t, testTemplate.Generate())
+	assert.Equal(t, []string{"IMAGETGA"}, testTemplate.Config.UnusedVariables())
+}
+
+func TestGenerateWritesEachFileOnce(t *testing.T) {
+	variables := map[string]string{
+		"APPNAME":        "testapp",
+		"NAMESPACE":      "default",
+		"PORT":           "80",
+		"IMAGENAME":      "testimage",
+		"IMAGETAG":       "latest",
+		"GENERATORLABEL": "draft",
+		"SERVICEPORT":    "80",
+		"ENVVARS":        `{"key1":"value1","key2":"value2"}`,
+	}
+	generate := func(writer *countingWriter, idempotent bool) {
+		testTemplate, err := GetTemplate("deployment-manifests", "0.0.1", ".", writer, WithOverwrite(OverwriteForce))
+		assert.Nil(t, err)
+		testTemplate.Idempotent = idempotent
+		for name, value := range variables {
+			testTemplate.Config.SetVariable(name, value)
+		}
+		assert.Nil(t, testTemplate.Generate())
+	}
+
+	writer := &countingWriter{}
+	generate(writer, false)
+	assert.NotEmpty(t, writer.writes)
+	for path, count := range writer.writes {
+		assert.Equal(t, 1, count, path)
+	}
+
+	writer.writes = nil
+	generate(writer, true)
+	assert.Empty(t, writer.writes)
+
+	generate(writer, false)
+	assert.Len(t, writer.writes, len(writer.FileMap))
+
+	// only files whose content changes are written again
+	previous := map[string]string{}
+	for path, data := range writer.FileMap {
+		previous[path] = string(data)
+	}
+	writer.writes = nil
+	variables["PORT"] = "8080"
+	generate(writer, true)
+	assert.NotEmpty(t, writer.writes)
+	for path, data := range writer.FileMap {
+		assert.Equal(t, previous[path] != string(data), writer.writes[path] == 1, path)
+	}
+}
+
+func newFileNameOverrideTemplate(writer templatewriter.TemplateWriter) *Template {
+	return &Template{
+		Config: &config.DraftConfig{
+			TemplateName: "file-name-overrides",
+			Versions:     []string{"0.0.1"},
+			Variables:    []*config.BuilderVar{{Name: "APPNAME", Value: "myapp"}},
+		},
+		templateFiles: fstest.MapFS{
+			"src/draft.yaml":               {Data: []byte("templateName: file-name-overrides\n")},
+			"src/dockerfile.tmpl":          {Data: []byte(`FROM {{ .Config.GetVariableValue "APPNAME" }}`)},
+			"src/workflow.yaml":            {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}`)},
+			"src/manifests/service.yaml":   {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}-svc`)},
+			"src/manifests/configmap.yaml": {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}-config`)},
+		},
+		templateWriter: writer,
+		src:            "src",
+		dest:           "out",
+		version:        "0.0.1",
+	}
+}
+
+func TestGenerateFileNameOverrides(t *testing.T) {
+	writer := &writers.FileMapWriter{}
+	testTemplate := newFileNameOverrideTemplate(writer)
+	testTemplate.Config.SetFileNameOverride("dockerfile", "Dockerfile")
+	testTemplate.Config.SetFileNameOverride("workflow.yaml", ".github/workflows/deploy.yaml")
+	testTemplate.Config.SetFileNameOverride("manifests/service.yaml", "k8s/service.yaml")
+	testTemplate.Config.SetFileNameOverride("configmap.yaml", "app-config.yaml")
+
+	assert.Nil(t, testTemplate.Generate())
+	assert.Equal(t, map[string][]byte{
+		filepath.Join("out", "Dockerfile"):                          []byte("FROM myapp"),
+		filepath.Join("out", ".github", "workflows", "deploy.yaml"): []byte("name: myapp"),
+		filepath.Join("out", "k8s", "service.yaml"):                 []byte("name: myapp-svc"),
+		filepath.Join("out", "manifests", "app-config.yaml"):        []byte("name: myapp-config"),
+	}, writer.FileMap)
+}
+
+func TestGenerateRejectsFileNameOverridesOutsideDest(t *testing.T) {
+	for _, override := range []string{"../Dockerfile", "/etc/Dockerfile", "build/../../Dockerfile", ""} {
+		writer := &writers.FileMapWriter{}
+		testTemplate := newFileNameOverrideTemplate(writer)
+		testTemplate.Config.SetFileNameOverride("dockerfile", override)
+
+		err := testTemplate.Generate()
+		assert.ErrorContains(t, err, "must be a relative path inside the destination", override)
+		assert.Empty(t, writer.FileMap, override)
+	}
+}
+
+func TestRender(t *testing.T) {
+	dest := t.TempDir()
+	render := func() map[string][]byte {
+		writer := &countingWriter{}
+		testTemplate, err := GetTemplate("deployment-manifests", "0.0.1", dest, &writers.LocalFSWriter{})
+		assert.Nil(t, err)
+		testTemplate.templateWriter = writer
+		for name, value := range map[string]string{
+			"APPNAME":        "testapp",
+			"NAMESPACE":      "default",
+			"PORT":           "80",
+			"IMAGENAME":      "testimage",
+			"IMAGETAG":       "latest",
+			"GENERATORLABEL": "draft",
+			"SERVICEPORT":    "80",
+			"ENVVARS":        `{"key1":"value1","key2":"value2"}`,
+		} {
+			testTemplate.Config.SetVariable(name, value)
+		}
+
+		files, err := testTemplate.Render()
+		assert.Nil(t, err)
+		assert.Empty(t, writer.writes)
+		return files
+	}
+
+	files := render()
+	assert.Contains(t, files, filepath.Join("manifests", "deployment.yaml"))
+	assert.Contains(t, string(files[filepath.Join("manifests", "deployment.yaml")]), "image: testimage:latest")
+	assert.Equal(t, files, render())
+
+	entries, err := os.ReadDir(dest)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	// Generate writes exactly what Render returns
+	writer := &countingWriter{}
+	testTemplate := newFileNameOverrideTemplate(writer)
+	rendered, err := testTemplate.DeepCopy().Render()
+	assert.Nil(t, err)
+	assert.Nil(t, testTemplate.Generate())
+	assert.Len(t, writer.FileMap, len(rendered))
+	for outputFile, content := range rendered {
+		assert.Equal(t, content, writer.FileMap[filepath.Join("out", outputFile)])
+	}
+}
+
+func TestRenderRejectsDuplicateOutputs(t *testing.T) {
+	testTemplate := newFileNameOverrideTemplate(&writers.FileMapWriter{})
+	testTemplate.Config.SetFileNameOverride("workflow.yaml", "Dockerfile")
+	testTemplate.Config.SetFileNameOverride("dockerfile", "Dockerfile")
+
+	_, err := testTemplate.Render()
+	assert.EqualError(t, err, "template files src/dockerfile.tmpl and src/workflow.yaml are both written to Dockerfile")
+}
+
+func TestGenerateOverwritePolicy(t *testing.T) {
+	newWriter := func() *countingWriter {
+		writer := &countingWriter{}
+		writer.FileMap = map[string][]byte{
+			filepath.Join("out", "dockerfile"):                []byte("FROM handwritten"),
+			filepath.Join("out", "workflow.yaml"):             []byte("name: myapp"),
+			filepath.Join("out", "manifests", "service.yaml"): []byte("name: handwritten-svc"),
+		}
+		return writer
+	}
+
+	// the conflicts are all reported before anything is written
+	writer := newWriter()
+	testTemplate := newFileNameOverrideTemplate(writer)
+	err := testTemplate.Generate()
+	assert.EqualError(t, err, "generating template: files already exist with different content, set an overwrite policy to replace or skip them: "+
+		filepath.Join("out", "dockerfile")+", "+filepath.Join("out", "manifests", "service.yaml"))
+	assert.Empty(t, writer.writes)
+	assert.Equal(t, "FROM handwritten", string(writer.FileMap[filepath.Join("out", "dockerfile")]))
+
+	writer = newWriter()
+	testTemplate = newFileNameOverrideTemplate(writer)
+	testTemplate.Overwrite = OverwriteSkip
+	assert.Nil(t, testTemplate.Generate())
+	assert.Equal(t, map[string]int{filepath.Join("out", "manifests", "configmap.yaml"): 1}, writer.writes)
+	assert.Equal(t, "FROM handwritten", string(writer.FileMap[filepath.Join("out", "dockerfile")]))
+
+	writer = newWriter()
+	testTemplate = newFileNameOverrideTemplate(writer)
+	WithOverwrite(OverwriteForce)(testTemplate)
+	assert.Nil(t, testTemplate.Generate())
+	assert.Len(t, writer.writes, 4)
+	assert.Equal(t, "FROM myapp", string(writer.FileMap[filepath.Join("out", "dockerfile")]))
+
+	// files with the same content aren't conflicts
+	writer = &countingWriter{}
+	assert.Nil(t, newFileNameOverrideTemplate(writer).Generate())
+	writer.writes = nil
+	assert.Nil(t, newFileNameOverrideTemplate(writer).Generate())
+	assert.Len(t, writer.writes, 4)
+}
+
+func TestGetTemplateOptions(t *testing.T) {
+	testTemplate, err := GetTemplate("deployment-manifests", "0.0.1", ".", &writers.FileMapWriter{}, WithOverwrite(OverwriteSkip))
+	assert.Nil(t, err)
+	assert.Equal(t, OverwriteSkip, testTemplate.Overwrite)
+	assert.Equal(t, OverwriteSkip, testTemplate.DeepCopy().Overwrite)
+
+	testTemplate, err = GetTemplate("deployment-manifests", "0.0.1", ".", &writers.FileMapWriter{})
+	assert.Nil(t, err)
+	assert.Equal(t, OverwriteFail, testTemplate.Overwrite)
+}
+
+func TestParseOverwritePolicy(t *testing.T) {
+	for name, expected := range map[string]OverwritePolicy{
+		"fail":  OverwriteFail,
+		"skip":  OverwriteSkip,
+		"force": OverwriteForce,
+		"Force": OverwriteForce,
+	} {
+		policy, err := ParseOverwritePolicy(name)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, policy)
+		assert.Equal(t, strings.ToLower(name), policy.String())
+	}
+
+	_, err := ParseOverwritePolicy("always")
+	assert.EqualError(t, err, `invalid overwrite policy "always": expected fail, skip or force`)
+}
+
+func TestRenderWithCustomDelims(t *testing.T) {
+	templateFiles := os.DirFS(filepath.Join("testdata", "delims"))
+	draftConfig, err := config.NewConfigFromFS(templateFiles, "template/draft.yaml")
+	assert.Nil(t, err)
+	draftConfig.SetVariable("APPNAME", "myapp")
+
+	testTemplate := &Template{
+		Config:         draftConfig,
+		templateFiles:  templateFiles,
+		templateWriter: &writers.FileMapWriter{},
+		src:            "template",
+		dest:           ".",
+		version:        "0.0.1",
+	}
+
+	files, err := testTemplate.Render()
+	assert.Nil(t, err)
+	expected, err := os.ReadFile(filepath.Join("testdata", "delims", "expected.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, files["build.yaml"])
+}
+
+func TestRenderConditionalFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		variables   map[string]string
+		wantFiles   []string
+		wantSkipped []SkippedFile
+	}{
+		{
+			name:      "defaults",
+			wantFiles: []string{filepath.Join("manifests", "deployment.yaml")},
+			wantSkipped: []SkippedFile{
+				{Path: filepath.Join("manifests", "hpa.yaml"), Reason: `condition AUTOSCALING notEquals "none" is not met`},
+				{Path: filepath.Join("manifests", "ingress.yaml"), Reason: `condition USEINGRESS equals "true" is not met`},
+			},
+		},
+		{
+			name:      "ingress",
+			variables: map[string]string{"USEINGRESS": "True"},
+			wantFiles: []string{filepath.Join("manifests", "deployment.yaml"), filepath.Join("manifests", "ingress.yaml")},
+			wantSkipped: []SkippedFile{
+				{Path: filepath.Join("manifests", "hpa.yaml"), Reason: `condition AUTOSCALING notEquals "none" is not met`},
+			},
+		},
+		{
+			name:      "ingress and autoscaling",
+			variables: map[string]string{"USEINGRESS": "true", "AUTOSCALING": "cpu"},
+			wantFiles: []string{
+				filepath.Join("manifests", "deployment.yaml"),
+				filepath.Join("manifests", "hpa.yaml"),
+				filepath.Join("manifests", "ingress.yaml"),
+			},
+		},
+	}
+
+	templateFiles := os.DirFS(filepath.Join("testdata", "conditional"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draftConfig, err := config.NewConfigFromFS(templateFiles, "template/draft.yaml")
+			assert.Nil(t, err)
+			draftConfig.SetVariable("APPNAME", "myapp")
+			for name, value := range tt.variables {
+				draftConfig.SetVariable(name, value)
+			}
+
+			writer := &writers.FileMapWriter{}
+			testTemplate := &Template{
+				Config:         draftConfig,
+				templateFiles:  templateFiles,
+				templateWriter: writer,
+				src:            "template",
+				dest:           "out",
+				version:        "0.0.1",
+			}
+
+			assert.Nil(t, testTemplate.Generate())
+			var written []string
+			for file := range writer.FileMap {
+				relativePath, err := filepath.Rel("out", file)
+				assert.Nil(t, err)
+				written = append(written, relativePath)
+			}
+			assert.ElementsMatch(t, tt.wantFiles, written)
+			assert.Equal(t, tt.wantSkipped, testTemplate.SkippedFiles())
+			assert.Empty(t, draftConfig.UnusedVariables())
+		})
+	}
+}
+
+func TestGenerateStripsTemplateSuffixes(t *testing.T) {
+	writer := &writers.FileMapWriter{}
+	testTemplate := &Template{
+		Config: &config.DraftConfig{
+			TemplateName: "suffixes",
+			Versions:     []string{"0.0.1"},
+			Variables:    []*config.BuilderVar{{Name: "APPNAME", Value: "myapp"}},
+		},
+		templateFiles: fstest.MapFS{
+			"src/draft.yaml":                         {Data: []byte("templateName: suffixes\n")},
+			"src/Dockerfile.tmpl":                    {Data: []byte(`FROM {{ .Config.GetVariableValue "APPNAME" }}`)},
+			"src/charts/templates/_helpers.tpl.tmpl": {Data: []byte(`{{ "{{- define \"name\" -}}" }}`)},
+			"src/charts/templates/service.yaml.tpl":  {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}`)},
+			"src/manifests/deployment.yaml.tmpl":     {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}`)},
+			"src/manifests/configmap.yaml.tmpl":      {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}-config`)},
+			"src/workflow.yaml":                      {Data: []byte(`on: push`)},
+			"src/.tmpl":                              {Data: []byte(`no name`)},
+		},
+		templateWriter: writer,
+		src:            "src",
+		dest:           "out",
+		version:        "0.0.1",
+	}
+	// overrides name the files without their suffix
+	testTemplate.Config.SetFileNameOverride("manifests/deployment.yaml", "k8s/deployment.yaml")
+	testTemplate.Config.SetFileNameOverride("configmap.yaml", "app-config.yaml")
+
+	assert.Nil(t, testTemplate.Generate())
+	assert.Equal(t, map[string][]byte{
+		filepath.Join("out", "Dockerfile"):                          []byte("FROM myapp"),
+		filepath.Join("out", "charts", "templates", "_helpers.tpl"): []byte(`{{- define "name" -}}`),
+		filepath.Join("out", "charts", "templates", "service.yaml"): []byte("name: myapp"),
+		filepath.Join("out", "k8s", "deployment.yaml"):              []byte("name: myapp"),
+		filepath.Join("out", "manifests", "app-config.yaml"):        []byte("name: myapp-config"),
+		filepath.Join("out", "workflow.yaml"):                       []byte("on: push"),
+		filepath.Join("out", ".tmpl"):                               []byte("no name"),
+	}, writer.FileMap)
+}
+
+func TestGenerateCopiesRawFiles(t *testing.T) {
+	templateFiles := os.DirFS(filepath.Join("testdata", "raw"))
+	newRawTemplate := func(writer templatewriter.TemplateWriter) *Template {
+		draftConfig, err := config.NewConfigFromFS(templateFiles, "template/draft.yaml")
+		assert.Nil(t, err)
+		draftConfig.SetVariable("APPNAME", "myapp")
+		return &Template{
+			Config:         draftConfig,
+			templateFiles:  templateFiles,
+			templateWriter: writer,
+			src:            "template",
+			dest:           "out",
+			version:        "0.0.1",
+		}
+	}
+
+	writer := &writers.FileMapWriter{}
+	assert.Nil(t, newRawTemplate(writer).Generate())
+
+	png, err := os.ReadFile(filepath.Join("testdata", "raw", "template", "assets", "favicon.png"))
+	assert.Nil(t, err)
+	script, err := os.ReadFile(filepath.Join("testdata", "raw", "template", "scripts", "entrypoint.sh"))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]byte{
+		filepath.Join("out", "deployment.yaml"):                 []byte("name: myapp\n"),
+		filepath.Join("out", "assets", "favicon.png"):           png,
+		filepath.Join("out", "assets", ".keep"):                 nil,
+		filepath.Join("out", "scripts", "docker-entrypoint.sh"): script,
+	}, writer.FileMap)
+
+	// the script isn't a valid template
+	rawTemplate := newRawTemplate(&writers.FileMapWriter{})
+	rawTemplate.Config.RawFiles = nil
+	_, err = rawTemplate.Render()
+	assert.ErrorContains(t, err, "failed to render template template/scripts/entrypoint.sh")
+}
+
+func TestGenerateWithDestinations(t *testing.T) {
+	templateFiles := os.DirFS(filepath.Join("testdata", "destinations"))
+	newDestinationsTemplate := func() *Template {
+		draftConfig, err := config.NewConfigFromFS(templateFiles, "template/draft.yaml")
+		assert.Nil(t, err)
+		draftConfig.SetVariable("APPNAME", "myapp")
+		return &Template{
+			Config:         draftConfig,
+			templateFiles:  templateFiles,
+			templateWriter: &writers.FileMapWriter{},
+			src:            "template",
+			dest:           "out",
+			version:        "0.0.1",
+		}
+	}
+
+	writer := &writers.FileMapWriter{}
+	destinationsTemplate := newDestinationsTemplate()
+	destinationsTemplate.templateWriter = writer
+	assert.Nil(t, destinationsTemplate.Generate())
+	assert.Equal(t, map[string][]byte{
+		filepath.Join("out", ".github", "workflows", "deploy.yaml"):             []byte("name: deploy myapp\n"),
+		filepath.Join("out", "services", "myapp", "Dockerfile"):                 []byte("FROM scratch\n"),
+		filepath.Join("out", "charts", "myapp", "Chart.yaml"):                   []byte("name: myapp\n"),
+		filepath.Join("out", "charts", "myapp", "templates", "deployment.yaml"): []byte("kind: Deployment\n"),
+	}, writer.FileMap)
+
+	// a file name override is placed below the destination
+	overridden := newDestinationsTemplate()
+	overridden.Config.FileNameOverrideMap = map[string]string{"deploy.yaml": "release.yaml"}
+	files, err := overridden.Render()
+	assert.Nil(t, err)
+	assert.Contains(t, files, filepath.Join(".github", "workflows", "release.yaml"))
+
+	conflicting := newDestinationsTemplate()
+	conflicting.Config.Destinations["charts/templates/*.yaml"] = "manifests"
+	err = conflicting.validate()
+	assert.ErrorContains(t, err, "destinations patterns charts/**, charts/templates/*.yaml all match charts/templates/deployment.yaml")
+
+	escaping := newDestinationsTemplate()
+	escaping.Config.Destinations["docker/Dockerfile"] = "../docker"
+	_, err = escaping.Render()
+	assert.ErrorContains(t, err, "must be a relative path inside the destination")
+}
+
+func TestGetTemplateIsolatesCallers(t *testing.T) {
+	const callers = 8
+	fileWriters := make([]*writers.FileMapWriter, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		fileWriters[i] = &writers.FileMapWriter{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			testTemplate, err := GetTemplate("service-manifests", "0.0.1", fmt.Sprintf("out-%d", i), fileWriters[i])
+			if !assert.Nil(t, err) {
+				return
+			}
+			testTemplate.Config.SetVariable("APPNAME", fmt.Sprintf("app-%d", i))
+			testTemplate.Config.SetVariable("PARTOF", fmt.Sprintf("project-%d", i))
+			testTemplate.Config.SetVariable("PORT", fmt.Sprint(8000+i))
+			assert.Nil(t, testTemplate.Generate())
+		}()
+	}
+	wg.Wait()
+
+	for i, writer := range fileWriters {
+		assert.Len(t, writer.FileMap, 1)
+		service := string(writer.FileMap[filepath.Join(fmt.Sprintf("out-%d", i), "service.yaml")])
+		assert.Contains(t, service, fmt.Sprintf("name: app-%d\n", i))
+		assert.Contains(t, service, fmt.Sprintf("part-of: project-%d\n", i))
+		assert.Contains(t, service, fmt.Sprintf("targetPort: %d", 8000+i))
+	}
+
+	// values set by the callers don't leak into the registry
+	registered, err := GetTemplate("service-manifests", "0.0.1", ".", &writers.FileMapWriter{})
+	assert.Nil(t, err)
+	for _, variable := range registered.Config.Variables {
+		assert.Empty(t, variable.Value, "variable %s", variable.Name)
+	}
+
+	GetTemplates()["service-manifests"].Config.Variables[0].Value = "changed"
+	assert.Empty(t, GetTemplates()["service-manifests"].Config.Variables[0].Value)
+}
+
+func TestRenderWithValidatedVariables(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	draftConfig := &config.DraftConfig{
+		TemplateName: "variables",
+		Versions:     []string{"0.0.1"},
+		Variables: []*config.BuilderVar{
+			{Name: "APPNAME", Kind: "lowercase"},
+			{Name: "NAMESPACE"},
+		},
+	}
+	draftConfig.SetVariable("APPNAME", "MyApp")
+	draftConfig.SetVariable("NAMESPACE", "default")
+
+	variablesTemplate := &Template{
+		Config: draftConfig,
+		templateFiles: fstest.MapFS{
+			"src/deployment.yaml": {Data: []byte("name: {{ .Variables.APPNAME }}\nnamespace: {{ .Config.GetVariableValue \"NAMESPACE\" }}\n")},
+		},
+		templateWriter: &writers.FileMapWriter{},
+		src:            "src",
+		dest:           "out",
+		version:        "0.0.1",
+	}
+
+	files, err := variablesTemplate.Render()
+	assert.Nil(t, err)
+	assert.Equal(t, "name: myapp\nnamespace: default\n", string(files["deployment.yaml"]))
+	assert.Empty(t, draftConfig.UnusedVariables())
+	assert.NotContains(t, logs.String(), "not used")
+
+	// misspelled variables fail instead of rendering empty values
+	variablesTemplate.templateFiles = fstest.MapFS{
+		"src/deployment.yaml": {Data: []byte("name: {{ .Variables.APPNAEM }}\n")},
+	}
+	_, err = variablesTemplate.Render()
+	assert.ErrorContains(t, err, `map has no entry for key "APPNAEM"`)
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"path/filepath"
 	"runtime"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/Azure/draft/pkg/config"
 	"github.com/Azure/draft/template"
@@ -14,7 +17,11 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-var templateConfigs map[string]*Template
+var (
+	// templateConfigsMu guards templateConfigs, which RegisterTemplate and UnregisterTemplate change at runtime
+	templateConfigsMu sync.RWMutex
+	templateConfigs   map[string]*Template
+)
 
 type TemplateType string
 
@@ -35,69 +42,229 @@ func init() {
 	}
 }
 
-// GetTemplates returns all templates
+// GetTemplates returns copies of all templates keyed by lowercase name, so changes to them don't affect later calls
 func GetTemplates() map[string]*Template {
-	return templateConfigs
-}
+	templateConfigsMu.RLock()
+	defer templateConfigsMu.RUnlock()
 
-func GetTemplatesByType(templateType TemplateType) map[string]*Template {
-	templates := make(map[string]*Template)
+	templates := make(map[string]*Template, len(templateConfigs))
 	for name, template := range templateConfigs {
-		if template.Config.Type == templateType.String() {
-			templates[name] = template
+		templates[name] = template.DeepCopy()
+	}
+	return templates
+}
+
+// TemplateMetadata describes a registered template without its files
+type TemplateMetadata struct {
+	Name           string
+	DisplayName    string
+	Description    string
+	Type           string
+	Versions       []string
+	DefaultVersion string
+	Labels         map[string]string
+}
+
+// ListTemplates returns the metadata of every registered template, sorted by name
+func ListTemplates() []TemplateMetadata {
+	templateConfigsMu.RLock()
+	templates := make([]TemplateMetadata, 0, len(templateConfigs))
+	for _, template := range templateConfigs {
+		templates = append(templates, TemplateMetadata{
+			Name:           template.Config.TemplateName,
+			DisplayName:    template.Config.DisplayName,
+			Description:    template.Config.Description,
+			Type:           template.Config.Type,
+			Versions:       slices.Clone(template.Config.Versions),
+			DefaultVersion: template.Config.DefaultVersion,
+			Labels:         maps.Clone(template.Config.Labels),
+		})
+	}
+	templateConfigsMu.RUnlock()
+
+	slices.SortFunc(templates, func(a, b TemplateMetadata) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return templates
+}
+
+// GetTemplateConfig returns a copy of the draft config of the template with the given name
+func GetTemplateConfig(name string) (*config.DraftConfig, error) {
+	template, ok := registeredTemplate(name)
+	if !ok {
+		return nil, fmt.Errorf("template not found: %s", name)
+	}
+
+	return template.Config, nil
+}
+
+// registeredTemplate returns a copy of the registered template with the given name
+func registeredTemplate(name string) (*Template, bool) {
+	templateConfigsMu.RLock()
+	defer templateConfigsMu.RUnlock()
+
+	template, ok := templateConfigs[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return template.DeepCopy(), true
+}
+
+// RegisterTemplate adds a template to the ones GetTemplate, ListTemplates and FindTemplates know, e.g. one embedded
+// in another program. Its files are read from the src directory of files, or its root if src is ".", and a copy of
+// draftConfig is used, whose TemplateName is set to name if empty. The config must be valid and the name must not be
+// registered yet, regardless of case.
+func RegisterTemplate(name string, draftConfig *config.DraftConfig, files fs.FS, src string) error {
+	if name == "" {
+		return errors.New("template name is empty")
+	}
+	if draftConfig == nil {
+		return fmt.Errorf("template %s has no draft config", name)
+	}
+	if files == nil {
+		return fmt.Errorf("template %s has no files", name)
+	}
+	if !fs.ValidPath(src) {
+		return fmt.Errorf("invalid source directory %q for template %s", src, name)
+	}
+	if info, err := fs.Stat(files, src); err != nil {
+		return fmt.Errorf("reading source directory of template %s: %w", name, err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("source %s of template %s is not a directory", src, name)
+	}
+
+	draftConfig = draftConfig.DeepCopy()
+	if draftConfig.TemplateName == "" {
+		draftConfig.TemplateName = name
+	}
+	if !strings.EqualFold(draftConfig.TemplateName, name) {
+		return fmt.Errorf("template name %s doesn't match draft config template name %s", name, draftConfig.TemplateName)
+	}
+	if err := draftConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid draft config for template %s: %w", name, err)
+	}
+
+	templateConfigsMu.Lock()
+	defer templateConfigsMu.Unlock()
+
+	if _, ok := templateConfigs[strings.ToLower(name)]; ok {
+		return fmt.Errorf("duplicate template name: %s", name)
+	}
+
+	templateConfigs[strings.ToLower(name)] = &Template{
+		Config:        draftConfig,
+		src:           src,
+		templateFiles: files,
+	}
+	return nil
+}
+
+// UnregisterTemplate removes the template with the given name, if there is one. Templates already returned by
+// GetTemplate keep working.
+func UnregisterTemplate(name string) {
+	templateConfigsMu.Lock()
+	defer templateConfigsMu.Unlock()
+
+	delete(templateConfigs, strings.ToLower(name))
+}
+
+// GetTemplatesByType returns the metadata of the templates of the given type, e.g. workflow, sorted by name. An unknown
+// type returns no templates.
+func GetTemplatesByType(templateType string) []TemplateMetadata {
+	templates := make([]TemplateMetadata, 0)
+	for _, template := range ListTemplates() {
+		if template.Type == templateType {
+			templates = append(templates, template)
 		}
 	}
 	return templates
 }
 
+// FindTemplates returns the metadata of the templates with every label of selector, e.g. {"language": "go"}, sorted
+// by name. An empty selector returns every template.
+func FindTemplates(selector map[string]string) []TemplateMetadata {
+	templates := make([]TemplateMetadata, 0)
+	for _, template := range ListTemplates() {
+		if matchesLabels(template.Labels, selector) {
+			templates = append(templates, template)
+		}
+	}
+	return templates
+}
+
+func matchesLabels(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if label, ok := labels[key]; !ok || label != value {
+			return false
+		}
+	}
+	return true
+}
+
 func IsValidTemplate(templateName string) bool {
+	templateConfigsMu.RLock()
+	defer templateConfigsMu.RUnlock()
+
 	_, ok := templateConfigs[strings.ToLower(templateName)]
 	return ok
 }
 
 func loadTemplates() error {
-	templateConfigs = make(map[string]*Template)
-	return fs.WalkDir(template.Templates, ".", func(path string, d fs.DirEntry, err error) error {
+	templates := make(map[string]*Template)
+	err := fs.WalkDir(template.Templates, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if d.IsDir() {
+		if !d.IsDir() {
 			return nil
 		}
 
-		if !strings.EqualFold(d.Name(), "draft.yaml") {
+		configPath, err := config.FindConfigFile(template.Templates, path)
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil
 		}
+		if err != nil {
+			return err
+		}
 
-		draftConfig, err := config.NewConfigFromFS(template.Templates, path)
+		draftConfig, err := config.NewConfigFromFS(template.Templates, configPath)
 		if err != nil {
 			return err
 		}
 
-		if _, ok := templateConfigs[strings.ToLower(draftConfig.TemplateName)]; ok {
+		if _, ok := templates[strings.ToLower(draftConfig.TemplateName)]; ok {
 			return fmt.Errorf("duplicate template name: %s", draftConfig.TemplateName)
 		}
 
 		newTemplate := &Template{
 			Config:        draftConfig,
-			src:           sanatizeTemplateSrcDir(path),
+			src:           sanatizeTemplateSrcDir(configPath),
 			templateFiles: template.Templates,
 		}
 
-		templateConfigs[strings.ToLower(draftConfig.TemplateName)] = newTemplate
+		templates[strings.ToLower(draftConfig.TemplateName)] = newTemplate
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	templateConfigsMu.Lock()
+	templateConfigs = templates
+	templateConfigsMu.Unlock()
+	return nil
 }
 
-// IsValidVersion checks if a version is valid for a given version range
+// IsValidVersion checks if a version is valid for a given version range. A leading v is ignored, as it is when
+// defaults are applied for a version.
 func IsValidVersion(versions []string, version string) bool {
-	_, err := semver.Parse(version)
+	_, err := semver.Parse(config.NormalizeVersion(version))
 	if err != nil {
 		return false
 	}
 
-	return slices.Contains(versions, version)
+	return config.ContainsVersion(versions, version)
 }
 
 func sanatizeTemplateSrcDir(src string) string {
@@ -1,8 +1,16 @@
 package handlers
 
 import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 
+	"github.com/Azure/draft/pkg/config"
+	"github.com/Azure/draft/pkg/templatewriter/writers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,3 +26,248 @@ func TestLoadTemplates(t *testing.T) {
 	loadedTemplates := GetTemplates()
 	assert.Positive(t, len(loadedTemplates))
 }
+
+func TestListTemplates(t *testing.T) {
+	templates := ListTemplates()
+	assert.Len(t, templates, len(GetTemplates()))
+	assert.True(t, slices.IsSortedFunc(templates, func(a, b TemplateMetadata) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	}))
+
+	for _, template := range templates {
+		assert.NotEmpty(t, template.DisplayName, "template %s has no display name", template.Name)
+		assert.NotEmpty(t, template.Type, "template %s has no type", template.Name)
+		assert.True(t, IsValidVersion(template.Versions, template.DefaultVersion), "template %s has an invalid default version", template.Name)
+	}
+
+	index := slices.IndexFunc(templates, func(template TemplateMetadata) bool { return template.Name == "dockerfile-go" })
+	assert.NotEqual(t, -1, index)
+	assert.Equal(t, TemplateMetadata{
+		Name:           "dockerfile-go",
+		DisplayName:    "Go",
+		Description:    "This template is used to create a Dockerfile for a Go application",
+		Type:           "dockerfile",
+		Versions:       []string{"0.0.1"},
+		DefaultVersion: "0.0.1",
+		Labels:         map[string]string{"language": "go"},
+	}, templates[index])
+}
+
+func TestGetTemplateConfig(t *testing.T) {
+	draftConfig, err := GetTemplateConfig("Dockerfile-Go")
+	assert.Nil(t, err)
+	assert.Equal(t, "dockerfile-go", draftConfig.TemplateName)
+
+	// the copy doesn't change the registered template
+	draftConfig.DisplayName = "changed"
+	draftConfig.Variables[0].Value = "changed"
+	registered := GetTemplates()["dockerfile-go"].Config
+	assert.Equal(t, "Go", registered.DisplayName)
+	assert.NotEqual(t, "changed", registered.Variables[0].Value)
+
+	_, err = GetTemplateConfig("unknown")
+	assert.EqualError(t, err, "template not found: unknown")
+}
+
+func TestIsValidVersion(t *testing.T) {
+	versions := []string{"0.0.1", "0.0.2-beta.1"}
+	assert.True(t, IsValidVersion(versions, "0.0.1"))
+	assert.True(t, IsValidVersion(versions, "v0.0.1"))
+	assert.True(t, IsValidVersion(versions, "0.0.2-beta.1"))
+	assert.False(t, IsValidVersion(versions, "0.0.2"))
+	assert.False(t, IsValidVersion(versions, "v"))
+	assert.False(t, IsValidVersion(versions, ""))
+}
+
+func TestGetTemplatesByType(t *testing.T) {
+	workflows := GetTemplatesByType(TemplateTypeWorkflow.String())
+	assert.Equal(t, []string{"azure-pipeline-kustomize", "azure-pipeline-manifests", "github-workflow-helm", "github-workflow-kustomize", "github-workflow-manifests"}, templateNames(workflows))
+
+	for _, template := range GetTemplatesByType(TemplateTypeDockerfile.String()) {
+		assert.Equal(t, TemplateTypeDockerfile.String(), template.Type)
+	}
+
+	unknown := GetTemplatesByType("unknown")
+	assert.NotNil(t, unknown)
+	assert.Empty(t, unknown)
+}
+
+func TestFindTemplates(t *testing.T) {
+	assert.Equal(t, []string{"dockerfile-go", "dockerfile-gomodule"}, templateNames(FindTemplates(map[string]string{"language": "go"})))
+	assert.Equal(t, []string{"dockerfile-gradle", "dockerfile-gradlew"}, templateNames(FindTemplates(map[string]string{"language": "java", "buildTool": "gradle"})))
+	assert.Equal(t, []string{"azure-pipeline-kustomize", "deployment-kustomize", "github-workflow-kustomize"}, templateNames(FindTemplates(map[string]string{"deploymentType": "kustomize"})))
+	assert.Equal(t, []string{"github-workflow-helm"}, templateNames(FindTemplates(map[string]string{"platform": "github", "deploymentType": "helm"})))
+	assert.Len(t, FindTemplates(nil), len(GetTemplates()))
+
+	noMatch := FindTemplates(map[string]string{"language": "cobol"})
+	assert.NotNil(t, noMatch)
+	assert.Empty(t, noMatch)
+
+	// every builtin template is labelled
+	for _, template := range ListTemplates() {
+		assert.NotEmpty(t, template.Labels, "template %s has no labels", template.Name)
+	}
+}
+
+func templateNames(templates []TemplateMetadata) []string {
+	names := make([]string, 0, len(templates))
+	for _, template := range templates {
+		names = append(names, template.Name)
+	}
+	return names
+}
+
+func newRegisteredConfig(t *testing.T) *config.DraftConfig {
+	draftConfig, err := config.NewConfigFromBytes([]byte(`
+templateName: "custom-service"
+displayName: "Custom Service"
+type: "manifest"
+versions: ["0.0.1", "0.0.2"]
+defaultVersion: "0.0.2"
+labels:
+  platform: custom
+filenameOverrideMap:
+  service.yaml: "custom-service.yaml"
+variables:
+  - name: "APPNAME"
+    type: "string"
+    kind: "kubernetesResourceName"
+    description: "the name of the application"
+  - name: "PORT"
+    type: "int"
+    kind: "port"
+    description: "the port of the service"
+    versions: ">=0.0.2"
+    default:
+      value: 80
+`), true)
+	assert.Nil(t, err)
+	return draftConfig
+}
+
+var registeredFiles = fstest.MapFS{
+	"templates/custom/service.yaml":      {Data: []byte("name: {{ .Config.GetVariableValue \"APPNAME\" }}\n")},
+	"templates/custom/Dockerfile.tmpl":   {Data: []byte("EXPOSE {{ .Config.GetVariableValue \"PORT\" }}\n")},
+	"templates/custom/config/app.yaml":   {Data: []byte("app: {{ .Config.GetVariableValue \"APPNAME\" }}\n")},
+	"templates/custom/config/draft.yaml": {Data: []byte("ignored: true\n")},
+}
+
+func TestRegisterTemplate(t *testing.T) {
+	draftConfig := newRegisteredConfig(t)
+	assert.Nil(t, RegisterTemplate("custom-service", draftConfig, registeredFiles, "templates/custom"))
+	defer UnregisterTemplate("custom-service")
+
+	// the registered config is a copy
+	draftConfig.DisplayName = "changed"
+
+	assert.True(t, IsValidTemplate("Custom-Service"))
+	assert.Equal(t, []string{"custom-service"}, templateNames(FindTemplates(map[string]string{"platform": "custom"})))
+	registeredConfig, err := GetTemplateConfig("custom-service")
+	assert.Nil(t, err)
+	assert.Equal(t, "Custom Service", registeredConfig.DisplayName)
+
+	_, err = GetTemplate("custom-service", "0.0.3", "out", &writers.FileMapWriter{})
+	assert.EqualError(t, err, "invalid version: 0.0.3")
+
+	writer := &writers.FileMapWriter{}
+	customTemplate, err := GetTemplate("custom-service", "", "out", writer)
+	assert.Nil(t, err)
+	customTemplate.Config.SetVariable("APPNAME", "myapp")
+	assert.Nil(t, customTemplate.Generate())
+	assert.Equal(t, map[string][]byte{
+		filepath.Join("out", "custom-service.yaml"): []byte("name: myapp\n"),
+		filepath.Join("out", "Dockerfile"):          []byte("EXPOSE 80\n"),
+		filepath.Join("out", "config", "app.yaml"):  []byte("app: myapp\n"),
+	}, writer.FileMap)
+
+	assert.EqualError(t, RegisterTemplate("other-service", newRegisteredConfig(t), registeredFiles, "templates/custom"), "template name other-service doesn't match draft config template name custom-service")
+	assert.EqualError(t, RegisterTemplate("CUSTOM-SERVICE", newRegisteredConfig(t), registeredFiles, "templates/custom"), "duplicate template name: CUSTOM-SERVICE")
+	assert.EqualError(t, RegisterTemplate("custom-service", newRegisteredConfig(t), registeredFiles, "templates/custom"), "duplicate template name: custom-service")
+
+	UnregisterTemplate("custom-service")
+	assert.False(t, IsValidTemplate("custom-service"))
+	_, err = GetTemplate("custom-service", "", "out", &writers.FileMapWriter{})
+	assert.EqualError(t, err, "template not found: custom-service")
+
+	// a template returned before unregistering keeps working
+	customTemplate.templateWriter = &writers.FileMapWriter{}
+	assert.Nil(t, customTemplate.Generate())
+}
+
+func TestRegisterTemplateAtRoot(t *testing.T) {
+	files := fstest.MapFS{
+		".github/workflows/deploy.yaml": {Data: []byte("name: {{ .Config.GetVariableValue \"APPNAME\" }}\n")},
+		"service.yaml":                  {Data: []byte("port: {{ .Config.GetVariableValue \"PORT\" }}\n")},
+		"draft.yaml":                    {Data: []byte("ignored: true\n")},
+	}
+	draftConfig := newRegisteredConfig(t)
+	skipped := "skipped"
+	draftConfig.ConditionalFiles = map[string]config.FileCondition{
+		".github/workflows/*.yaml": {Variable: "APPNAME", NotEquals: &skipped},
+	}
+	assert.Nil(t, RegisterTemplate("custom-service", draftConfig, files, "."))
+	defer UnregisterTemplate("custom-service")
+
+	writer := &writers.FileMapWriter{}
+	customTemplate, err := GetTemplate("custom-service", "", "out", writer)
+	assert.Nil(t, err)
+	customTemplate.Config.SetVariable("APPNAME", "myapp")
+	assert.Nil(t, customTemplate.Generate())
+	assert.Equal(t, map[string][]byte{
+		filepath.Join("out", ".github", "workflows", "deploy.yaml"): []byte("name: myapp\n"),
+		filepath.Join("out", "custom-service.yaml"):                 []byte("port: 80\n"),
+	}, writer.FileMap)
+
+	// the conditional file is matched by its path including the dot-directory
+	skippedTemplate, err := GetTemplate("custom-service", "", "out", &writers.FileMapWriter{})
+	assert.Nil(t, err)
+	skippedTemplate.Config.SetVariable("APPNAME", skipped)
+	assert.Nil(t, skippedTemplate.Generate())
+	if assert.Len(t, skippedTemplate.SkippedFiles(), 1) {
+		assert.Equal(t, filepath.Join(".github", "workflows", "deploy.yaml"), skippedTemplate.SkippedFiles()[0].Path)
+	}
+}
+
+func TestRegisterTemplateRejectsInvalidTemplates(t *testing.T) {
+	builtinConfig, err := GetTemplateConfig("deployment-manifests")
+	assert.Nil(t, err)
+	assert.EqualError(t, RegisterTemplate("deployment-manifests", builtinConfig, registeredFiles, "templates/custom"), "duplicate template name: deployment-manifests")
+
+	invalidConfig := newRegisteredConfig(t)
+	invalidConfig.DefaultVersion = "0.0.3"
+	assert.EqualError(t, RegisterTemplate("custom-service", invalidConfig, registeredFiles, "templates/custom"), "invalid draft config for template custom-service: defaultVersion 0.0.3 is not one of versions [0.0.1, 0.0.2]")
+
+	assert.EqualError(t, RegisterTemplate("", newRegisteredConfig(t), registeredFiles, "templates/custom"), "template name is empty")
+	assert.EqualError(t, RegisterTemplate("custom-service", nil, registeredFiles, "templates/custom"), "template custom-service has no draft config")
+	assert.EqualError(t, RegisterTemplate("custom-service", newRegisteredConfig(t), nil, "templates/custom"), "template custom-service has no files")
+	assert.EqualError(t, RegisterTemplate("custom-service", newRegisteredConfig(t), registeredFiles, "../custom"), `invalid source directory "../custom" for template custom-service`)
+	assert.EqualError(t, RegisterTemplate("custom-service", newRegisteredConfig(t), registeredFiles, "templates/custom/service.yaml"), "source templates/custom/service.yaml of template custom-service is not a directory")
+	assert.ErrorContains(t, RegisterTemplate("custom-service", newRegisteredConfig(t), registeredFiles, "templates/missing"), "reading source directory of template custom-service")
+
+	assert.False(t, IsValidTemplate("custom-service"))
+}
+
+func TestRegisterTemplateConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("custom-service-%d", i)
+			draftConfig := newRegisteredConfig(t)
+			draftConfig.TemplateName = name
+			assert.Nil(t, RegisterTemplate(name, draftConfig, registeredFiles, "templates/custom"))
+			defer UnregisterTemplate(name)
+
+			assert.NotEmpty(t, ListTemplates())
+			customTemplate, err := GetTemplate(name, "", "out", &writers.FileMapWriter{})
+			if assert.Nil(t, err) {
+				customTemplate.Config.SetVariable("APPNAME", name)
+				assert.Nil(t, customTemplate.Generate())
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, FindTemplates(map[string]string{"platform": "custom"}))
+}
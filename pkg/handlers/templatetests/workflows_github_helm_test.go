@@ -19,7 +19,33 @@ func TestGitHubWorkflowHelmTemplates(t *testing.T) {
 				"WORKFLOWNAME":           "testWorkflow",
 				"BRANCHNAME":             "testBranch",
 				"ACRRESOURCEGROUP":       "testAcrRG",
-				"AZURECONTAINERREGISTRY": "testAcr",
+				"AZURECONTAINERREGISTRY": "testacr",
+				"CONTAINERNAME":          "testContainer",
+				"CLUSTERRESOURCEGROUP":   "testClusterRG",
+				"CLUSTERRESOURCETYPE":    "Microsoft.ContainerService/managedClusters",
+				"CLUSTERNAME":            "testCluster",
+				"KUSTOMIZEPATH":          "./overlays/production",
+				"DEPLOYMENTMANIFESTPATH": "./manifests",
+				"DOCKERFILE":             "./Dockerfile",
+				"BUILDCONTEXTPATH":       "test",
+				"CHARTPATH":              "testPath",
+				"CHARTOVERRIDEPATH":      "testOverridePath",
+				"CHARTOVERRIDES":         "replicas:2",
+				"NAMESPACE":              "default",
+			},
+		},
+		{
+			Name:            "valid helm workflow with acr login server",
+			TemplateName:    "github-workflow-helm",
+			FixturesBaseDir: "../../fixtures/workflows/github/helm",
+			Version:         "0.0.1",
+			Dest:            ".",
+			TemplateWriter:  &writers.FileMapWriter{},
+			VarMap: map[string]string{
+				"WORKFLOWNAME":           "testWorkflow",
+				"BRANCHNAME":             "testBranch",
+				"ACRRESOURCEGROUP":       "testAcrRG",
+				"AZURECONTAINERREGISTRY": "testacr.azurecr.io",
 				"CONTAINERNAME":          "testContainer",
 				"CLUSTERRESOURCEGROUP":   "testClusterRG",
 				"CLUSTERRESOURCETYPE":    "Microsoft.ContainerService/managedClusters",
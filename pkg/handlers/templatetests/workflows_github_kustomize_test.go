@@ -19,7 +19,7 @@ func TestGitHubWorkflowKustomizeTemplates(t *testing.T) {
 				"WORKFLOWNAME":           "testWorkflow",
 				"BRANCHNAME":             "testBranch",
 				"ACRRESOURCEGROUP":       "testAcrRG",
-				"AZURECONTAINERREGISTRY": "testAcr",
+				"AZURECONTAINERREGISTRY": "testacr",
 				"CONTAINERNAME":          "testContainer",
 				"CLUSTERRESOURCEGROUP":   "testClusterRG",
 				"CLUSTERRESOURCETYPE":    "Microsoft.ContainerService/managedClusters",
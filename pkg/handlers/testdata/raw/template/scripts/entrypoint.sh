@@ -0,0 +1,4 @@
+#!/bin/sh
+# start the application, passing {{ and }} through untouched
+set -eu
+exec "${APP_BINARY:-/app/server}" --port "${PORT}" "$@"
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MergeConflictError is returned by Update when changes made to generated files conflict with the newly generated
+// content. Text files are written with conflict markers around each conflict, binary files are left as they are.
+type MergeConflictError struct {
+	// Files are the conflicted paths relative to the destination, sorted
+	Files []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("merge conflicts in %s", strings.Join(e.Files, ", "))
+}
+
+// Update regenerates the template over files recorded in the generation manifest at GenerationManifestPath, or
+// DefaultGenerationManifestPath if it is empty, keeping changes made to them since. destFS reads the destination.
+// Each file is merged three ways: the base is the previously generated content, rebuilt by rendering the recorded
+// version with the recorded variables, the current file is read from destFS and the new content is rendered with the
+// template's current variables. Files that weren't changed since they were generated are replaced, and files deleted
+// since they were generated are not recreated. When the recorded hash shows that the base can't be rebuilt, e.g.
+// because a sensitive variable changed, a changed file conflicts as a whole. The manifest is rewritten with the new
+// content, and a *MergeConflictError lists the files with conflicts.
+func (t *Template) Update(destFS fs.FS) error {
+	manifestPath := t.GenerationManifestPath
+	if manifestPath == "" {
+		manifestPath = DefaultGenerationManifestPath
+	}
+
+	manifest, err := LoadGenerationManifest(destFS, manifestPath)
+	if err != nil {
+		return fmt.Errorf("updating template: %w", err)
+	}
+	if manifest.TemplateName != t.Config.TemplateName {
+		return fmt.Errorf("updating template: generation manifest %s was recorded for template %s, not %s", manifestPath, manifest.TemplateName, t.Config.TemplateName)
+	}
+
+	baseFiles, err := t.renderBase(manifest)
+	if err != nil {
+		log.Warnf("Can't render template %s version %s as it was generated, changed files will conflict as a whole: %s", manifest.TemplateName, manifest.Version, err)
+		baseFiles = map[string][]byte{}
+	}
+
+	files, err := t.Render()
+	if err != nil {
+		return err
+	}
+
+	recordedHashes := make(map[string]string)
+	for _, file := range manifest.Files {
+		recordedHashes[filepath.FromSlash(file.Path)] = file.SHA256
+	}
+
+	outputFiles := make([]string, 0, len(files))
+	for outputFile := range files {
+		outputFiles = append(outputFiles, outputFile)
+	}
+	slices.Sort(outputFiles)
+
+	var conflicts []string
+	for _, outputFile := range outputFiles {
+		conflicted, err := t.updateFile(destFS, outputFile, files[outputFile], baseFiles[outputFile], recordedHashes)
+		if err != nil {
+			return fmt.Errorf("updating %s: %w", outputFile, err)
+		}
+		if conflicted {
+			conflicts = append(conflicts, outputFile)
+		}
+	}
+
+	for outputFile := range recordedHashes {
+		if _, ok := files[outputFile]; !ok {
+			log.Infof("%s is no longer generated by template %s", filepath.Join(t.dest, outputFile), t.Config.TemplateName)
+		}
+	}
+
+	if err := t.writeGenerationManifest(manifestPath, t.newGenerationManifest(outputFiles, files)); err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		return &MergeConflictError{Files: conflicts}
+	}
+	return nil
+}
+
+// renderBase renders the files as they were generated for manifest
+func (t *Template) renderBase(manifest *GenerationManifest) (map[string][]byte, error) {
+	base := t.DeepCopy()
+	base.version = manifest.Version
+	for name, value := range manifest.Variables {
+		base.Config.SetVariable(name, value)
+	}
+
+	return base.Render()
+}
+
+// updateFile merges the generated content of outputFile into the file at the destination, returning true if there
+// were conflicts
+func (t *Template) updateFile(destFS fs.FS, outputFile string, generated, base []byte, recordedHashes map[string]string) (bool, error) {
+	recordedHash, wasGenerated := recordedHashes[outputFile]
+	current, err := fs.ReadFile(destFS, filepath.ToSlash(outputFile))
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && wasGenerated:
+		log.Infof("Not recreating %s, it was deleted since it was generated", filepath.Join(t.dest, outputFile))
+		return false, nil
+	case errors.Is(err, fs.ErrNotExist):
+		return false, writeFile(t, outputFile, generated)
+	case err != nil:
+		return false, err
+	case bytes.Equal(current, generated):
+		return false, nil
+	case wasGenerated && contentHash(current) == recordedHash:
+		return false, writeFile(t, outputFile, generated)
+	}
+
+	if !wasGenerated || contentHash(base) != recordedHash {
+		base = nil
+	}
+
+	if isBinary(base) || isBinary(current) || isBinary(generated) {
+		return true, nil
+	}
+
+	mergedLines, conflicted := mergeLines(splitLines(string(base)), splitLines(string(current)), splitLines(string(generated)))
+	if err := writeFile(t, outputFile, []byte(strings.Join(mergedLines, ""))); err != nil {
+		return false, err
+	}
+	return conflicted, nil
+}
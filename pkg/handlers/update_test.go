@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Azure/draft/pkg/config"
+	"github.com/Azure/draft/pkg/templatewriter/writers"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUpdateTestTemplate(dest, port string) *Template {
+	return &Template{
+		Config: &config.DraftConfig{
+			TemplateName: "update",
+			Versions:     []string{"0.0.1"},
+			Variables: []*config.BuilderVar{
+				{Name: "APPNAME", Value: "myapp"},
+				{Name: "PORT", Value: port},
+			},
+		},
+		templateFiles: fstest.MapFS{
+			"src/deployment.yaml": {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}
+replicas: 1
+env:
+  - LOG_LEVEL=info
+resources: {}
+port: {{ .Config.GetVariableValue "PORT" }}
+`)},
+			"src/service.yaml": {Data: []byte(`name: {{ .Config.GetVariableValue "APPNAME" }}-svc
+port: {{ .Config.GetVariableValue "PORT" }}
+`)},
+		},
+		templateWriter:         &writers.LocalFSWriter{},
+		src:                    "src",
+		dest:                   dest,
+		version:                "0.0.1",
+		GenerationManifestPath: DefaultGenerationManifestPath,
+	}
+}
+
+func readDestFile(t *testing.T, dest, path string) string {
+	content, err := os.ReadFile(filepath.Join(dest, path))
+	assert.Nil(t, err)
+	return string(content)
+}
+
+func TestUpdateKeepsUserChanges(t *testing.T) {
+	dest := t.TempDir()
+	assert.Nil(t, newUpdateTestTemplate(dest, "80").Generate())
+
+	// the user adds an environment variable to the generated deployment
+	edited := "name: myapp\nreplicas: 1\nenv:\n  - LOG_LEVEL=info\n  - FEATURE_FLAG=on\nresources: {}\nport: 80\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(dest, "deployment.yaml"), []byte(edited), 0644))
+
+	assert.Nil(t, newUpdateTestTemplate(dest, "8080").Update(os.DirFS(dest)))
+	assert.Equal(t, "name: myapp\nreplicas: 1\nenv:\n  - LOG_LEVEL=info\n  - FEATURE_FLAG=on\nresources: {}\nport: 8080\n", readDestFile(t, dest, "deployment.yaml"))
+	assert.Equal(t, "name: myapp-svc\nport: 8080\n", readDestFile(t, dest, "service.yaml"))
+
+	// the manifest now records the new content as the base
+	manifest, err := LoadGenerationManifest(os.DirFS(dest), DefaultGenerationManifestPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "8080", manifest.Variables["PORT"])
+	assert.Nil(t, newUpdateTestTemplate(dest, "9000").Update(os.DirFS(dest)))
+	assert.Equal(t, "name: myapp\nreplicas: 1\nenv:\n  - LOG_LEVEL=info\n  - FEATURE_FLAG=on\nresources: {}\nport: 9000\n", readDestFile(t, dest, "deployment.yaml"))
+}
+
+func TestUpdateConflict(t *testing.T) {
+	dest := t.TempDir()
+	assert.Nil(t, newUpdateTestTemplate(dest, "80").Generate())
+
+	// the user changes the line the update changes too
+	assert.Nil(t, os.WriteFile(filepath.Join(dest, "service.yaml"), []byte("name: myapp-svc\nport: 443\n"), 0644))
+
+	err := newUpdateTestTemplate(dest, "8080").Update(os.DirFS(dest))
+	var conflictErr *MergeConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, []string{"service.yaml"}, conflictErr.Files)
+	assert.EqualError(t, err, "merge conflicts in service.yaml")
+
+	assert.Equal(t, "name: myapp-svc\n<<<<<<< current\nport: 443\n=======\nport: 8080\n>>>>>>> generated\n", readDestFile(t, dest, "service.yaml"))
+	assert.Equal(t, "name: myapp\nreplicas: 1\nenv:\n  - LOG_LEVEL=info\nresources: {}\nport: 8080\n", readDestFile(t, dest, "deployment.yaml"))
+}
+
+func TestUpdateWithoutManifest(t *testing.T) {
+	dest := t.TempDir()
+	err := newUpdateTestTemplate(dest, "80").Update(os.DirFS(dest))
+	assert.ErrorContains(t, err, "updating template: reading generation manifest")
+
+	other := newUpdateTestTemplate(dest, "80")
+	other.Config.TemplateName = "other"
+	assert.Nil(t, other.Generate())
+	err = newUpdateTestTemplate(dest, "80").Update(os.DirFS(dest))
+	assert.EqualError(t, err, "updating template: generation manifest .draft/manifest.yaml was recorded for template other, not update")
+}
@@ -4,3 +4,9 @@ type TemplateWriter interface {
 	WriteFile(string, []byte) error
 	EnsureDirectory(string) error
 }
+
+// TemplateReader is implemented by template writers that can read back the files at their destination, so a file whose
+// content would not change doesn't have to be written again
+type TemplateReader interface {
+	ReadFile(string) ([]byte, error)
+}
@@ -1,5 +1,10 @@
 package writers
 
+import (
+	"fmt"
+	"io/fs"
+)
+
 type FileMapWriter struct {
 	FileMap map[string][]byte
 }
@@ -13,6 +18,15 @@ func (w *FileMapWriter) WriteFile(path string, data []byte) error {
 	return nil
 }
 
+func (w *FileMapWriter) ReadFile(path string) ([]byte, error) {
+	data, ok := w.FileMap[path]
+	if !ok {
+		return nil, fmt.Errorf("read %s: %w", path, fs.ErrNotExist)
+	}
+
+	return data, nil
+}
+
 func (w *FileMapWriter) EnsureDirectory(path string) error {
 	return nil
 }
@@ -18,6 +18,11 @@ func (w *LocalFSWriter) WriteFile(path string, data []byte) error {
 
 	return os.WriteFile(path, data, mode)
 }
+
+func (w *LocalFSWriter) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
 func (w *LocalFSWriter) EnsureDirectory(path string) error {
 	return osutil.EnsureDirectory(path)
 }